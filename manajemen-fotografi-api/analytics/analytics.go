@@ -0,0 +1,176 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+	_ "time/tzdata" // zona waktu laporan tetap bisa dibaca di image tanpa tzdata
+
+	"manajemen-fotografi-api/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DefaultCacheTTL adalah lama hasil laporan disimpan, bisa diganti lewat ANALYTICS_CACHE_TTL
+const DefaultCacheTTL = time.Minute
+
+// ErrUnknownMetric dikembalikan Run untuk nama metrik yang tidak terdaftar
+var ErrUnknownMetric = errors.New("metrik analitik tidak dikenal")
+
+// collection diambil saat dipakai supaya pipeline bisa diuji tanpa koneksi database
+var collection = repositories.New
+
+// Params adalah rentang waktu [From, To) dan zona waktu pengelompokan laporan.
+// PhotographerID membatasi laporan ke satu fotografer; nil berarti seluruh studio.
+type Params struct {
+	From           time.Time
+	To             time.Time
+	Location       *time.Location
+	PhotographerID *primitive.ObjectID
+	Limit          int // jumlah baris untuk laporan peringkat, misal lokasi teratas
+}
+
+// Report adalah hasil satu metrik beserta parameter yang dipakai
+type Report struct {
+	Metric      string      `json:"metric"`
+	From        time.Time   `json:"from"`
+	To          time.Time   `json:"to"`
+	Timezone    string      `json:"timezone"`
+	GeneratedAt time.Time   `json:"generated_at"`
+	Data        interface{} `json:"data"`
+}
+
+// Metric adalah satu laporan: pipeline agregasi pada sebuah collection. Single berarti
+// pipeline menghasilkan satu dokumen ringkasan, bukan daftar baris.
+type Metric struct {
+	Collection string
+	Pipeline   func(p Params) mongo.Pipeline
+	Single     bool
+}
+
+// Names mengembalikan nama semua metrik yang terdaftar, terurut
+func Names() []string {
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Pipeline mengembalikan pipeline agregasi metrik, dipakai untuk pengujian dan debugging
+func Pipeline(name string, p Params) (mongo.Pipeline, error) {
+	m, ok := metrics[name]
+	if !ok {
+		return nil, ErrUnknownMetric
+	}
+	return m.Pipeline(p), nil
+}
+
+// Run menjalankan metrik, memakai hasil cache jika parameter yang sama baru saja diminta
+func Run(ctx context.Context, name string, p Params) (Report, error) {
+	m, ok := metrics[name]
+	if !ok {
+		return Report{}, ErrUnknownMetric
+	}
+
+	key := cacheKey(name, p)
+	if report, ok := cache.get(key); ok {
+		return report, nil
+	}
+
+	cursor, err := collection(m.Collection).Aggregate(ctx, m.Pipeline(p))
+	if err != nil {
+		return Report{}, err
+	}
+	rows := []bson.M{}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return Report{}, err
+	}
+
+	report := Report{
+		Metric:      name,
+		From:        p.From,
+		To:          p.To,
+		Timezone:    p.Location.String(),
+		GeneratedAt: time.Now(),
+		Data:        rows,
+	}
+	if m.Single {
+		report.Data = bson.M{}
+		if len(rows) > 0 {
+			report.Data = rows[0]
+		}
+	}
+
+	cache.set(key, report, cacheTTL())
+	return report, nil
+}
+
+func cacheKey(name string, p Params) string {
+	scope := "all"
+	if p.PhotographerID != nil {
+		scope = p.PhotographerID.Hex()
+	}
+	return fmt.Sprintf("%s|%s|%d|%d|%s|%d", name, scope, p.From.UnixNano(), p.To.UnixNano(), p.Location, p.Limit)
+}
+
+func cacheTTL() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("ANALYTICS_CACHE_TTL")); err == nil && d >= 0 {
+		return d
+	}
+	return DefaultCacheTTL
+}
+
+// ttlCache menyimpan laporan per instance. Dashboard biasanya meminta laporan yang sama
+// berulang kali, jadi TTL pendek cukup untuk menahan beban agregasi ke database.
+type ttlCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	report  Report
+	expires time.Time
+}
+
+var cache = &ttlCache{entries: map[string]cacheEntry{}}
+
+func (c *ttlCache) get(key string) (Report, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || time.Now().After(e.expires) {
+		return Report{}, false
+	}
+	return e.report, true
+}
+
+// set menyimpan laporan dan membuang entri yang sudah kedaluwarsa
+func (c *ttlCache) set(key string, report Report, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for k, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cacheEntry{report: report, expires: now.Add(ttl)}
+}
+
+// ResetCache mengosongkan cache laporan
+func ResetCache() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.entries = map[string]cacheEntry{}
+}
@@ -0,0 +1,224 @@
+package analytics
+
+import (
+	"fmt"
+	"time"
+
+	"manajemen-fotografi-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DefaultLimit adalah jumlah baris laporan peringkat jika Limit tidak diisi
+const DefaultLimit = 10
+
+// msPerDay mengubah selisih tanggal Mongo (milidetik) menjadi hari
+const msPerDay = 24 * 60 * 60 * 1000
+
+// metrics adalah semua laporan yang tersedia. Pendapatan dihitung dari transaksi lunas
+// berdasarkan waktu bayar, laporan booking berdasarkan waktu booking dibuat.
+var metrics = map[string]Metric{
+	"revenue_monthly":         {Collection: "transactions", Pipeline: revenueMonthly},
+	"revenue_by_photographer": {Collection: "transactions", Pipeline: revenueByPhotographer},
+	"booking_status":          {Collection: "bookings", Pipeline: bookingStatus},
+	"conversion":              {Collection: "bookings", Pipeline: conversion, Single: true},
+	"cancellation_rate":       {Collection: "bookings", Pipeline: cancellationRate, Single: true},
+	"lead_time":               {Collection: "bookings", Pipeline: leadTime, Single: true},
+	"top_locations":           {Collection: "bookings", Pipeline: topLocations},
+	"new_clients_weekly":      {Collection: "bookings", Pipeline: newClientsWeekly},
+}
+
+func stage(name string, value interface{}) bson.D {
+	return bson.D{{Key: name, Value: value}}
+}
+
+func between(p Params) bson.M {
+	return bson.M{"$gte": p.From, "$lt": p.To}
+}
+
+// timezone adalah nama zona untuk operator tanggal Mongo. Zona tanpa nama IANA
+// (misal FixedZone) dikirim sebagai offset seperti +07:00.
+func timezone(p Params) string {
+	if _, err := time.LoadLocation(p.Location.String()); err == nil {
+		return p.Location.String()
+	}
+	_, offset := p.From.In(p.Location).Zone()
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	return fmt.Sprintf("%c%02d:%02d", sign, offset/3600, offset%3600/60)
+}
+
+func limit(p Params) int {
+	if p.Limit > 0 {
+		return p.Limit
+	}
+	return DefaultLimit
+}
+
+// bookingMatch memilih booking yang dibuat dalam rentang, untuk satu fotografer jika dibatasi
+func bookingMatch(p Params) bson.D {
+	match := bson.M{"created_at": between(p)}
+	if p.PhotographerID != nil {
+		match["photographer_id"] = *p.PhotographerID
+	}
+	return stage("$match", match)
+}
+
+// paidTransactions memilih transaksi lunas dalam rentang beserta booking-nya. Booking selalu
+// di-join karena fotografer hanya tersimpan di booking.
+func paidTransactions(p Params) mongo.Pipeline {
+	pipeline := mongo.Pipeline{
+		stage("$match", bson.M{"status": models.TransactionStatusPaid, "created_at": between(p)}),
+		stage("$lookup", bson.M{"from": "bookings", "localField": "booking_id", "foreignField": "_id", "as": "booking"}),
+		stage("$unwind", "$booking"),
+	}
+	if p.PhotographerID != nil {
+		pipeline = append(pipeline, stage("$match", bson.M{"booking.photographer_id": *p.PhotographerID}))
+	}
+	return pipeline
+}
+
+func revenueMonthly(p Params) mongo.Pipeline {
+	return append(paidTransactions(p),
+		stage("$group", bson.M{
+			"_id":          bson.M{"$dateToString": bson.M{"format": "%Y-%m", "date": "$created_at", "timezone": timezone(p)}},
+			"revenue":      bson.M{"$sum": "$total"},
+			"transactions": bson.M{"$sum": 1},
+		}),
+		stage("$sort", bson.M{"_id": 1}),
+		stage("$project", bson.M{"_id": 0, "month": "$_id", "revenue": 1, "transactions": 1}),
+	)
+}
+
+func revenueByPhotographer(p Params) mongo.Pipeline {
+	return append(paidTransactions(p),
+		stage("$group", bson.M{
+			"_id":          "$booking.photographer_id",
+			"revenue":      bson.M{"$sum": "$total"},
+			"transactions": bson.M{"$sum": 1},
+		}),
+		stage("$sort", bson.D{{Key: "revenue", Value: -1}, {Key: "_id", Value: 1}}),
+		stage("$limit", limit(p)),
+		stage("$lookup", bson.M{"from": "photographers", "localField": "_id", "foreignField": "_id", "as": "photographer"}),
+		stage("$unwind", bson.M{"path": "$photographer", "preserveNullAndEmptyArrays": true}),
+		stage("$lookup", bson.M{"from": "users", "localField": "photographer.user_id", "foreignField": "_id", "as": "user"}),
+		stage("$project", bson.M{
+			"_id":             0,
+			"photographer_id": "$_id",
+			"name":            bson.M{"$arrayElemAt": bson.A{"$user.name", 0}},
+			"location":        "$photographer.location",
+			"revenue":         1,
+			"transactions":    1,
+		}),
+	)
+}
+
+func bookingStatus(p Params) mongo.Pipeline {
+	return mongo.Pipeline{
+		bookingMatch(p),
+		stage("$group", bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}),
+		stage("$sort", bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}),
+		stage("$project", bson.M{"_id": 0, "status": "$_id", "count": 1}),
+	}
+}
+
+// countIf menghitung dokumen yang status-nya termasuk statuses
+func countIf(statuses ...string) bson.M {
+	return bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$in": bson.A{"$status", statuses}}, 1, 0}}}
+}
+
+// ratio membagi dua field hasil $group, 0 jika penyebut 0
+func ratio(numerator, denominator string) bson.M {
+	return bson.M{"$cond": bson.A{
+		bson.M{"$gt": bson.A{denominator, 0}},
+		bson.M{"$divide": bson.A{numerator, denominator}},
+		0,
+	}}
+}
+
+// conversion adalah porsi booking yang naik dari pending menjadi confirmed (termasuk yang
+// sudah selesai). Booking yang masih pending ikut dihitung agar terlihat berapa yang belum diputuskan.
+func conversion(p Params) mongo.Pipeline {
+	return mongo.Pipeline{
+		bookingMatch(p),
+		stage("$group", bson.M{
+			"_id":       nil,
+			"total":     bson.M{"$sum": 1},
+			"pending":   countIf(models.BookingStatusPending),
+			"converted": countIf(models.BookingStatusConfirmed, models.BookingStatusDone),
+		}),
+		stage("$project", bson.M{"_id": 0, "total": 1, "pending": 1, "converted": 1, "rate": ratio("$converted", "$total")}),
+	}
+}
+
+func cancellationRate(p Params) mongo.Pipeline {
+	return mongo.Pipeline{
+		bookingMatch(p),
+		stage("$group", bson.M{
+			"_id":       nil,
+			"total":     bson.M{"$sum": 1},
+			"cancelled": countIf(models.BookingStatusCancelled),
+			"no_show":   countIf(models.BookingStatusNoShow),
+		}),
+		stage("$project", bson.M{"_id": 0, "total": 1, "cancelled": 1, "no_show": 1, "rate": ratio("$cancelled", "$total")}),
+	}
+}
+
+// leadTime adalah jarak antara booking dibuat dan tanggal pemotretan, dalam hari
+func leadTime(p Params) mongo.Pipeline {
+	days := bson.M{"$divide": bson.A{bson.M{"$subtract": bson.A{"$date", "$created_at"}}, msPerDay}}
+	return mongo.Pipeline{
+		bookingMatch(p),
+		stage("$project", bson.M{"days": days}),
+		stage("$group", bson.M{
+			"_id":      nil,
+			"bookings": bson.M{"$sum": 1},
+			"avg_days": bson.M{"$avg": "$days"},
+			"min_days": bson.M{"$min": "$days"},
+			"max_days": bson.M{"$max": "$days"},
+		}),
+		stage("$project", bson.M{"_id": 0, "bookings": 1, "avg_days": 1, "min_days": 1, "max_days": 1}),
+	}
+}
+
+// topLocations mengelompokkan lokasi tanpa membedakan huruf besar dan spasi di tepi
+func topLocations(p Params) mongo.Pipeline {
+	return mongo.Pipeline{
+		bookingMatch(p),
+		stage("$match", bson.M{"location": bson.M{"$nin": bson.A{nil, ""}}}),
+		stage("$group", bson.M{
+			"_id":      bson.M{"$toLower": bson.M{"$trim": bson.M{"input": "$location"}}},
+			"location": bson.M{"$first": "$location"},
+			"bookings": bson.M{"$sum": 1},
+		}),
+		stage("$sort", bson.D{{Key: "bookings", Value: -1}, {Key: "_id", Value: 1}}),
+		stage("$limit", limit(p)),
+		stage("$project", bson.M{"_id": 0, "location": 1, "bookings": 1}),
+	}
+}
+
+// newClientsWeekly menghitung client per minggu ISO berdasarkan booking pertamanya (di
+// studio, atau di fotografer tersebut jika dibatasi). Client yang mendaftar tanpa pernah
+// booking tidak dihitung.
+func newClientsWeekly(p Params) mongo.Pipeline {
+	// Booking dari client yang sudah di-purge (client_id dianonimkan) tidak dihitung
+	match := bson.M{"client_id": bson.M{"$ne": primitive.NilObjectID}}
+	if p.PhotographerID != nil {
+		match["photographer_id"] = *p.PhotographerID
+	}
+	return mongo.Pipeline{
+		stage("$match", match),
+		stage("$group", bson.M{"_id": "$client_id", "first": bson.M{"$min": "$created_at"}}),
+		stage("$match", bson.M{"first": between(p)}),
+		stage("$group", bson.M{
+			"_id":     bson.M{"$dateToString": bson.M{"format": "%G-W%V", "date": "$first", "timezone": timezone(p)}},
+			"clients": bson.M{"$sum": 1},
+		}),
+		stage("$sort", bson.M{"_id": 1}),
+		stage("$project", bson.M{"_id": 0, "week": "$_id", "clients": 1}),
+	}
+}
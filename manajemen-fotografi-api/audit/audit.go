@@ -0,0 +1,62 @@
+package audit
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"manajemen-fotografi-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Source adalah asal request yang mengubah data, diisi middleware audit
+type Source struct {
+	IP        string
+	RequestID string
+}
+
+type ctxKey struct{}
+
+// WithSource menyimpan asal request ke context agar bisa dicatat repository
+func WithSource(ctx context.Context, source Source) context.Context {
+	return context.WithValue(ctx, ctxKey{}, source)
+}
+
+// SourceFrom mengambil asal request; kosong untuk proses di luar request HTTP
+func SourceFrom(ctx context.Context) Source {
+	if ctx == nil {
+		return Source{}
+	}
+	source, _ := ctx.Value(ctxKey{}).(Source)
+	return source
+}
+
+// Diff membandingkan field top-level dua dokumen, diurutkan berdasarkan nama field.
+// before nil berarti dokumen baru dibuat, after nil berarti dokumen dihapus.
+func Diff(before, after bson.M) []models.AuditChange {
+	fields := map[string]bool{}
+	for k := range before {
+		fields[k] = true
+	}
+	for k := range after {
+		fields[k] = true
+	}
+	delete(fields, "_id")
+
+	names := make([]string, 0, len(fields))
+	for k := range fields {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	changes := []models.AuditChange{}
+	for _, name := range names {
+		b, a := before[name], after[name]
+		if reflect.DeepEqual(b, a) {
+			continue
+		}
+		changes = append(changes, models.AuditChange{Field: name, Before: b, After: a})
+	}
+	return changes
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Kunci data user di session login
+const (
+	SessionUserID   = "user_id"
+	SessionRole     = "role"
+	SessionLanguage = "language" // preferensi bahasa user, kosong jika mengikuti Accept-Language
+)
+
+// Actor adalah user yang sedang login, diisi middleware session dari data session
+type Actor struct {
+	UserID primitive.ObjectID
+	Role   string
+}
+
+type ctxKey struct{}
+
+// WithActor menyimpan user yang login ke context agar bisa dipakai handler dan repository
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, ctxKey{}, actor)
+}
+
+// FromContext mengambil user yang login; ok bernilai false untuk request anonim
+func FromContext(ctx context.Context) (Actor, bool) {
+	if ctx == nil {
+		return Actor{}, false
+	}
+	actor, ok := ctx.Value(ctxKey{}).(Actor)
+	return actor, ok
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
@@ -36,7 +37,8 @@ func ConnectDB() {
 	defer cancel()
 
 	// Create MongoDB client
-	clientOptions := options.Client().ApplyURI(mongoURI)
+	// Monitor otelmongo membuat span untuk setiap command yang dikirim driver
+	clientOptions := options.Client().ApplyURI(mongoURI).SetMonitor(otelmongo.NewMonitor())
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
 		log.Fatal("Failed to connect to MongoDB:", err)
@@ -0,0 +1,215 @@
+// Package coupons menghitung dan mencatat pemakaian kode promo saat checkout. Batas pemakaian
+// total dan per client dicek dan dinaikkan dalam satu update atomik pada dokumen kupon,
+// sehingga checkout bersamaan tidak bisa melewati batas.
+package coupons
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"manajemen-fotografi-api/logging"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Nama collection kupon dan riwayat pemakaiannya
+const (
+	Collection           = "coupons"
+	RedemptionCollection = "coupon_redemptions"
+)
+
+// field adalah nama field body checkout yang dilaporkan saat kupon ditolak
+const field = "coupon_code"
+
+// collection diambil saat dipakai, bukan saat package di-load,
+// supaya aturan kupon bisa diuji tanpa koneksi database
+var collection = repositories.New
+
+// Checkout adalah data pembayaran yang menentukan apakah kupon berlaku
+type Checkout struct {
+	ClientID       primitive.ObjectID
+	PhotographerID primitive.ObjectID
+	BookingID      primitive.ObjectID
+	Subtotal       float64
+	At             time.Time
+}
+
+// Quote adalah hasil perhitungan kupon pada sebuah checkout
+type Quote struct {
+	Code     string  `json:"code"`
+	Subtotal float64 `json:"subtotal"`
+	Discount float64 `json:"discount"`
+	Total    float64 `json:"total"`
+}
+
+// Normalize menyeragamkan kode kupon: tanpa spasi di tepi dan huruf besar
+func Normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Check memastikan kupon bisa dipakai pada checkout, tanpa memeriksa batas pemakaian
+func Check(coupon models.Coupon, checkout Checkout) error {
+	switch {
+	case !coupon.Active:
+		return utils.Invalid(field, "coupon.inactive")
+	case coupon.StartsAt != nil && checkout.At.Before(*coupon.StartsAt):
+		return utils.Invalid(field, "coupon.not_started")
+	case coupon.EndsAt != nil && !checkout.At.Before(*coupon.EndsAt):
+		return utils.Invalid(field, "coupon.expired")
+	case len(coupon.PhotographerIDs) > 0 && !slices.Contains(coupon.PhotographerIDs, checkout.PhotographerID):
+		return utils.Invalid(field, "coupon.not_applicable")
+	case checkout.Subtotal < coupon.MinSpend:
+		return utils.InvalidWith(field, "coupon.min_spend", map[string]string{
+			"field": field,
+			"param": strconv.FormatFloat(coupon.MinSpend, 'f', 0, 64),
+		})
+	}
+	return nil
+}
+
+// Discount menghitung potongan dalam rupiah bulat, tidak pernah melebihi subtotal
+func Discount(coupon models.Coupon, subtotal float64) float64 {
+	discount := coupon.Value
+	if coupon.Type == models.CouponPercent {
+		discount = subtotal * coupon.Value / 100
+		if coupon.MaxDiscount > 0 {
+			discount = min(discount, coupon.MaxDiscount)
+		}
+	}
+	return math.Round(min(discount, subtotal))
+}
+
+// find mengambil kupon berdasarkan kode, kode yang tidak dikenal dilaporkan sebagai input salah
+func find(ctx context.Context, code string) (models.Coupon, error) {
+	var coupon models.Coupon
+	err := collection(Collection).FindOne(ctx, bson.M{"code": Normalize(code)}).Decode(&coupon)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return coupon, utils.Invalid(field, "coupon.not_found")
+	}
+	if err != nil {
+		return coupon, utils.ErrDatabase.Wrap(err)
+	}
+	return coupon, nil
+}
+
+// quote menghitung total setelah potongan
+func quote(coupon models.Coupon, subtotal float64) Quote {
+	discount := Discount(coupon, subtotal)
+	return Quote{Code: coupon.Code, Subtotal: subtotal, Discount: discount, Total: subtotal - discount}
+}
+
+// Preview menghitung potongan tanpa memakai kupon, untuk ditampilkan sebelum membayar.
+// Batas pemakaian ikut dicek tapi bisa berubah sampai kupon benar-benar dipakai.
+func Preview(ctx context.Context, code string, checkout Checkout) (Quote, error) {
+	coupon, err := find(ctx, code)
+	if err != nil {
+		return Quote{}, err
+	}
+	if err := Check(coupon, checkout); err != nil {
+		return Quote{}, err
+	}
+	if err := checkLimits(coupon, checkout.ClientID); err != nil {
+		return Quote{}, err
+	}
+	return quote(coupon, checkout.Subtotal), nil
+}
+
+// checkLimits memeriksa batas pemakaian dari dokumen kupon yang sudah dibaca
+func checkLimits(coupon models.Coupon, clientID primitive.ObjectID) error {
+	if coupon.UsageLimit > 0 && coupon.Redeemed >= coupon.UsageLimit {
+		return utils.Invalid(field, "coupon.used_up")
+	}
+	if coupon.PerClientLimit > 0 && coupon.Usage[clientID.Hex()] >= coupon.PerClientLimit {
+		return utils.Invalid(field, "coupon.client_limit")
+	}
+	return nil
+}
+
+// Redeem memakai kupon untuk transaksi trxID. Batas pemakaian total dan per client dicek
+// sekaligus dinaikkan secara atomik; jika transaksi gagal disimpan, panggil Release.
+func Redeem(ctx context.Context, code string, checkout Checkout, trxID primitive.ObjectID) (models.CouponRedemption, Quote, error) {
+	var redemption models.CouponRedemption
+	coupon, err := find(ctx, code)
+	if err != nil {
+		return redemption, Quote{}, err
+	}
+	if err := Check(coupon, checkout); err != nil {
+		return redemption, Quote{}, err
+	}
+
+	usageKey := "usage." + checkout.ClientID.Hex()
+	filter := bson.M{"_id": coupon.ID, "active": true}
+	var limits bson.A
+	if coupon.UsageLimit > 0 {
+		limits = append(limits, bson.M{"redeemed": bson.M{"$lt": coupon.UsageLimit}})
+	}
+	if coupon.PerClientLimit > 0 {
+		limits = append(limits, bson.M{usageKey: bson.M{"$not": bson.M{"$gte": coupon.PerClientLimit}}})
+	}
+	if len(limits) > 0 {
+		filter["$and"] = limits
+	}
+
+	err = collection(Collection).FindOneAndUpdate(ctx, filter,
+		bson.M{"$inc": bson.M{"redeemed": 1, usageKey: 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.Before),
+	).Decode(&coupon)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// Baca ulang untuk memberi tahu batas mana yang habis
+		latest, findErr := find(ctx, code)
+		if findErr != nil {
+			return redemption, Quote{}, findErr
+		}
+		if err := checkLimits(latest, checkout.ClientID); err != nil {
+			return redemption, Quote{}, err
+		}
+		return redemption, Quote{}, utils.Invalid(field, "coupon.inactive")
+	}
+	if err != nil {
+		return redemption, Quote{}, utils.ErrDatabase.Wrap(err)
+	}
+
+	q := quote(coupon, checkout.Subtotal)
+	redemption = models.CouponRedemption{
+		ID:            primitive.NewObjectID(),
+		CouponID:      coupon.ID,
+		Code:          coupon.Code,
+		ClientID:      checkout.ClientID,
+		BookingID:     checkout.BookingID,
+		TransactionID: trxID,
+		Subtotal:      q.Subtotal,
+		Discount:      q.Discount,
+		CreatedAt:     time.Now(),
+	}
+	if _, err := collection(RedemptionCollection).InsertOne(ctx, redemption); err != nil {
+		Release(ctx, redemption)
+		return redemption, q, utils.ErrDatabase.Wrap(err)
+	}
+	return redemption, q, nil
+}
+
+// Release mengembalikan jatah pemakaian kupon, misal karena transaksi gagal disimpan
+func Release(ctx context.Context, redemption models.CouponRedemption) {
+	_, err := collection(Collection).UpdateOne(ctx,
+		bson.M{"_id": redemption.CouponID},
+		bson.M{"$inc": bson.M{"redeemed": -1, "usage." + redemption.ClientID.Hex(): -1}},
+	)
+	if err == nil {
+		_, err = collection(RedemptionCollection).DeleteOne(ctx, bson.M{"_id": redemption.ID})
+	}
+	if err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "gagal mengembalikan jatah kupon",
+			"coupon_id", redemption.CouponID.Hex(), "client_id", redemption.ClientID.Hex(), "error", err)
+	}
+}
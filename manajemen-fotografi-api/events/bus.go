@@ -0,0 +1,243 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"manajemen-fotografi-api/logging"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Jenis event domain
+const (
+	BookingCreated       = "booking.created"
+	BookingStatusChanged = "booking.status_changed"
+	TransactionPaid      = "transaction.paid"
+	GalleryPublished     = "gallery.published"
+	MessageCreated       = "message.created"
+	MessagesRead         = "message.read"
+	WaitlistOffered      = "waitlist.offered"
+)
+
+// Collection menyimpan event untuk replay Last-Event-ID dan change stream antar instance
+const Collection = "events"
+
+// recentSize adalah jumlah ID event terakhir yang diingat untuk membuang event ganda,
+// misal event yang dikirim lokal saat change stream putus lalu datang lagi setelah resume
+const recentSize = 1024
+
+// subscriptionBuffer adalah jumlah event yang boleh antre per subscriber. Subscriber yang
+// tertinggal lebih jauh diputus, lalu client menyambung ulang dan mengejar lewat replay.
+const subscriptionBuffer = 64
+
+// Subscription menerima event yang cocok dengan filter sampai Close dipanggil.
+// C ditutup jika subscriber tertinggal atau bus dihentikan.
+type Subscription struct {
+	C     <-chan models.Event
+	ch    chan models.Event
+	match func(models.Event) bool
+	bus   *Bus
+}
+
+// Close berhenti menerima event
+func (s *Subscription) Close() {
+	s.bus.remove(s)
+}
+
+// Bus adalah event bus di dalam proses. Event disimpan ke store (jika ada) supaya bisa
+// di-replay, lalu dikirim ke subscriber lokal. Jika change stream aktif, pengiriman ke
+// subscriber dilakukan oleh watcher agar semua instance menerima event yang sama.
+type Bus struct {
+	mu     sync.Mutex
+	subs   map[*Subscription]struct{}
+	store  repositories.Collection
+	remote bool // true jika event lokal dikirim lewat change stream
+
+	seen   map[primitive.ObjectID]struct{}
+	recent []primitive.ObjectID // urutan masuk seen, sebagai ring buffer
+	next   int
+}
+
+// NewBus membuat bus dengan store opsional (nil berarti event tidak disimpan)
+func NewBus(store repositories.Collection) *Bus {
+	return &Bus{
+		subs:   map[*Subscription]struct{}{},
+		store:  store,
+		seen:   map[primitive.ObjectID]struct{}{},
+		recent: make([]primitive.ObjectID, 0, recentSize),
+	}
+}
+
+// Default adalah bus yang dipakai handler, store dipasang oleh Start
+var Default = NewBus(nil)
+
+// Publish mengirim event ke Default bus
+func Publish(ctx context.Context, eventType string, recipients []primitive.ObjectID, data interface{}) {
+	Default.Publish(ctx, eventType, recipients, data)
+}
+
+// Publish menyimpan lalu mengirim event. Kegagalan hanya dicatat ke log karena event
+// adalah efek samping; operasi utama yang memicunya sudah berhasil.
+func (b *Bus) Publish(ctx context.Context, eventType string, recipients []primitive.ObjectID, data interface{}) {
+	// Data disimpan dalam bentuk dokumen supaya event live dan hasil replay identik
+	doc, err := toM(data)
+	if err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "gagal membuat event", "type", eventType, "error", err)
+		return
+	}
+	event := models.Event{
+		ID:         primitive.NewObjectID(),
+		Type:       eventType,
+		Recipients: recipients,
+		Data:       doc,
+		At:         time.Now().Truncate(time.Millisecond), // presisi tanggal Mongo
+	}
+	if event.Recipients == nil {
+		event.Recipients = []primitive.ObjectID{}
+	}
+
+	b.mu.Lock()
+	store, remote := b.store, b.remote
+	b.mu.Unlock()
+
+	if store != nil {
+		if _, err := store.InsertOne(context.WithoutCancel(ctx), event); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "gagal menyimpan event", "type", eventType, "error", err)
+			remote = false // watcher tidak akan melihat event ini
+		}
+	}
+	if !remote {
+		b.Dispatch(event)
+	}
+}
+
+// Dispatch mengirim event ke subscriber lokal yang cocok tanpa menyimpannya.
+// Event yang ID-nya baru saja dikirim diabaikan.
+func (b *Bus) Dispatch(event models.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.remember(event.ID) {
+		return
+	}
+	for sub := range b.subs {
+		if !sub.match(event) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			// Subscriber terlalu lambat, diputus supaya bus tidak tertahan
+			delete(b.subs, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+// remember mencatat ID event dan mengembalikan false jika ID sudah pernah dicatat
+func (b *Bus) remember(id primitive.ObjectID) bool {
+	if _, ok := b.seen[id]; ok {
+		return false
+	}
+	if len(b.recent) < recentSize {
+		b.recent = append(b.recent, id)
+	} else {
+		delete(b.seen, b.recent[b.next])
+		b.recent[b.next] = id
+		b.next = (b.next + 1) % recentSize
+	}
+	b.seen[id] = struct{}{}
+	return true
+}
+
+// Subscribe mendaftarkan subscriber untuk event yang lolos match (nil berarti semua event)
+func (b *Bus) Subscribe(match func(models.Event) bool) *Subscription {
+	if match == nil {
+		match = func(models.Event) bool { return true }
+	}
+	ch := make(chan models.Event, subscriptionBuffer)
+	sub := &Subscription{C: ch, ch: ch, match: match, bus: b}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub
+}
+
+// ForUser adalah filter event yang ditujukan ke satu user
+func ForUser(userID primitive.ObjectID) func(models.Event) bool {
+	return func(e models.Event) bool {
+		for _, r := range e.Recipients {
+			if r == userID {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (b *Bus) remove(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subs[sub]; ok {
+		delete(b.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// Close memutus semua subscriber, dipakai saat server berhenti
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subs {
+		delete(b.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// Replay mengambil event untuk user setelah event afterID, urut dari yang terlama.
+// Dipakai saat client menyambung ulang dengan Last-Event-ID.
+func (b *Bus) Replay(ctx context.Context, userID, afterID primitive.ObjectID, limit int64) ([]models.Event, error) {
+	return b.find(ctx, bson.M{"recipients": userID, "_id": bson.M{"$gt": afterID}}, limit)
+}
+
+// Since mengambil semua event setelah afterID, urut dari yang terlama. Dipakai subscriber
+// internal untuk mengejar event yang terlewat setelah tertinggal.
+func (b *Bus) Since(ctx context.Context, afterID primitive.ObjectID, limit int64) ([]models.Event, error) {
+	return b.find(ctx, bson.M{"_id": bson.M{"$gt": afterID}}, limit)
+}
+
+func (b *Bus) find(ctx context.Context, filter bson.M, limit int64) ([]models.Event, error) {
+	b.mu.Lock()
+	store := b.store
+	b.mu.Unlock()
+	if store == nil {
+		return nil, nil
+	}
+
+	cursor, err := store.Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var events []models.Event
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func toM(data interface{}) (bson.M, error) {
+	raw, err := bson.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.M
+	return doc, bson.Unmarshal(raw, &doc)
+}
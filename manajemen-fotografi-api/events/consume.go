@@ -0,0 +1,61 @@
+package events
+
+import (
+	"bytes"
+	"context"
+
+	"manajemen-fotografi-api/logging"
+	"manajemen-fotografi-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// catchUpLimit adalah jumlah maksimal event yang dikejar setelah consumer tertinggal
+const catchUpLimit = 1000
+
+// after melaporkan apakah id lebih baru dari last, dengan urutan _id yang sama seperti Since
+func after(id, last primitive.ObjectID) bool {
+	return bytes.Compare(id[:], last[:]) > 0
+}
+
+// Consume memanggil handle untuk setiap event di Default bus sampai ctx dibatalkan.
+// Dipakai proses internal (webhook, notifikasi) yang tidak boleh kehilangan event: jika
+// tertinggal dan diputus bus, event yang terlewat dikejar dari store sebelum berlangganan lagi.
+// Setiap event diproses paling banyak sekali per consumer, sama seperti StreamEvents.
+func Consume(ctx context.Context, name string, handle func(context.Context, models.Event)) {
+	logger := logging.FromContext(ctx)
+	var last primitive.ObjectID
+
+	for ctx.Err() == nil {
+		sub := Default.Subscribe(nil)
+		// caughtUp berisi event yang sudah diproses saat mengejar. Event yang terbit antara
+		// Subscribe dan Since ikut masuk langganan dan dilewati di sini; pencocokan per ID,
+		// bukan per urutan, karena event live bisa datang tidak urut _id (Publish bersamaan
+		// atau event dari replika lain)
+		caughtUp := map[primitive.ObjectID]bool{}
+		if !last.IsZero() {
+			missed, err := Default.Since(ctx, last, catchUpLimit)
+			if err != nil {
+				logger.ErrorContext(ctx, "gagal mengejar event yang terlewat", "consumer", name, "error", err)
+			}
+			for _, event := range missed {
+				handle(ctx, event)
+				caughtUp[event.ID] = true
+				last = event.ID
+			}
+		}
+		for event := range sub.C {
+			if caughtUp[event.ID] {
+				continue
+			}
+			handle(ctx, event)
+			if after(event.ID, last) {
+				last = event.ID
+			}
+		}
+		sub.Close()
+		if ctx.Err() == nil {
+			logger.WarnContext(ctx, "consumer event tertinggal, menyambung ulang", "consumer", name)
+		}
+	}
+}
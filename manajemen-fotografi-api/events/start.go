@@ -0,0 +1,102 @@
+package events
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"manajemen-fotografi-api/config"
+	"manajemen-fotografi-api/logging"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Batas jeda sebelum change stream disambung ulang setelah putus
+const (
+	minBackoff = time.Second
+	maxBackoff = 30 * time.Second
+)
+
+// Start memasang store event pada Default bus. Jika EVENTS_CHANGE_STREAM=true (butuh
+// replica set), event dikirim ke subscriber lewat change stream sehingga client yang
+// tersambung ke instance manapun menerima event yang sama. Bus ditutup saat ctx dibatalkan.
+func Start(ctx context.Context) {
+	Default.mu.Lock()
+	Default.store = repositories.Unscoped(Collection)
+	Default.mu.Unlock()
+
+	if os.Getenv("EVENTS_CHANGE_STREAM") == "true" {
+		go Default.watch(ctx, config.GetCollection(Collection))
+	}
+
+	go func() {
+		<-ctx.Done()
+		Default.Close()
+	}()
+}
+
+func (b *Bus) setRemote(remote bool) {
+	b.mu.Lock()
+	b.remote = remote
+	b.mu.Unlock()
+}
+
+// watch meneruskan event baru dari change stream ke subscriber lokal. Selama stream putus
+// event dikirim lokal, lalu stream dilanjutkan dari resume token terakhir.
+func (b *Bus) watch(ctx context.Context, coll *mongo.Collection) {
+	logger := logging.FromContext(ctx)
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{"operationType": "insert"}}}}
+	backoff := minBackoff
+	var resumeToken bson.Raw
+
+	for ctx.Err() == nil {
+		opts := options.ChangeStream()
+		if resumeToken != nil {
+			opts.SetResumeAfter(resumeToken)
+		}
+		stream, err := coll.Watch(ctx, pipeline, opts)
+		if err != nil {
+			logger.ErrorContext(ctx, "change stream event gagal dibuka, event dikirim lokal", "error", err)
+			b.setRemote(false)
+			if !sleep(ctx, backoff) {
+				return
+			}
+			backoff = min(backoff*2, maxBackoff)
+			continue
+		}
+
+		b.setRemote(true)
+		backoff = minBackoff
+		for stream.Next(ctx) {
+			var change struct {
+				FullDocument models.Event `bson:"fullDocument"`
+			}
+			if err := stream.Decode(&change); err != nil {
+				logger.ErrorContext(ctx, "event dari change stream tidak bisa dibaca", "error", err)
+				continue
+			}
+			b.Dispatch(change.FullDocument)
+			resumeToken = stream.ResumeToken()
+		}
+		if err := stream.Err(); err != nil && ctx.Err() == nil {
+			logger.ErrorContext(ctx, "change stream event terputus", "error", err)
+		}
+		b.setRemote(false)
+		stream.Close(context.Background())
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"manajemen-fotografi-api/analytics"
+	"manajemen-fotografi-api/query"
+	"manajemen-fotografi-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Batas parameter laporan analitik
+const (
+	defaultAnalyticsRange = 30 * 24 * time.Hour
+	maxAnalyticsDays      = 731
+	defaultAnalyticsZone  = "Asia/Jakarta"
+)
+
+// analyticsParams membaca ?from dan ?to (YYYY-MM-DD atau RFC3339, to inklusif untuk tanggal
+// tanpa jam), ?tz untuk pengelompokan bulan/minggu dan batas hari, serta ?limit.
+// Default 30 hari terakhir sampai menit berjalan dalam WIB.
+func analyticsParams(c *fiber.Ctx) (analytics.Params, error) {
+	var p analytics.Params
+
+	loc, err := time.LoadLocation(c.Query("tz", defaultAnalyticsZone))
+	if err != nil {
+		return p, utils.Invalid("tz", "analytics.invalid_timezone")
+	}
+	p.Location = loc
+
+	// Default dibulatkan ke menit berikutnya supaya permintaan dashboard tanpa ?to dalam
+	// menit yang sama memakai kunci cache yang sama
+	p.To = time.Now().Truncate(time.Minute).Add(time.Minute).In(loc)
+	if v := c.Query("to"); v != "" {
+		if p.To, err = parseAnalyticsTime(v, loc, true); err != nil {
+			return p, utils.Invalid("to", "query.invalid_date")
+		}
+	}
+	p.From = p.To.Add(-defaultAnalyticsRange)
+	if v := c.Query("from"); v != "" {
+		if p.From, err = parseAnalyticsTime(v, loc, false); err != nil {
+			return p, utils.Invalid("from", "query.invalid_date")
+		}
+	}
+	if !p.From.Before(p.To) {
+		return p, utils.Invalid("from", "analytics.invalid_range")
+	}
+	if p.To.Sub(p.From) > maxAnalyticsDays*24*time.Hour {
+		return p, utils.InvalidWith("to", "analytics.range_too_long", map[string]string{
+			"field": "to",
+			"param": strconv.Itoa(maxAnalyticsDays),
+		})
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return p, utils.Invalid("limit", "query.positive_number")
+		}
+		p.Limit = min(limit, query.MaxLimit)
+	}
+	return p, nil
+}
+
+// parseAnalyticsTime membaca tanggal dalam zona laporan. endOfDay menggeser tanggal tanpa
+// jam ke awal hari berikutnya karena batas atas rentang bersifat eksklusif.
+func parseAnalyticsTime(v string, loc *time.Location, endOfDay bool) (time.Time, error) {
+	if len(v) == len(time.DateOnly) {
+		t, err := time.ParseInLocation(time.DateOnly, v, loc)
+		if err == nil && endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, err
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
+// runAnalytics menjalankan metrik dari parameter :metric untuk seluruh studio atau satu fotografer
+func runAnalytics(c *fiber.Ctx, photographerID *primitive.ObjectID) error {
+	p, err := analyticsParams(c)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+	p.PhotographerID = photographerID
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 30*time.Second)
+	defer cancel()
+
+	report, err := analytics.Run(ctx, c.Params("metric"), p)
+	if errors.Is(err, analytics.ErrUnknownMetric) {
+		return utils.Error(c, utils.ErrMetricNotFound)
+	}
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("analytics.fetch_failed", nil).Wrap(err))
+	}
+	return utils.Success(c, fiber.StatusOK, report)
+}
+
+// GetAnalyticsMetrics menampilkan nama laporan yang tersedia
+func GetAnalyticsMetrics(c *fiber.Ctx) error {
+	return utils.Success(c, fiber.StatusOK, analytics.Names())
+}
+
+// GetAnalytics menampilkan laporan untuk seluruh studio (admin)
+func GetAnalytics(c *fiber.Ctx) error {
+	return runAnalytics(c, nil)
+}
+
+// GetPhotographerAnalytics menampilkan laporan untuk satu fotografer, hanya untuk
+// fotografer tersebut dan admin
+func GetPhotographerAnalytics(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	if _, err := ownedPhotographer(ctx, id); err != nil {
+		return utils.Error(c, err)
+	}
+	return runAnalytics(c, &id)
+}
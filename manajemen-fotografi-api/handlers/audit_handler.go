@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/query"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var auditCollection = repositories.Unscoped(repositories.AuditCollection)
+
+// auditListSpec adalah whitelist filter dan sort untuk GET /api/admin/audit-logs
+var auditListSpec = query.Spec{
+	Filters: map[string]query.Filter{
+		"entity":    {Field: "entity", Type: query.String, Op: query.Eq},
+		"entity_id": {Field: "entity_id", Type: query.ObjectID, Op: query.Eq},
+		"actor_id":  {Field: "actor_id", Type: query.ObjectID, Op: query.Eq},
+		"action":    {Field: "action", Type: query.String, Op: query.Eq},
+		"from":      {Field: "at", Type: query.Time, Op: query.Gte},
+		"to":        {Field: "at", Type: query.Time, Op: query.Lte},
+	},
+	Sorts:       []string{"at"},
+	DefaultSort: "-at",
+}
+
+// GetAuditLogs menampilkan riwayat perubahan data, khusus admin
+func GetAuditLogs(c *fiber.Ctx) error {
+	q, err := query.Parse(c, auditListSpec)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	logs, meta, err := query.Find[models.AuditLog](ctx, auditCollection, q)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("audit.fetch_failed", nil).Wrap(err))
+	}
+
+	query.SetLinks(c, q, meta)
+	return utils.SuccessList(c, logs, meta)
+}
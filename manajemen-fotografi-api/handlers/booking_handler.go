@@ -4,103 +4,167 @@ import (
 	"context"
 	"time"
 
-	"manajemen-fotografi-api/config"
+	"manajemen-fotografi-api/events"
+	"manajemen-fotografi-api/integrity"
+	"manajemen-fotografi-api/logging"
 	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/query"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
+	"manajemen-fotografi-api/waitlist"
 
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-var bookingHandlerCollection = config.GetBookingCollection()
-
-func isValidBookingStatus(status string) bool {
-    return status == models.BookingStatusPending ||
-           status == models.BookingStatusConfirmed ||
-           status == models.BookingStatusDone
+var bookingHandlerCollection = repositories.New("bookings")
+
+// bookingListSpec adalah whitelist filter dan sort untuk GET /api/bookings
+var bookingListSpec = query.Spec{
+	Filters: map[string]query.Filter{
+		"status":          {Field: "status", Type: query.String, Op: query.Eq},
+		"client_id":       {Field: "client_id", Type: query.ObjectID, Op: query.Eq},
+		"photographer_id": {Field: "photographer_id", Type: query.ObjectID, Op: query.Eq},
+		"date_from":       {Field: "date", Type: query.Time, Op: query.Gte},
+		"date_to":         {Field: "date", Type: query.Time, Op: query.Lte},
+	},
+	Sorts:       []string{"created_at", "date", "status"},
+	DefaultSort: "-created_at",
 }
 
+// bookingPatch mengatur field yang boleh diubah lewat PATCH /api/bookings/:id
+var bookingPatch = patchResource[models.Booking]{
+	coll:     bookingHandlerCollection,
+	fields:   []string{"client_id", "photographer_id", "date", "location", "status", "note"},
+	notFound: utils.ErrBookingNotFound,
+	failed:   utils.ErrDatabase.WithKey("booking.update_failed", nil),
+	touch:    func(b *models.Booking) { b.UpdatedAt = time.Now() },
+	check: func(ctx context.Context, before models.Booking, b *models.Booking) error {
+		if err := integrity.Exists(ctx,
+			integrity.Client("client_id", b.ClientID),
+			integrity.Photographer("photographer_id", b.PhotographerID),
+		); err != nil {
+			return err
+		}
+		if slotChanged(before, *b) {
+			return checkBusy(ctx, *b)
+		}
+		return nil
+	},
+	saved: func(ctx context.Context, before, after models.Booking) {
+		publishBookingStatus(ctx, before.Status, after)
+	},
+}
 
 func GetAllBookings(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	cursor, err := bookingHandlerCollection.Find(ctx, bson.M{})
+	q, err := query.Parse(c, bookingListSpec)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal mengambil data"})
+		return utils.Error(c, err)
 	}
-	defer cursor.Close(ctx)
 
-	var bookings []models.Booking
-	if err := cursor.All(ctx, &bookings); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal decode data"})
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	bookings, meta, err := query.Find[models.Booking](ctx, bookingHandlerCollection, q)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("booking.fetch_failed", nil).Wrap(err))
 	}
 
-	return c.JSON(bookings)
+	query.SetLinks(c, q, meta)
+	return utils.SuccessList(c, bookings, meta)
 }
 
 func GetBookingByID(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := primitive.ObjectIDFromHex(idParam)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID tidak valid"})
+		return utils.Error(c, utils.ErrInvalidID)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
 	var booking models.Booking
 	err = bookingHandlerCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&booking)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Booking tidak ditemukan"})
+		return utils.Error(c, utils.NotFoundOr(err, utils.ErrBookingNotFound))
 	}
 
-	return c.JSON(booking)
+	utils.SetETag(c, booking.Version)
+	return utils.Success(c, fiber.StatusOK, booking)
 }
 
 func CreateBooking(c *fiber.Ctx) error {
 	var booking models.Booking
 
 	if err := c.BodyParser(&booking); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format data salah"})
+		return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
 	}
 
-	// Validasi status booking
-	if !isValidBookingStatus(booking.Status) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Status booking tidak valid"})
+	if err := utils.Validate(booking); err != nil {
+		return utils.Error(c, err)
 	}
 
 	booking.ID = primitive.NewObjectID()
 	booking.CreatedAt = time.Now()
 	booking.UpdatedAt = booking.CreatedAt
+	booking.Version = 1
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
+	if err := integrity.Exists(ctx,
+		integrity.Client("client_id", booking.ClientID),
+		integrity.Photographer("photographer_id", booking.PhotographerID),
+	); err != nil {
+		return utils.Error(c, err)
+	}
+
+	// Tanggal yang diblokir dari kalender pribadi fotografer tidak bisa dibooking
+	if err := checkBusy(ctx, booking); err != nil {
+		return utils.Error(c, err)
+	}
+
 	_, err := bookingHandlerCollection.InsertOne(ctx, booking)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal menyimpan booking"})
+		return utils.Error(c, utils.ErrDatabase.WithKey("booking.save_failed", nil).Wrap(err))
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(booking)
-}
+	// Client yang mengantre tanggal ini tidak perlu ditawari lagi
+	if err := waitlist.Accept(ctx, booking, booking.CreatedAt); err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "gagal menutup antrean waitlist",
+			"booking_id", booking.ID.Hex(), "error", err)
+	}
 
+	seedNoteMessage(ctx, booking)
+	events.Publish(ctx, events.BookingCreated, bookingRecipients(ctx, booking), map[string]interface{}{"booking": booking})
+
+	utils.SetETag(c, booking.Version)
+	return utils.Success(c, fiber.StatusCreated, booking)
+}
 
 func UpdateBooking(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := primitive.ObjectIDFromHex(idParam)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID tidak valid"})
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+
+	// Update hanya berlaku untuk versi yang terakhir dibaca client
+	version, err := utils.IfMatch(c)
+	if err != nil {
+		return utils.Error(c, err)
 	}
 
 	var updated models.Booking
 	if err := c.BodyParser(&updated); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format data salah"})
+		return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
 	}
 
-	// Validasi status booking
-	if !isValidBookingStatus(updated.Status) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Status booking tidak valid"})
+	// PUT mengganti seluruh field, jadi divalidasi seperti create
+	if err := utils.Validate(updated); err != nil {
+		return utils.Error(c, err)
 	}
 
 	updated.UpdatedAt = time.Now()
@@ -117,38 +181,56 @@ func UpdateBooking(c *fiber.Ctx) error {
 		},
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
-	result, err := bookingHandlerCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal update booking"})
+	if err := integrity.Exists(ctx,
+		integrity.Client("client_id", updated.ClientID),
+		integrity.Photographer("photographer_id", updated.PhotographerID),
+	); err != nil {
+		return utils.Error(c, err)
+	}
+
+	// Status lama dibutuhkan untuk event perubahan status
+	var current models.Booking
+	if err := bookingHandlerCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&current); err != nil {
+		return utils.Error(c, utils.NotFoundOr(err, utils.ErrBookingNotFound))
 	}
-	if result.MatchedCount == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Booking tidak ditemukan"})
+	if slotChanged(current, updated) {
+		if err := checkBusy(ctx, updated); err != nil {
+			return utils.Error(c, err)
+		}
 	}
 
-	return c.JSON(fiber.Map{"message": "Booking diperbarui"})
+	var booking models.Booking
+	if err := updateVersioned(ctx, c, bookingHandlerCollection, id, version, update, &booking,
+		utils.ErrBookingNotFound, nil, utils.ErrDatabase.WithKey("booking.update_failed", nil)); err != nil {
+		return utils.Error(c, err)
+	}
+	publishBookingStatus(ctx, current.Status, booking)
+
+	return utils.SuccessMessage(c, fiber.StatusOK, "booking.updated", booking)
 }
 
+// PatchBooking mengubah sebagian field booking (JSON Merge Patch), field lain tetap
+func PatchBooking(c *fiber.Ctx) error {
+	return patchDocument(c, bookingPatch)
+}
 
 func DeleteBooking(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := primitive.ObjectIDFromHex(idParam)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID tidak valid"})
+		return utils.Error(c, utils.ErrInvalidID)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
-	result, err := bookingHandlerCollection.DeleteOne(ctx, bson.M{"_id": id})
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal hapus booking"})
-	}
-	if result.DeletedCount == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Booking tidak ditemukan"})
+	// Booking yang sudah punya transaksi tidak boleh dihapus
+	if err := integrity.Delete(ctx, "bookings", id, utils.ErrBookingNotFound); err != nil {
+		return utils.Error(c, err)
 	}
 
-	return c.JSON(fiber.Map{"message": "Booking dihapus"})
+	return utils.SuccessMessage(c, fiber.StatusOK, "booking.deleted", nil)
 }
@@ -0,0 +1,397 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"manajemen-fotografi-api/auth"
+	"manajemen-fotografi-api/i18n"
+	"manajemen-fotografi-api/ical"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
+	"manajemen-fotografi-api/waitlist"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// calendarProdID adalah PRODID feed iCalendar, juga akhiran UID setiap event
+const calendarProdID = "-//Manajemen Fotografi//Booking Calendar//ID"
+
+// calendarUIDDomain membuat UID event stabil per booking, misal <id>@manajemen-fotografi
+const calendarUIDDomain = "@manajemen-fotografi"
+
+// calendarFeedWindow adalah seberapa jauh ke belakang booking lama masih dimuat di feed
+const calendarFeedWindow = 90 * 24 * time.Hour
+
+// maxCalendarUpload membatasi ukuran file .ics yang diimpor
+const maxCalendarUpload = 2 << 20
+
+var (
+	// Feed membaca booking yang sudah dihapus juga, supaya tampil sebagai CANCELLED
+	// dan hilang dari kalender fotografer, bukan menetap dengan data lama
+	calendarBookingCollection = repositories.Unscoped("bookings")
+	calendarClientCollection  = repositories.Unscoped("clients")
+	busyTimeCollection        = repositories.Unscoped("busy_times")
+
+	// calendarEventDuration adalah lama sesi di kalender karena booking hanya menyimpan waktu mulai
+	calendarEventDuration = durationFromEnv("CALENDAR_EVENT_DURATION", 2*time.Hour)
+
+	// calendarLocation dipakai untuk waktu tanpa zona di file .ics yang diimpor
+	calendarLocation = loadWIB()
+)
+
+// ownedPhotographer membaca fotografer dan memastikan yang login adalah pemiliknya atau admin
+func ownedPhotographer(ctx context.Context, id primitive.ObjectID) (models.Photographer, error) {
+	var photographer models.Photographer
+	if err := photographerCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&photographer); err != nil {
+		return photographer, utils.NotFoundOr(err, utils.ErrPhotographerNotFound)
+	}
+	actor, ok := auth.FromContext(ctx)
+	if !ok {
+		return photographer, utils.ErrUnauthenticated
+	}
+	if actor.Role != models.RoleAdmin && actor.UserID != photographer.UserID {
+		return photographer, utils.ErrForbidden
+	}
+	return photographer, nil
+}
+
+// CreateCalendarToken membuat token baru untuk feed kalender fotografer. Token lama langsung
+// tidak berlaku, dan token hanya ditampilkan sekali karena yang disimpan hanya hash-nya.
+func CreateCalendarToken(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	if _, err := ownedPhotographer(ctx, id); err != nil {
+		return utils.Error(c, err)
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return utils.Error(c, utils.ErrInternal.Wrap(err))
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	_, err = photographerCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{"calendar_token_hash": hashCalendarToken(token)},
+	})
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("photographer.update_failed", nil).Wrap(err))
+	}
+
+	return utils.SuccessMessage(c, fiber.StatusCreated, "calendar.token_created", fiber.Map{
+		"token": token,
+		"url":   c.BaseURL() + "/photographers/" + id.Hex() + "/calendar.ics?token=" + token,
+	})
+}
+
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// validCalendarToken membandingkan hash token dengan waktu konstan
+func validCalendarToken(hash, token string) bool {
+	if hash == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(hashCalendarToken(token))) == 1
+}
+
+// GetPhotographerCalendar mengirim feed iCalendar booking fotografer. Feed dibuka aplikasi
+// kalender tanpa session, jadi aksesnya memakai ?token= dari CreateCalendarToken.
+// Booking pending tidak ditampilkan; booking batal, no-show atau terhapus tampil CANCELLED.
+func GetPhotographerCalendar(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	// Fotografer yang tidak ada dijawab sama dengan token salah agar ID tidak bisa ditebak
+	var photographer models.Photographer
+	if err := photographerCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&photographer); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return utils.Error(c, utils.ErrCalendarTokenInvalid)
+		}
+		return utils.Error(c, utils.ErrDatabase.WithKey("calendar.fetch_failed", nil).Wrap(err))
+	}
+	if !validCalendarToken(photographer.CalendarTokenHash, c.Query("token")) {
+		return utils.Error(c, utils.ErrCalendarTokenInvalid)
+	}
+
+	cursor, err := calendarBookingCollection.Find(ctx, bson.M{
+		"photographer_id": id,
+		"status":          bson.M{"$ne": models.BookingStatusPending},
+		"date":            bson.M{"$gte": time.Now().Add(-calendarFeedWindow)},
+	}, options.Find().SetSort(bson.D{{Key: "date", Value: 1}}))
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("calendar.fetch_failed", nil).Wrap(err))
+	}
+	var bookings []models.Booking
+	if err := cursor.All(ctx, &bookings); err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("calendar.fetch_failed", nil).Wrap(err))
+	}
+
+	names, err := clientNames(ctx, bookings)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("calendar.fetch_failed", nil).Wrap(err))
+	}
+
+	lang := utils.Lang(c)
+	cal := ical.Calendar{ProdID: calendarProdID, Name: i18n.T(lang, "calendar.name", nil)}
+	for _, booking := range bookings {
+		cal.Events = append(cal.Events, bookingEvent(lang, booking, names[booking.ClientID]))
+	}
+
+	c.Set(fiber.HeaderContentType, ical.ContentType)
+	c.Set(fiber.HeaderCacheControl, "private, max-age=300")
+	return c.Status(fiber.StatusOK).Send(ical.Encode(cal))
+}
+
+// clientNames mengambil nama client untuk setiap booking, termasuk client yang sudah dihapus
+func clientNames(ctx context.Context, bookings []models.Booking) (map[primitive.ObjectID]string, error) {
+	names := map[primitive.ObjectID]string{}
+	if len(bookings) == 0 {
+		return names, nil
+	}
+	ids := make([]primitive.ObjectID, 0, len(bookings))
+	for _, booking := range bookings {
+		ids = append(ids, booking.ClientID)
+	}
+
+	cursor, err := calendarClientCollection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}},
+		options.Find().SetProjection(bson.M{"name": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var clients []models.Client
+	if err := cursor.All(ctx, &clients); err != nil {
+		return nil, err
+	}
+	for _, client := range clients {
+		names[client.ID] = client.Name
+	}
+	return names, nil
+}
+
+// bookingEvent mengubah booking menjadi VEVENT. SEQUENCE memakai versi dokumen sehingga
+// setiap perubahan booking menggantikan event lama di aplikasi kalender.
+func bookingEvent(lang string, booking models.Booking, client string) ical.Event {
+	status := ical.StatusConfirmed
+	if booking.Status == models.BookingStatusCancelled || booking.Status == models.BookingStatusNoShow || booking.DeletedAt != nil {
+		status = ical.StatusCancelled
+	}
+	modified := booking.UpdatedAt
+	if modified.IsZero() {
+		modified = booking.CreatedAt
+	}
+
+	return ical.Event{
+		UID:         booking.ID.Hex() + calendarUIDDomain,
+		Start:       booking.Date,
+		End:         booking.Date.Add(calendarEventDuration),
+		Summary:     i18n.T(lang, "calendar.summary", map[string]string{"client": client}),
+		Location:    booking.Location,
+		Description: booking.Note,
+		Status:      status,
+		Sequence:    booking.Version,
+		Modified:    modified,
+	}
+}
+
+// ImportBusyTimes mengimpor jadwal sibuk dari kalender pribadi fotografer (file .ics lewat
+// multipart "file" atau body text/calendar). Hasil impor sebelumnya diganti seluruhnya.
+// Acara yang sudah lewat, dibatalkan, bertanda free (TRANSP:TRANSPARENT) atau berulang
+// (RRULE belum didukung) dilewati.
+func ImportBusyTimes(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 30*time.Second)
+	defer cancel()
+
+	if _, err := ownedPhotographer(ctx, id); err != nil {
+		return utils.Error(c, err)
+	}
+
+	data, err := calendarUpload(c)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+	events, err := ical.Parse(bytes.NewReader(data), calendarLocation)
+	if err != nil {
+		return utils.Error(c, utils.Invalid("file", "calendar.invalid_ics").Wrap(err))
+	}
+
+	now := time.Now()
+	imported, skipped := 0, 0
+	for _, event := range events {
+		if event.Status == ical.StatusCancelled || event.Transparent || event.RRule != "" ||
+			!event.End.After(event.Start) || !event.End.After(now) {
+			skipped++
+			continue
+		}
+		_, err := busyTimeCollection.InsertOne(ctx, models.BusyTime{
+			ID:             primitive.NewObjectID(),
+			PhotographerID: id,
+			Source:         models.BusySourceICS,
+			UID:            event.UID,
+			Start:          event.Start,
+			End:            event.End,
+			AllDay:         event.AllDay,
+			ImportedAt:     now,
+		})
+		if err != nil {
+			return utils.Error(c, utils.ErrDatabase.WithKey("busy.import_failed", nil).Wrap(err))
+		}
+		imported++
+	}
+
+	// Hasil impor lama baru dihapus setelah yang baru tersimpan, jadi tidak ada jeda
+	// saat fotografer terlihat kosong
+	_, err = busyTimeCollection.DeleteMany(ctx, bson.M{
+		"photographer_id": id,
+		"source":          models.BusySourceICS,
+		"imported_at":     bson.M{"$ne": now},
+	})
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("busy.import_failed", nil).Wrap(err))
+	}
+
+	return utils.SuccessMessage(c, fiber.StatusOK, "busy.imported", fiber.Map{
+		"imported": imported,
+		"skipped":  skipped,
+	})
+}
+
+// calendarUpload membaca isi .ics dari multipart "file" atau langsung dari body
+func calendarUpload(c *fiber.Ctx) ([]byte, error) {
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, utils.Invalid("file", "calendar.file_required")
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, utils.ErrUpload.Wrap(err)
+		}
+		defer file.Close()
+		return readCalendar(file)
+	}
+	if len(c.Body()) == 0 {
+		return nil, utils.Invalid("file", "calendar.file_required")
+	}
+	return readCalendar(bytes.NewReader(c.Body()))
+}
+
+func readCalendar(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxCalendarUpload+1))
+	if err != nil {
+		return nil, utils.ErrUpload.Wrap(err)
+	}
+	if len(data) > maxCalendarUpload {
+		return nil, utils.InvalidWith("file", "validation.max", map[string]string{"field": "file", "param": "2MB"})
+	}
+	return data, nil
+}
+
+// GetBusyTimes menampilkan jadwal sibuk fotografer yang belum lewat
+func GetBusyTimes(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	if _, err := ownedPhotographer(ctx, id); err != nil {
+		return utils.Error(c, err)
+	}
+
+	cursor, err := busyTimeCollection.Find(ctx, bson.M{
+		"photographer_id": id,
+		"end":             bson.M{"$gt": time.Now()},
+	}, options.Find().SetSort(bson.D{{Key: "start", Value: 1}}))
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("busy.fetch_failed", nil).Wrap(err))
+	}
+	busy := []models.BusyTime{}
+	if err := cursor.All(ctx, &busy); err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("busy.fetch_failed", nil).Wrap(err))
+	}
+
+	return utils.Success(c, fiber.StatusOK, busy)
+}
+
+// activeBooking adalah booking yang memakai jadwal fotografer
+func activeBooking(booking models.Booking) bool {
+	return booking.Status == models.BookingStatusPending || booking.Status == models.BookingStatusConfirmed
+}
+
+// checkBusy menolak booking aktif yang jatuh pada jadwal sibuk fotografer atau pada tanggal
+// yang sedang ditawarkan ke client lain dari waitlist
+func checkBusy(ctx context.Context, booking models.Booking) error {
+	if !activeBooking(booking) {
+		return nil
+	}
+	n, err := busyTimeCollection.CountDocuments(ctx, bson.M{
+		"photographer_id": booking.PhotographerID,
+		"start":           bson.M{"$lte": booking.Date},
+		"end":             bson.M{"$gt": booking.Date},
+	}, options.Count().SetLimit(1))
+	if err != nil {
+		return utils.ErrDatabase.Wrap(err)
+	}
+	if n > 0 {
+		return utils.ErrBookingSlotBusy
+	}
+	return waitlist.Hold(ctx, booking)
+}
+
+// slotChanged mengecek apakah jadwal booking berubah sehingga perlu dicek ulang ke jadwal
+// sibuk. Booking lama yang kebetulan bertabrakan dengan impor baru tetap bisa diubah.
+func slotChanged(before, after models.Booking) bool {
+	return !activeBooking(before) || !before.Date.Equal(after.Date) || before.PhotographerID != after.PhotographerID
+}
+
+func loadWIB() *time.Location {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		return time.FixedZone("WIB", 7*60*60)
+	}
+	return loc
+}
+
+// durationFromEnv membaca durasi seperti "90m" dari env, fallback jika kosong atau tidak valid
+func durationFromEnv(name string, fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(name)); err == nil && d > 0 {
+		return d
+	}
+	return fallback
+}
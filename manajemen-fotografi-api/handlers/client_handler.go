@@ -2,11 +2,13 @@ package handlers
 
 import (
 	"context"
-	
+
 	"time"
 
-	"manajemen-fotografi-api/config"
+	"manajemen-fotografi-api/integrity"
 	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/query"
+	"manajemen-fotografi-api/repositories"
 	"manajemen-fotografi-api/utils"
 
 	"github.com/gofiber/fiber/v2"
@@ -14,68 +16,85 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-var clientCollection = config.GetCollection("clients")
+var clientCollection = repositories.New("clients")
 
-func CreateClient(c *fiber.Ctx) error {
-    var client models.Client
+// clientListSpec adalah whitelist filter dan sort untuk GET /api/clients
+var clientListSpec = query.Spec{
+	Filters: map[string]query.Filter{
+		"user_id": {Field: "user_id", Type: query.ObjectID, Op: query.Eq},
+		"name":    {Field: "name", Type: query.String, Op: query.Prefix},
+	},
+	Sorts:       []string{"created_at", "name"},
+	DefaultSort: "-created_at",
+}
 
-    if err := c.BodyParser(&client); err != nil {
-        return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format data salah"})
-    }
+// clientPatch mengatur field yang boleh diubah lewat PATCH /api/clients/:id
+var clientPatch = patchResource[models.Client]{
+	coll:      clientCollection,
+	fields:    []string{"name", "phone", "address"},
+	notFound:  utils.ErrClientNotFound,
+	duplicate: utils.ErrClientExists,
+	failed:    utils.ErrDatabase.WithKey("client.update_failed", nil),
+	touch: func(cl *models.Client) {
+		cl.Phone = utils.NormalizePhone(cl.Phone)
+		cl.UpdatedAt = time.Now().Unix()
+	},
+}
 
-    if client.UserID.IsZero() {
-        return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "User ID harus diisi"})
-    }
+func CreateClient(c *fiber.Ctx) error {
+	var client models.Client
 
-    if len(client.Name) < 3 {
-        return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Nama client minimal 3 karakter"})
-    }
+	if err := c.BodyParser(&client); err != nil {
+		return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
+	}
 
-    if !utils.IsValidPhone(client.Phone) {
-        return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Nomor telepon tidak valid"})
-    }
+	if err := utils.Validate(client); err != nil {
+		return utils.Error(c, err)
+	}
 
-    if len(client.Address) < 5 {
-        return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Alamat minimal 5 karakter"})
-    }
+	// Nomor disimpan dalam format E.164 supaya bisa langsung dipakai kanal WhatsApp/SMS
+	client.Phone = utils.NormalizePhone(client.Phone)
+	client.ID = primitive.NewObjectID()
+	client.CreatedAt = time.Now().Unix()
+	client.UpdatedAt = client.CreatedAt
+	client.Version = 1
 
-    client.ID = primitive.NewObjectID()
-    client.CreatedAt = time.Now().Unix()
-    client.UpdatedAt = client.CreatedAt
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
 
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
+	// Profil client hanya untuk user dengan role client
+	if err := integrity.UserWithRole(ctx, client.UserID, models.RoleClient); err != nil {
+		return utils.Error(c, err)
+	}
 
-    _, err := clientCollection.InsertOne(ctx, client)
-    if err != nil {
-        return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal menyimpan client"})
-    }
+	_, err := clientCollection.InsertOne(ctx, client)
+	if err != nil {
+		return utils.Error(c, utils.DuplicateOr(err, utils.ErrClientExists, utils.ErrDatabase.WithKey("client.save_failed", nil)))
+	}
 
-    return c.Status(fiber.StatusCreated).JSON(client)
+	utils.SetETag(c, client.Version)
+	return utils.Success(c, fiber.StatusCreated, client)
 }
 
-
-
-
-
 // GetClientByID mendapatkan data client berdasarkan ID
 func GetClientByID(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	clientID, err := primitive.ObjectIDFromHex(idParam)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID tidak valid"})
+		return utils.Error(c, utils.ErrInvalidID)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
 	var client models.Client
 	err = clientCollection.FindOne(ctx, bson.M{"_id": clientID}).Decode(&client)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Client tidak ditemukan"})
+		return utils.Error(c, utils.NotFoundOr(err, utils.ErrClientNotFound))
 	}
 
-	return c.JSON(client)
+	utils.SetETag(c, client.Version)
+	return utils.Success(c, fiber.StatusOK, client)
 }
 
 // GetClientByUserID mendapatkan data client berdasarkan UserID (relasi)
@@ -83,118 +102,107 @@ func GetClientByUserID(c *fiber.Ctx) error {
 	userIDParam := c.Params("user_id")
 	userID, err := primitive.ObjectIDFromHex(userIDParam)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "User ID tidak valid"})
+		return utils.Error(c, utils.ErrInvalidUserID)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
 	var client models.Client
 	err = clientCollection.FindOne(ctx, bson.M{"user_id": userID}).Decode(&client)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Client tidak ditemukan"})
+		return utils.Error(c, utils.NotFoundOr(err, utils.ErrClientNotFound))
 	}
 
-	return c.JSON(client)
+	utils.SetETag(c, client.Version)
+	return utils.Success(c, fiber.StatusOK, client)
 }
 
 // GetAllClients mengambil semua data client
 func GetAllClients(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	cursor, err := clientCollection.Find(ctx, bson.M{})
+	q, err := query.Parse(c, clientListSpec)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal mengambil data client"})
+		return utils.Error(c, err)
 	}
-	defer cursor.Close(ctx)
 
-	var clients []models.Client
-	if err = cursor.All(ctx, &clients); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memproses data client"})
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	clients, meta, err := query.Find[models.Client](ctx, clientCollection, q)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("client.fetch_failed", nil).Wrap(err))
 	}
 
-	return c.JSON(clients)
+	query.SetLinks(c, q, meta)
+	return utils.SuccessList(c, clients, meta)
 }
 
 // UpdateClient mengubah data client berdasarkan ID
 func UpdateClient(c *fiber.Ctx) error {
-    idParam := c.Params("id")
-    clientID, err := primitive.ObjectIDFromHex(idParam)
-    if err != nil {
-        return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID tidak valid"})
-    }
-
-    var updateData models.Client
-    if err := c.BodyParser(&updateData); err != nil {
-        return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format data salah"})
-    }
-
-    // Validasi Name jika ada update
-    if updateData.Name != "" && len(updateData.Name) < 3 {
-        return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Nama client minimal 3 karakter"})
-    }
-
-    // Validasi Phone jika ada update
-    if updateData.Phone != "" && !utils.IsValidPhone(updateData.Phone) {
-        return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Nomor telepon tidak valid"})
-    }
-
-    // Validasi Address jika ada update
-    if updateData.Address != "" && len(updateData.Address) < 5 {
-        return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Alamat minimal 5 karakter"})
-    }
-
-    updateData.UpdatedAt = time.Now().Unix()
-
-    update := bson.M{
-        "name":       updateData.Name,
-        "phone":      updateData.Phone,
-        "address":    updateData.Address,
-        "updated_at": updateData.UpdatedAt,
-    }
-
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
-
-    result, err := clientCollection.UpdateOne(ctx, bson.M{"_id": clientID}, bson.M{"$set": update})
-    if err != nil {
-        return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal update client"})
-    }
-
-    if result.MatchedCount == 0 {
-        return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Client tidak ditemukan"})
-    }
-
-    var updatedClient models.Client
-    err = clientCollection.FindOne(ctx, bson.M{"_id": clientID}).Decode(&updatedClient)
-    if err != nil {
-        return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal mengambil data yang diupdate"})
-    }
-
-    return c.JSON(updatedClient)
+	idParam := c.Params("id")
+	clientID, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+
+	// Update hanya berlaku untuk versi yang terakhir dibaca client
+	version, err := utils.IfMatch(c)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	var updateData models.Client
+	if err := c.BodyParser(&updateData); err != nil {
+		return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
+	}
+
+	// PUT mengganti seluruh field yang bisa diubah, jadi semuanya divalidasi.
+	// Untuk mengubah sebagian field gunakan PATCH.
+	if err := utils.ValidateFields(updateData, "Name", "Phone", "Address"); err != nil {
+		return utils.Error(c, err)
+	}
+
+	updateData.UpdatedAt = time.Now().Unix()
+
+	update := bson.M{
+		"name":       updateData.Name,
+		"phone":      utils.NormalizePhone(updateData.Phone),
+		"address":    updateData.Address,
+		"updated_at": updateData.UpdatedAt,
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var updatedClient models.Client
+	if err := updateVersioned(ctx, c, clientCollection, clientID, version, bson.M{"$set": update}, &updatedClient,
+		utils.ErrClientNotFound, utils.ErrClientExists, utils.ErrDatabase.WithKey("client.update_failed", nil)); err != nil {
+		return utils.Error(c, err)
+	}
+
+	return utils.Success(c, fiber.StatusOK, updatedClient)
 }
 
+// PatchClient mengubah sebagian field client (JSON Merge Patch), field lain tetap
+func PatchClient(c *fiber.Ctx) error {
+	return patchDocument(c, clientPatch)
+}
 
 // DeleteClient menghapus data client berdasarkan ID
 func DeleteClient(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	clientID, err := primitive.ObjectIDFromHex(idParam)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID tidak valid"})
+		return utils.Error(c, utils.ErrInvalidID)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
-	result, err := clientCollection.DeleteOne(ctx, bson.M{"_id": clientID})
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal menghapus client"})
+	// Booking aktif menahan penghapusan, riwayat booking dianonimkan
+	if err := integrity.Delete(ctx, "clients", clientID, utils.ErrClientNotFound); err != nil {
+		return utils.Error(c, err)
 	}
 
-	if result.DeletedCount == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Client tidak ditemukan"})
-	}
-
-	return c.JSON(fiber.Map{"message": "Client berhasil dihapus"})
-}
\ No newline at end of file
+	return utils.SuccessMessage(c, fiber.StatusOK, "client.deleted", nil)
+}
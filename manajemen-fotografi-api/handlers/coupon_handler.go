@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"manajemen-fotografi-api/coupons"
+	"manajemen-fotografi-api/integrity"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/query"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	couponCollection     = repositories.New(coupons.Collection)
+	redemptionCollection = repositories.New(coupons.RedemptionCollection)
+)
+
+// couponListSpec adalah whitelist filter dan sort untuk GET /api/admin/coupons
+var couponListSpec = query.Spec{
+	Filters: map[string]query.Filter{
+		"code":            {Field: "code", Type: query.String, Op: query.Prefix},
+		"type":            {Field: "type", Type: query.String, Op: query.Eq},
+		"photographer_id": {Field: "photographer_ids", Type: query.ObjectID, Op: query.Eq},
+		"ends_from":       {Field: "ends_at", Type: query.Time, Op: query.Gte},
+		"ends_to":         {Field: "ends_at", Type: query.Time, Op: query.Lte},
+	},
+	Sorts:       []string{"created_at", "code", "ends_at", "redeemed"},
+	DefaultSort: "-created_at",
+}
+
+// redemptionListSpec adalah whitelist filter dan sort untuk GET /api/admin/coupons/:id/redemptions
+var redemptionListSpec = query.Spec{
+	Filters: map[string]query.Filter{
+		"client_id":    {Field: "client_id", Type: query.ObjectID, Op: query.Eq},
+		"created_from": {Field: "created_at", Type: query.Time, Op: query.Gte},
+		"created_to":   {Field: "created_at", Type: query.Time, Op: query.Lte},
+	},
+	Sorts:       []string{"created_at", "discount"},
+	DefaultSort: "-created_at",
+}
+
+// couponPreviewInput adalah body POST /api/coupons/preview; total adalah harga sebelum potongan
+type couponPreviewInput struct {
+	Code      string             `json:"coupon_code" validate:"required,max=32"`
+	BookingID primitive.ObjectID `json:"booking_id" validate:"objectid"`
+	Total     float64            `json:"total" validate:"required,gt=0"`
+}
+
+// checkCoupon memeriksa aturan yang melibatkan lebih dari satu field kupon
+func checkCoupon(ctx context.Context, coupon models.Coupon) error {
+	if coupon.Type == models.CouponPercent && coupon.Value > 100 {
+		return utils.Invalid("value", "coupon.percent_max")
+	}
+	if coupon.StartsAt != nil && coupon.EndsAt != nil && !coupon.StartsAt.Before(*coupon.EndsAt) {
+		return utils.Invalid("ends_at", "coupon.invalid_window")
+	}
+	refs := make([]integrity.Ref, 0, len(coupon.PhotographerIDs))
+	for _, id := range coupon.PhotographerIDs {
+		refs = append(refs, integrity.Photographer("photographer_ids", id))
+	}
+	return integrity.Exists(ctx, refs...)
+}
+
+// CreateCoupon membuat kode promo baru. Kupon langsung aktif kecuali active dikirim false.
+func CreateCoupon(c *fiber.Ctx) error {
+	coupon := models.Coupon{Active: true}
+	if err := c.BodyParser(&coupon); err != nil {
+		return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
+	}
+	coupon.Code = coupons.Normalize(coupon.Code)
+	if err := utils.Validate(coupon); err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	if err := checkCoupon(ctx, coupon); err != nil {
+		return utils.Error(c, err)
+	}
+
+	now := time.Now()
+	coupon.ID = primitive.NewObjectID()
+	coupon.Redeemed = 0
+	coupon.Usage = nil
+	coupon.CreatedBy = actorID(ctx)
+	coupon.CreatedAt = now
+	coupon.UpdatedAt = now
+	if coupon.PhotographerIDs == nil {
+		coupon.PhotographerIDs = []primitive.ObjectID{}
+	}
+
+	if _, err := couponCollection.InsertOne(ctx, coupon); err != nil {
+		return utils.Error(c, utils.DuplicateOr(err, utils.ErrCouponExists, utils.ErrDatabase.WithKey("coupon.save_failed", nil)))
+	}
+	return utils.SuccessMessage(c, fiber.StatusCreated, "coupon.created", coupon)
+}
+
+// GetAllCoupons menampilkan kupon; ?active=true|false menyaring status aktif
+func GetAllCoupons(c *fiber.Ctx) error {
+	q, err := query.Parse(c, couponListSpec)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+	switch c.Query("active") {
+	case "":
+	case "true", "false":
+		q.Filter["active"] = c.Query("active") == "true"
+	default:
+		return utils.Error(c, utils.Invalid("active", "validation.invalid"))
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	list, meta, err := query.Find[models.Coupon](ctx, couponCollection, q)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("coupon.fetch_failed", nil).Wrap(err))
+	}
+
+	query.SetLinks(c, q, meta)
+	return utils.SuccessList(c, list, meta)
+}
+
+// GetCouponByID menampilkan satu kupon beserta jumlah pemakaiannya
+func GetCouponByID(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var coupon models.Coupon
+	if err := couponCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&coupon); err != nil {
+		return utils.Error(c, utils.NotFoundOr(err, utils.ErrCouponNotFound))
+	}
+	return utils.Success(c, fiber.StatusOK, coupon)
+}
+
+// UpdateCoupon mengganti aturan kupon. Kode dan jumlah pemakaian tidak bisa diubah;
+// menurunkan batas di bawah pemakaian saat ini hanya menutup pemakaian berikutnya.
+func UpdateCoupon(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var current models.Coupon
+	if err := couponCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&current); err != nil {
+		return utils.Error(c, utils.NotFoundOr(err, utils.ErrCouponNotFound))
+	}
+
+	var updated models.Coupon
+	if err := c.BodyParser(&updated); err != nil {
+		return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
+	}
+	updated.Code = current.Code
+	if err := utils.Validate(updated); err != nil {
+		return utils.Error(c, err)
+	}
+	if err := checkCoupon(ctx, updated); err != nil {
+		return utils.Error(c, err)
+	}
+	if updated.PhotographerIDs == nil {
+		updated.PhotographerIDs = []primitive.ObjectID{}
+	}
+
+	var coupon models.Coupon
+	err = couponCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{
+			"description":      updated.Description,
+			"type":             updated.Type,
+			"value":            updated.Value,
+			"max_discount":     updated.MaxDiscount,
+			"min_spend":        updated.MinSpend,
+			"starts_at":        updated.StartsAt,
+			"ends_at":          updated.EndsAt,
+			"usage_limit":      updated.UsageLimit,
+			"per_client_limit": updated.PerClientLimit,
+			"photographer_ids": updated.PhotographerIDs,
+			"active":           updated.Active,
+			"updated_at":       time.Now(),
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&coupon)
+	if err != nil {
+		return utils.Error(c, utils.NotFoundOr(err, utils.ErrCouponNotFound))
+	}
+	return utils.SuccessMessage(c, fiber.StatusOK, "coupon.updated", coupon)
+}
+
+// GetCouponRedemptions menampilkan riwayat pemakaian kupon
+func GetCouponRedemptions(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+	q, err := query.Parse(c, redemptionListSpec)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	q.Filter["coupon_id"] = id
+	redemptions, meta, err := query.Find[models.CouponRedemption](ctx, redemptionCollection, q)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("coupon.fetch_failed", nil).Wrap(err))
+	}
+
+	query.SetLinks(c, q, meta)
+	return utils.SuccessList(c, redemptions, meta)
+}
+
+// PreviewCoupon menghitung potongan kupon untuk booking sebelum dibayar, tanpa memakai kuota
+func PreviewCoupon(c *fiber.Ctx) error {
+	var input couponPreviewInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
+	}
+	if err := utils.Validate(input); err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var booking models.Booking
+	if err := bookingCollection.FindOne(ctx, bson.M{"_id": input.BookingID}).Decode(&booking); err != nil {
+		return utils.Error(c, utils.NotFoundOr(err, utils.ErrBookingNotFound))
+	}
+
+	quote, err := coupons.Preview(ctx, input.Code, coupons.Checkout{
+		ClientID:       booking.ClientID,
+		PhotographerID: booking.PhotographerID,
+		BookingID:      booking.ID,
+		Subtotal:       input.Total,
+		At:             time.Now(),
+	})
+	if err != nil {
+		return utils.Error(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, quote)
+}
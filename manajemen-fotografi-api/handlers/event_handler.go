@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"manajemen-fotografi-api/auth"
+	"manajemen-fotografi-api/events"
+	"manajemen-fotografi-api/logging"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Pengaturan stream Server-Sent Events
+const (
+	replayLimit       = 500              // event maksimal yang dikirim ulang saat menyambung kembali
+	heartbeatInterval = 25 * time.Second // komentar kosong supaya proxy tidak memutus koneksi diam
+	retryMillis       = 3000             // jeda reconnect yang disarankan ke EventSource
+)
+
+// StreamEvents mengirim event untuk user yang login lewat Server-Sent Events.
+// Client yang menyambung ulang mengirim header Last-Event-ID (atau ?last_event_id)
+// dan menerima event yang terlewat sebelum event baru.
+func StreamEvents(c *fiber.Ctx) error {
+	actor, ok := auth.FromContext(c.UserContext())
+	if !ok {
+		return utils.Error(c, utils.ErrUnauthenticated)
+	}
+
+	var after primitive.ObjectID
+	if lastID := c.Get("Last-Event-ID", c.Query("last_event_id")); lastID != "" {
+		id, err := primitive.ObjectIDFromHex(lastID)
+		if err != nil {
+			return utils.Error(c, utils.Invalid("last_event_id", "validation.objectid"))
+		}
+		after = id
+	}
+
+	// Subscribe sebelum replay supaya event yang terjadi di antaranya tidak hilang
+	sub := events.Default.Subscribe(events.ForUser(actor.UserID))
+
+	// c tidak boleh dipakai lagi setelah handler kembali, stream berjalan sesudahnya
+	ctx := context.WithoutCancel(c.UserContext())
+	var replay []models.Event
+	if !after.IsZero() {
+		replayCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		var err error
+		replay, err = events.Default.Replay(replayCtx, actor.UserID, after, replayLimit)
+		if err != nil {
+			sub.Close()
+			return utils.Error(c, utils.ErrDatabase.WithKey("event.replay_failed", nil).Wrap(err))
+		}
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no") // nginx tidak boleh menahan stream
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer sub.Close()
+		logger := logging.FromContext(ctx)
+
+		fmt.Fprintf(w, "retry: %d\n\n", retryMillis)
+		replayed := make(map[primitive.ObjectID]bool, len(replay))
+		for _, event := range replay {
+			replayed[event.ID] = true
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case event, ok := <-sub.C:
+				if !ok {
+					// Tertinggal atau server berhenti, client menyambung ulang dengan Last-Event-ID
+					return
+				}
+				if replayed[event.ID] {
+					continue
+				}
+				if err := writeEvent(w, event); err != nil {
+					logger.ErrorContext(ctx, "gagal menulis event", "error", err)
+					return
+				}
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": ping\n\n")
+			}
+			// Flush gagal berarti client sudah menutup koneksi
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+	return nil
+}
+
+func writeEvent(w *bufio.Writer, event models.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID.Hex(), event.Type, data)
+	return err
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+
+	"manajemen-fotografi-api/events"
+	"manajemen-fotografi-api/logging"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// profileUserID mengambil user_id pemilik profil client atau fotografer
+func profileUserID(ctx context.Context, coll repositories.Collection, id primitive.ObjectID) (primitive.ObjectID, bool) {
+	var profile struct {
+		UserID primitive.ObjectID `bson:"user_id"`
+	}
+	err := coll.FindOne(ctx, bson.M{"_id": id}, options.FindOne().SetProjection(bson.M{"user_id": 1})).Decode(&profile)
+	if err != nil {
+		return primitive.NilObjectID, false
+	}
+	return profile.UserID, true
+}
+
+// bookingRecipients adalah user client dan fotografer pada booking. Profil yang sudah
+// dihapus atau dianonimkan dilewati.
+func bookingRecipients(ctx context.Context, booking models.Booking) []primitive.ObjectID {
+	var recipients []primitive.ObjectID
+	if id, ok := profileUserID(ctx, clientCollection, booking.ClientID); ok {
+		recipients = append(recipients, id)
+	}
+	if id, ok := profileUserID(ctx, photographerCollection, booking.PhotographerID); ok {
+		recipients = append(recipients, id)
+	}
+	return recipients
+}
+
+// publishBookingStatus mengirim booking.status_changed jika status booking berubah
+func publishBookingStatus(ctx context.Context, from string, booking models.Booking) {
+	if from == booking.Status {
+		return
+	}
+	events.Publish(ctx, events.BookingStatusChanged, bookingRecipients(ctx, booking), map[string]interface{}{
+		"booking": booking,
+		"from":    from,
+		"to":      booking.Status,
+	})
+}
+
+// publishGallery mengirim gallery.published ke fotografer dan, jika galeri untuk sebuah
+// booking, ke client booking tersebut
+func publishGallery(ctx context.Context, gallery models.Gallery) {
+	var recipients []primitive.ObjectID
+	if gallery.BookingID != nil {
+		var booking models.Booking
+		err := bookingHandlerCollection.FindOne(ctx, bson.M{"_id": *gallery.BookingID}).Decode(&booking)
+		if err != nil {
+			logging.FromContext(ctx).WarnContext(ctx, "booking galeri tidak ditemukan untuk notifikasi",
+				"gallery_id", gallery.ID.Hex(), "error", err)
+		} else if id, ok := profileUserID(ctx, clientCollection, booking.ClientID); ok {
+			recipients = append(recipients, id)
+		}
+	}
+	if id, ok := profileUserID(ctx, photographerCollection, gallery.PhotographerID); ok {
+		recipients = append(recipients, id)
+	}
+	events.Publish(ctx, events.GalleryPublished, recipients, map[string]interface{}{"gallery": gallery})
+}
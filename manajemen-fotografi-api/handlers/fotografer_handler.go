@@ -3,13 +3,14 @@ package handlers
 import (
 	"context"
 	"encoding/json"
-	"fmt"
-	"strings"
 
 	"time"
 
-	"manajemen-fotografi-api/config"
+	"manajemen-fotografi-api/integrity"
 	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/query"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/storage"
 	"manajemen-fotografi-api/utils"
 
 	"github.com/gofiber/fiber/v2"
@@ -17,44 +18,73 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-var photographerCollection = config.GetCollection("photographers")
+var photographerCollection = repositories.New("photographers")
+
+// photographerListSpec adalah whitelist filter dan sort untuk GET /photographers.
+// Urutan default memakai jumlah favorit client, fotografer terbaru didahulukan jika sama.
+var photographerListSpec = query.Spec{
+	Filters: map[string]query.Filter{
+		"user_id":  {Field: "user_id", Type: query.ObjectID, Op: query.Eq},
+		"location": {Field: "location", Type: query.String, Op: query.Prefix},
+	},
+	Sorts:       []string{"favorite_count", "created_at", "location"},
+	DefaultSort: "-favorite_count",
+}
 
+// photographerPatch mengatur field yang boleh diubah lewat PATCH /photographers/:id.
+// Foto profil tetap diubah lewat PUT karena membutuhkan upload file.
+var photographerPatch = patchResource[models.Photographer]{
+	coll:      photographerCollection,
+	fields:    []string{"phone", "description", "portfolio", "location"},
+	notFound:  utils.ErrPhotographerNotFound,
+	duplicate: utils.ErrPhotographerExists,
+	failed:    utils.ErrDatabase.WithKey("photographer.update_failed", nil),
+	touch: func(p *models.Photographer) {
+		p.Phone = utils.NormalizePhone(p.Phone)
+		p.UpdatedAt = time.Now().Unix()
+	},
+}
 
 // CreatePhotographer menambahkan data fotografer baru
 func CreatePhotographer(c *fiber.Ctx) error {
 	var photographer models.Photographer
 
 	if err := c.BodyParser(&photographer); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format data salah"})
+		return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
 	}
 
-	if !utils.IsValidPhone(photographer.Phone) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Nomor telepon tidak valid"})
+	if err := utils.Validate(photographer); err != nil {
+		return utils.Error(c, err)
 	}
 
+	// Nomor disimpan dalam format E.164 supaya bisa langsung dipakai kanal WhatsApp/SMS
+	photographer.Phone = utils.NormalizePhone(photographer.Phone)
 
 	photographer.CreatedAt = time.Now().Unix()
 	photographer.UpdatedAt = photographer.CreatedAt
 
-
 	// Set ID baru dan timestamp
 	photographer.ID = primitive.NewObjectID()
 	photographer.CreatedAt = time.Now().Unix()
+	photographer.Version = 1
+	photographer.FavoriteCount = 0 // dihitung dari shortlist client, bukan dari input
+	photographer.ProfilePhoto = "" // hanya diisi dari upload lewat PUT, file-nya ikut dihapus saat purge
 
-	// Validasi UserID
-	if photographer.UserID.IsZero() {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "User ID harus diisi"})
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
+	// Profil fotografer hanya untuk user dengan role photographer
+	if err := integrity.UserWithRole(ctx, photographer.UserID, models.RolePhotographer); err != nil {
+		return utils.Error(c, err)
+	}
+
 	_, err := photographerCollection.InsertOne(ctx, photographer)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal menyimpan fotografer"})
+		return utils.Error(c, utils.DuplicateOr(err, utils.ErrPhotographerExists, utils.ErrDatabase.WithKey("photographer.save_failed", nil)))
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(photographer)
+	utils.SetETag(c, photographer.Version)
+	return utils.Success(c, fiber.StatusCreated, photographer)
 }
 
 // GetPhotographerByID mendapatkan data fotografer berdasarkan ID
@@ -62,19 +92,20 @@ func GetPhotographerByID(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	photographerID, err := primitive.ObjectIDFromHex(idParam)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID tidak valid"})
+		return utils.Error(c, utils.ErrInvalidID)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
 	var photographer models.Photographer
 	err = photographerCollection.FindOne(ctx, bson.M{"_id": photographerID}).Decode(&photographer)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Fotografer tidak ditemukan"})
+		return utils.Error(c, utils.NotFoundOr(err, utils.ErrPhotographerNotFound))
 	}
 
-	return c.JSON(photographer)
+	utils.SetETag(c, photographer.Version)
+	return utils.Success(c, fiber.StatusOK, photographer)
 }
 
 // GetPhotographerByUserID mendapatkan data fotografer berdasarkan UserID (relasi)
@@ -82,150 +113,138 @@ func GetPhotographerByUserID(c *fiber.Ctx) error {
 	userIDParam := c.Params("user_id")
 	userID, err := primitive.ObjectIDFromHex(userIDParam)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "User ID tidak valid"})
+		return utils.Error(c, utils.ErrInvalidUserID)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
 	var photographer models.Photographer
 	err = photographerCollection.FindOne(ctx, bson.M{"user_id": userID}).Decode(&photographer)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Fotografer tidak ditemukan"})
+		return utils.Error(c, utils.NotFoundOr(err, utils.ErrPhotographerNotFound))
 	}
 
-	return c.JSON(photographer)
+	utils.SetETag(c, photographer.Version)
+	return utils.Success(c, fiber.StatusOK, photographer)
 }
 
 // GetAllPhotographers mengambil semua data fotografer
 func GetAllPhotographers(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	cursor, err := photographerCollection.Find(ctx, bson.M{})
+	q, err := query.Parse(c, photographerListSpec)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal mengambil data fotografer"})
+		return utils.Error(c, err)
 	}
-	defer cursor.Close(ctx)
 
-	var photographers []models.Photographer
-	if err = cursor.All(ctx, &photographers); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memproses data fotografer"})
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	photographers, meta, err := query.Find[models.Photographer](ctx, photographerCollection, q)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("photographer.fetch_failed", nil).Wrap(err))
 	}
 
-	return c.JSON(photographers)
+	query.SetLinks(c, q, meta)
+	return utils.SuccessList(c, photographers, meta)
 }
 
 // UpdatePhotographer mengubah data fotografer berdasarkan ID
 func UpdatePhotographer(c *fiber.Ctx) error {
-    idParam := c.Params("id")
-    photographerID, err := primitive.ObjectIDFromHex(idParam)
-    if err != nil {
-        return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID tidak valid"})
-    }
-
-    // ambil file profile_photo jika ada
-    file, err := c.FormFile("profile_photo")
-    var profilePhotoURL string
-    if err == nil {
-        // simpan file ke uploads folder
-        filename := fmt.Sprintf("%d_%s", time.Now().Unix(), file.Filename)
-        err = c.SaveFile(file, fmt.Sprintf("./uploads/%s", filename))
-        if err != nil {
-            return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal menyimpan foto profil"})
-        }
-        profilePhotoURL = fmt.Sprintf("/uploads/%s", filename)
-    }
-
-    // ambil data lain dari form fields
-    phone := c.FormValue("phone")
-    description := c.FormValue("description")
-    location := c.FormValue("location")
-    portfolioStr := c.FormValue("portfolio") // misal portfolio dikirim sebagai JSON string array dari frontend
-
-    // Validasi nomor telepon jika tidak kosong
-    if phone != "" && !utils.IsValidPhone(phone) {
-        return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Nomor telepon tidak valid"})
-    }
-
-    if len(description) > 500 {
-        return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Deskripsi terlalu panjang (maksimal 500 karakter)"})
-    }
-
-    if len(location) > 200 {
-        return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Lokasi terlalu panjang (maksimal 200 karakter)"})
-    }
-
-    // parsing portfolio JSON string ke slice string
-    var portfolio []string
-    if portfolioStr != "" {
-        err = json.Unmarshal([]byte(portfolioStr), &portfolio)
-        if err != nil {
-            return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format portfolio salah"})
-        }
-    }
-
-    // validasi portfolio url tidak kosong
-    for _, url := range portfolio {
-        if strings.TrimSpace(url) == "" {
-            return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Terdapat URL portfolio yang kosong"})
-        }
-    }
-
-    update := bson.M{
-        "phone":       phone,
-        "description": description,
-        "portfolio":   portfolio,
-        "location":    location,
-        "updated_at":  time.Now().Unix(),
-    }
-
-    if profilePhotoURL != "" {
-        update["profile_photo"] = profilePhotoURL
-    }
-
-    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-    defer cancel()
-
-    result, err := photographerCollection.UpdateOne(ctx, bson.M{"_id": photographerID}, bson.M{"$set": update})
-    if err != nil {
-        return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal update fotografer"})
-    }
-
-    if result.MatchedCount == 0 {
-        return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Fotografer tidak ditemukan"})
-    }
-
-    var updatedPhotographer models.Photographer
-    err = photographerCollection.FindOne(ctx, bson.M{"_id": photographerID}).Decode(&updatedPhotographer)
-    if err != nil {
-        return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal mengambil data yang diupdate"})
-    }
-
-    return c.JSON(updatedPhotographer)
-}
+	idParam := c.Params("id")
+	photographerID, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
 
+	// Update hanya berlaku untuk versi yang terakhir dibaca client.
+	// Dicek sebelum upload supaya file tidak tersimpan untuk update yang pasti ditolak.
+	version, err := utils.IfMatch(c)
+	if err != nil {
+		return utils.Error(c, err)
+	}
 
+	// ambil file profile_photo jika ada
+	file, err := c.FormFile("profile_photo")
+	var profilePhotoURL string
+	if err == nil {
+		// simpan file ke uploads folder
+		profilePhotoURL, err = storage.Default.Save(c.UserContext(), "profile_photo", file)
+		if err != nil {
+			return utils.Error(c, utils.ErrUpload.WithKey("photographer.photo_upload_failed", nil).Wrap(err))
+		}
+	}
+
+	// ambil data lain dari form fields
+	phone := c.FormValue("phone")
+	description := c.FormValue("description")
+	location := c.FormValue("location")
+	portfolioStr := c.FormValue("portfolio") // misal portfolio dikirim sebagai JSON string array dari frontend
+
+	// parsing portfolio JSON string ke slice string, portfolio yang tidak dikirim menjadi kosong
+	portfolio := []string{}
+	if portfolioStr != "" {
+		err = json.Unmarshal([]byte(portfolioStr), &portfolio)
+		if err != nil {
+			return utils.Error(c, utils.Invalid("portfolio", "validation.json_array"))
+		}
+	}
+
+	// PUT mengganti seluruh field yang bisa diubah, jadi semuanya divalidasi.
+	// Untuk mengubah sebagian field gunakan PATCH.
+	input := models.Photographer{
+		Phone:       phone,
+		Description: description,
+		Location:    location,
+		Portfolio:   portfolio,
+	}
+	if err := utils.ValidateFields(input, "Phone", "Description", "Portfolio", "Location"); err != nil {
+		return utils.Error(c, err)
+	}
+
+	update := bson.M{
+		"phone":       utils.NormalizePhone(phone),
+		"description": description,
+		"portfolio":   portfolio,
+		"location":    location,
+		"updated_at":  time.Now().Unix(),
+	}
+
+	if profilePhotoURL != "" {
+		update["profile_photo"] = profilePhotoURL
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var updatedPhotographer models.Photographer
+	if err := updateVersioned(ctx, c, photographerCollection, photographerID, version, bson.M{"$set": update}, &updatedPhotographer,
+		utils.ErrPhotographerNotFound, utils.ErrPhotographerExists, utils.ErrDatabase.WithKey("photographer.update_failed", nil)); err != nil {
+		return utils.Error(c, err)
+	}
+
+	return utils.Success(c, fiber.StatusOK, updatedPhotographer)
+}
+
+// PatchPhotographer mengubah sebagian field fotografer (JSON Merge Patch), field lain tetap
+func PatchPhotographer(c *fiber.Ctx) error {
+	return patchDocument(c, photographerPatch)
+}
 
 // DeletePhotographer menghapus data fotografer berdasarkan ID
 func DeletePhotographer(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	photographerID, err := primitive.ObjectIDFromHex(idParam)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID tidak valid"})
+		return utils.Error(c, utils.ErrInvalidID)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
-	result, err := photographerCollection.DeleteOne(ctx, bson.M{"_id": photographerID})
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal menghapus fotografer"})
+	// Booking aktif menahan penghapusan, galeri ikut dihapus, riwayat booking dianonimkan
+	if err := integrity.Delete(ctx, "photographers", photographerID, utils.ErrPhotographerNotFound); err != nil {
+		return utils.Error(c, err)
 	}
 
-	if result.DeletedCount == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Fotografer tidak ditemukan"})
-	}
-
-	return c.JSON(fiber.Map{"message": "Fotografer berhasil dihapus"})
-}
\ No newline at end of file
+	return utils.SuccessMessage(c, fiber.StatusOK, "photographer.deleted", nil)
+}
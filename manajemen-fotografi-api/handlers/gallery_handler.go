@@ -4,90 +4,145 @@ import (
 	"context"
 	"time"
 
-	"manajemen-fotografi-api/config"
+	"manajemen-fotografi-api/integrity"
 	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/query"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
 
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-var galleryCollection = config.GetCollection("galleries")
+var galleryCollection = repositories.New("galleries")
+
+// galleryListSpec adalah whitelist filter dan sort untuk GET /api/galleries
+var galleryListSpec = query.Spec{
+	Filters: map[string]query.Filter{
+		"photographer_id": {Field: "photographer_id", Type: query.ObjectID, Op: query.Eq},
+		"title":           {Field: "title", Type: query.String, Op: query.Prefix},
+		"created_from":    {Field: "created_at", Type: query.Time, Op: query.Gte},
+		"created_to":      {Field: "created_at", Type: query.Time, Op: query.Lte},
+	},
+	Sorts:       []string{"created_at", "title"},
+	DefaultSort: "-created_at",
+}
 
-func GetAllGalleries(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// galleryPatch mengatur field yang boleh diubah lewat PATCH /api/galleries/:id
+var galleryPatch = patchResource[models.Gallery]{
+	coll:     galleryCollection,
+	fields:   []string{"photographer_id", "booking_id", "title", "image_url", "description"},
+	notFound: utils.ErrGalleryNotFound,
+	failed:   utils.ErrDatabase.WithKey("gallery.update_failed", nil),
+	touch:    func(g *models.Gallery) { g.UpdatedAt = time.Now() },
+	check: func(ctx context.Context, _ models.Gallery, g *models.Gallery) error {
+		return integrity.Exists(ctx, galleryRefs(*g)...)
+	},
+}
+
+// galleryRefs adalah referensi galeri yang harus ada, booking hanya jika diisi
+func galleryRefs(g models.Gallery) []integrity.Ref {
+	refs := []integrity.Ref{integrity.Photographer("photographer_id", g.PhotographerID)}
+	if g.BookingID != nil {
+		refs = append(refs, integrity.Booking("booking_id", *g.BookingID))
+	}
+	return refs
+}
 
-	cursor, err := galleryCollection.Find(ctx, bson.M{})
+func GetAllGalleries(c *fiber.Ctx) error {
+	q, err := query.Parse(c, galleryListSpec)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal mengambil galeri"})
+		return utils.Error(c, err)
 	}
-	defer cursor.Close(ctx)
 
-	var galleries []models.Gallery
-	if err := cursor.All(ctx, &galleries); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal decode data galeri"})
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	galleries, meta, err := query.Find[models.Gallery](ctx, galleryCollection, q)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("gallery.fetch_failed", nil).Wrap(err))
 	}
 
-	return c.JSON(galleries)
+	query.SetLinks(c, q, meta)
+	return utils.SuccessList(c, galleries, meta)
 }
 
 func GetGalleryByID(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := primitive.ObjectIDFromHex(idParam)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID tidak valid"})
+		return utils.Error(c, utils.ErrInvalidID)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
 	var gallery models.Gallery
 	err = galleryCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&gallery)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Galeri tidak ditemukan"})
+		return utils.Error(c, utils.NotFoundOr(err, utils.ErrGalleryNotFound))
 	}
 
-	return c.JSON(gallery)
+	utils.SetETag(c, gallery.Version)
+	return utils.Success(c, fiber.StatusOK, gallery)
 }
 
 func CreateGallery(c *fiber.Ctx) error {
 	var gallery models.Gallery
 
 	if err := c.BodyParser(&gallery); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format data salah"})
+		return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
 	}
 
-	if gallery.PhotographerID.IsZero() || gallery.Title == "" || gallery.ImageURL == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Photographer ID, Title, dan ImageURL wajib diisi"})
+	if err := utils.Validate(gallery); err != nil {
+		return utils.Error(c, err)
 	}
 
 	gallery.ID = primitive.NewObjectID()
 	gallery.CreatedAt = time.Now()
 	gallery.UpdatedAt = gallery.CreatedAt
+	gallery.Version = 1
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
+	if err := integrity.Exists(ctx, galleryRefs(gallery)...); err != nil {
+		return utils.Error(c, err)
+	}
+
 	_, err := galleryCollection.InsertOne(ctx, gallery)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal menyimpan galeri"})
+		return utils.Error(c, utils.ErrDatabase.WithKey("gallery.save_failed", nil).Wrap(err))
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(gallery)
-}
+	publishGallery(ctx, gallery)
 
+	utils.SetETag(c, gallery.Version)
+	return utils.Success(c, fiber.StatusCreated, gallery)
+}
 
 func UpdateGallery(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := primitive.ObjectIDFromHex(idParam)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID tidak valid"})
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+
+	// Update hanya berlaku untuk versi yang terakhir dibaca client
+	version, err := utils.IfMatch(c)
+	if err != nil {
+		return utils.Error(c, err)
 	}
 
 	var updated models.Gallery
 	if err := c.BodyParser(&updated); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format data salah"})
+		return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
+	}
+
+	// PUT mengganti seluruh field, jadi divalidasi seperti create
+	if err := utils.Validate(updated); err != nil {
+		return utils.Error(c, err)
 	}
 
 	updated.UpdatedAt = time.Now()
@@ -95,6 +150,7 @@ func UpdateGallery(c *fiber.Ctx) error {
 	update := bson.M{
 		"$set": bson.M{
 			"photographer_id": updated.PhotographerID,
+			"booking_id":      updated.BookingID,
 			"title":           updated.Title,
 			"image_url":       updated.ImageURL,
 			"description":     updated.Description,
@@ -102,37 +158,41 @@ func UpdateGallery(c *fiber.Ctx) error {
 		},
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
-	result, err := galleryCollection.UpdateOne(ctx, bson.M{"_id": id}, update)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal update galeri"})
+	if err := integrity.Exists(ctx, galleryRefs(updated)...); err != nil {
+		return utils.Error(c, err)
 	}
-	if result.MatchedCount == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Galeri tidak ditemukan"})
+
+	var gallery models.Gallery
+	if err := updateVersioned(ctx, c, galleryCollection, id, version, update, &gallery,
+		utils.ErrGalleryNotFound, nil, utils.ErrDatabase.WithKey("gallery.update_failed", nil)); err != nil {
+		return utils.Error(c, err)
 	}
 
-	return c.JSON(fiber.Map{"message": "Galeri berhasil diperbarui"})
+	return utils.SuccessMessage(c, fiber.StatusOK, "gallery.updated", gallery)
+}
+
+// PatchGallery mengubah sebagian field galeri (JSON Merge Patch), field lain tetap
+func PatchGallery(c *fiber.Ctx) error {
+	return patchDocument(c, galleryPatch)
 }
 
 func DeleteGallery(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := primitive.ObjectIDFromHex(idParam)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID tidak valid"})
+		return utils.Error(c, utils.ErrInvalidID)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
-	result, err := galleryCollection.DeleteOne(ctx, bson.M{"_id": id})
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal hapus galeri"})
-	}
-	if result.DeletedCount == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Galeri tidak ditemukan"})
+	// Galeri masuk trash dan masih bisa dipulihkan sampai masa retensi habis
+	if err := integrity.Delete(ctx, "galleries", id, utils.ErrGalleryNotFound); err != nil {
+		return utils.Error(c, err)
 	}
 
-	return c.JSON(fiber.Map{"message": "Galeri berhasil dihapus"})
+	return utils.SuccessMessage(c, fiber.StatusOK, "gallery.deleted", nil)
 }
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/query"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/scheduler"
+	"manajemen-fotografi-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var jobCollection = repositories.New(scheduler.Collection)
+
+// jobListSpec adalah whitelist filter dan sort untuk GET /api/admin/jobs
+var jobListSpec = query.Spec{
+	Filters: map[string]query.Filter{
+		"name":     {Field: "name", Type: query.String, Op: query.Eq},
+		"status":   {Field: "status", Type: query.String, Op: query.Eq},
+		"key":      {Field: "key", Type: query.String, Op: query.Prefix},
+		"run_from": {Field: "run_at", Type: query.Time, Op: query.Gte},
+		"run_to":   {Field: "run_at", Type: query.Time, Op: query.Lte},
+	},
+	Sorts:       []string{"run_at", "created_at"},
+	DefaultSort: "run_at",
+}
+
+// GetJobs menampilkan status job background: jadwal, percobaan, error terakhir dan
+// replica yang sedang menjalankannya
+func GetJobs(c *fiber.Ctx) error {
+	q, err := query.Parse(c, jobListSpec)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	jobs, meta, err := query.Find[models.Job](ctx, jobCollection, q)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("job.fetch_failed", nil).Wrap(err))
+	}
+
+	query.SetLinks(c, q, meta)
+	return utils.SuccessList(c, jobs, meta)
+}
+
+// RetryJob menjadwalkan ulang job yang gagal untuk dijalankan secepatnya dengan jatah
+// percobaan baru
+func RetryJob(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var job models.Job
+	err = jobCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "status": models.JobFailed},
+		bson.M{"$set": bson.M{
+			"status":       models.JobScheduled,
+			"run_at":       time.Now(),
+			"attempts":     0,
+			"locked_until": nil,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&job)
+	if err != nil {
+		return utils.Error(c, utils.NotFoundOr(err, utils.ErrJobNotFound))
+	}
+
+	return utils.SuccessMessage(c, fiber.StatusOK, "job.requeued", job)
+}
@@ -0,0 +1,397 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"manajemen-fotografi-api/auth"
+	"manajemen-fotografi-api/ledger"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/query"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	journalCollection = repositories.New(ledger.JournalCollection)
+	payoutCollection  = repositories.New(ledger.PayoutCollection)
+)
+
+// journalListSpec adalah whitelist filter dan sort untuk GET /api/photographer/:id/ledger/journals
+var journalListSpec = query.Spec{
+	Filters: map[string]query.Filter{
+		"kind":           {Field: "kind", Type: query.String, Op: query.Eq},
+		"transaction_id": {Field: "transaction_id", Type: query.ObjectID, Op: query.Eq},
+		"payout_id":      {Field: "payout_id", Type: query.ObjectID, Op: query.Eq},
+		"created_from":   {Field: "created_at", Type: query.Time, Op: query.Gte},
+		"created_to":     {Field: "created_at", Type: query.Time, Op: query.Lte},
+	},
+	Sorts:       []string{"created_at"},
+	DefaultSort: "-created_at",
+}
+
+// payoutListSpec adalah whitelist filter dan sort untuk GET /api/admin/payouts
+var payoutListSpec = query.Spec{
+	Filters: map[string]query.Filter{
+		"status":       {Field: "status", Type: query.String, Op: query.Eq},
+		"created_from": {Field: "created_at", Type: query.Time, Op: query.Gte},
+		"created_to":   {Field: "created_at", Type: query.Time, Op: query.Lte},
+	},
+	Sorts:       []string{"created_at", "total"},
+	DefaultSort: "-created_at",
+}
+
+// refundInput adalah body POST /api/admin/ledger/refunds, amount dalam rupiah
+type refundInput struct {
+	TransactionID primitive.ObjectID `json:"transaction_id" validate:"objectid"`
+	Amount        int64              `json:"amount" validate:"required,gt=0"`
+	Memo          string             `json:"memo" validate:"max=500"`
+}
+
+// adjustmentInput adalah body POST /api/admin/ledger/adjustments. Amount positif menambah
+// saldo fotografer, negatif menguranginya.
+type adjustmentInput struct {
+	PhotographerID primitive.ObjectID `json:"photographer_id" validate:"objectid"`
+	Amount         int64              `json:"amount" validate:"required"`
+	Memo           string             `json:"memo" validate:"required,notblank,max=500"`
+}
+
+// rateInput adalah body pengaturan tarif komisi, 0.2 berarti 20%
+type rateInput struct {
+	Rate *float64 `json:"rate" validate:"required,min=0,max=1"`
+}
+
+// payoutInput adalah body POST /api/admin/payouts; tanpa cutoff semua jurnal sampai saat ini ikut
+type payoutInput struct {
+	Cutoff *time.Time `json:"cutoff"`
+}
+
+// actorID mengembalikan ID user yang login untuk dicatat di jurnal
+func actorID(ctx context.Context) *primitive.ObjectID {
+	actor, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return &actor.UserID
+}
+
+// GetPhotographerLedger menampilkan saldo fotografer dan ringkasan pendapatan pada rentang
+// ?from dan ?to (default 30 hari terakhir), hanya untuk fotografer tersebut dan admin
+func GetPhotographerLedger(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+	p, err := analyticsParams(c)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	if _, err := ownedPhotographer(ctx, id); err != nil {
+		return utils.Error(c, err)
+	}
+
+	statement, err := ledger.StatementFor(ctx, id, p.From, p.To)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("ledger.fetch_failed", nil).Wrap(err))
+	}
+	return utils.Success(c, fiber.StatusOK, statement)
+}
+
+// GetPhotographerJournals menampilkan rincian jurnal fotografer
+func GetPhotographerJournals(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+	q, err := query.Parse(c, journalListSpec)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	if _, err := ownedPhotographer(ctx, id); err != nil {
+		return utils.Error(c, err)
+	}
+
+	q.Filter["photographer_id"] = id
+	journals, meta, err := query.Find[models.LedgerJournal](ctx, journalCollection, q)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("ledger.fetch_failed", nil).Wrap(err))
+	}
+
+	query.SetLinks(c, q, meta)
+	return utils.SuccessList(c, journals, meta)
+}
+
+// CreateRefund mencatat refund sebagian atau penuh atas transaksi yang sudah lunas
+func CreateRefund(c *fiber.Ctx) error {
+	var input refundInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
+	}
+	if err := utils.Validate(input); err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	journal, err := ledger.Refund(ctx, input.TransactionID, input.Amount, input.Memo, actorID(ctx))
+	if err != nil {
+		return utils.Error(c, ledgerError(err))
+	}
+	return utils.SuccessMessage(c, fiber.StatusCreated, "ledger.refund_created", journal)
+}
+
+// CreateAdjustment mencatat koreksi manual saldo fotografer
+func CreateAdjustment(c *fiber.Ctx) error {
+	var input adjustmentInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
+	}
+	if err := utils.Validate(input); err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	if err := photographerCollection.FindOne(ctx, bson.M{"_id": input.PhotographerID}).Err(); err != nil {
+		return utils.Error(c, utils.NotFoundOr(err, utils.ErrPhotographerNotFound))
+	}
+
+	journal, err := ledger.Adjust(ctx, input.PhotographerID, input.Amount, input.Memo, actorID(ctx))
+	if err != nil {
+		return utils.Error(c, ledgerError(err))
+	}
+	return utils.SuccessMessage(c, fiber.StatusCreated, "ledger.adjustment_created", journal)
+}
+
+// ledgerError meneruskan error bisnis dari buku besar, error lain dianggap kegagalan database
+func ledgerError(err error) error {
+	var appErr *utils.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return utils.ErrDatabase.WithKey("ledger.save_failed", nil).Wrap(err)
+}
+
+// GetCommissionRates menampilkan tarif komisi default dan per fotografer. Tarif default
+// dari PLATFORM_COMMISSION_RATE ikut ditampilkan sebagai fallback.
+func GetCommissionRates(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	rates, err := ledger.Rates(ctx)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("commission.fetch_failed", nil).Wrap(err))
+	}
+	return utils.Success(c, fiber.StatusOK, fiber.Map{
+		"fallback_rate": ledger.EnvRate(),
+		"rates":         rates,
+	})
+}
+
+// SetDefaultCommissionRate mengatur tarif komisi untuk semua fotografer tanpa tarif khusus
+func SetDefaultCommissionRate(c *fiber.Ctx) error {
+	return setCommissionRate(c, nil)
+}
+
+// SetPhotographerCommissionRate mengatur tarif komisi khusus satu fotografer
+func SetPhotographerCommissionRate(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+	return setCommissionRate(c, &id)
+}
+
+func setCommissionRate(c *fiber.Ctx, photographerID *primitive.ObjectID) error {
+	var input rateInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
+	}
+	if err := utils.Validate(input); err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	if photographerID != nil {
+		if err := photographerCollection.FindOne(ctx, bson.M{"_id": *photographerID}).Err(); err != nil {
+			return utils.Error(c, utils.NotFoundOr(err, utils.ErrPhotographerNotFound))
+		}
+	}
+
+	rate, err := ledger.SetRate(ctx, photographerID, *input.Rate, actorID(ctx))
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("commission.save_failed", nil).Wrap(err))
+	}
+	return utils.SuccessMessage(c, fiber.StatusOK, "commission.saved", rate)
+}
+
+// DeletePhotographerCommissionRate mengembalikan fotografer ke tarif default
+func DeletePhotographerCommissionRate(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	deleted, err := ledger.DeleteRate(ctx, id)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("commission.save_failed", nil).Wrap(err))
+	}
+	if !deleted {
+		return utils.Error(c, utils.ErrCommissionRateNotFound)
+	}
+	return utils.SuccessMessage(c, fiber.StatusOK, "commission.deleted", nil)
+}
+
+// CreatePayoutBatch membuat batch payout untuk semua saldo fotografer sampai cutoff
+func CreatePayoutBatch(c *fiber.Ctx) error {
+	var input payoutInput
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
+		}
+	}
+	cutoff := time.Now()
+	if input.Cutoff != nil {
+		if input.Cutoff.After(cutoff) {
+			return utils.Error(c, utils.Invalid("cutoff", "payout.future_cutoff"))
+		}
+		cutoff = *input.Cutoff
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 60*time.Second)
+	defer cancel()
+
+	batch, err := ledger.GeneratePayouts(ctx, cutoff, actorID(ctx))
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("payout.save_failed", nil).Wrap(err))
+	}
+	return utils.SuccessMessage(c, fiber.StatusCreated, "payout.created", batch)
+}
+
+// GetPayoutBatches menampilkan riwayat batch payout
+func GetPayoutBatches(c *fiber.Ctx) error {
+	q, err := query.Parse(c, payoutListSpec)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	batches, meta, err := query.Find[models.PayoutBatch](ctx, payoutCollection, q)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("payout.fetch_failed", nil).Wrap(err))
+	}
+
+	query.SetLinks(c, q, meta)
+	return utils.SuccessList(c, batches, meta)
+}
+
+// GetPayoutBatch menampilkan satu batch payout
+func GetPayoutBatch(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	batch, err := findPayoutBatch(ctx, c.Params("id"))
+	if err != nil {
+		return utils.Error(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, batch)
+}
+
+// ExportPayoutBatch mengunduh batch payout sebagai CSV untuk diunggah ke bank
+func ExportPayoutBatch(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 30*time.Second)
+	defer cancel()
+
+	batch, err := findPayoutBatch(ctx, c.Params("id"))
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	payees, err := payoutPayees(ctx, batch)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("payout.fetch_failed", nil).Wrap(err))
+	}
+
+	var buf bytes.Buffer
+	if err := ledger.WriteCSV(&buf, batch, payees); err != nil {
+		return utils.Error(c, utils.ErrInternal.Wrap(err))
+	}
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="payout-`+batch.ID.Hex()+`.csv"`)
+	return c.Send(buf.Bytes())
+}
+
+func findPayoutBatch(ctx context.Context, hex string) (models.PayoutBatch, error) {
+	var batch models.PayoutBatch
+	id, err := primitive.ObjectIDFromHex(hex)
+	if err != nil {
+		return batch, utils.ErrInvalidID
+	}
+	if err := payoutCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&batch); err != nil {
+		return batch, utils.NotFoundOr(err, utils.ErrPayoutNotFound)
+	}
+	return batch, nil
+}
+
+// payoutPayees mengambil nama dan nomor telepon fotografer dalam batch, termasuk fotografer
+// yang sudah dihapus karena saldonya tetap harus dibayar
+func payoutPayees(ctx context.Context, batch models.PayoutBatch) (map[primitive.ObjectID]ledger.Payee, error) {
+	ids := make(bson.A, 0, len(batch.Items))
+	for _, item := range batch.Items {
+		ids = append(ids, item.PhotographerID)
+	}
+	cursor, err := repositories.Unscoped("photographers").Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	var photographers []models.Photographer
+	if err := cursor.All(ctx, &photographers); err != nil {
+		return nil, err
+	}
+
+	userIDs := make(bson.A, 0, len(photographers))
+	for _, p := range photographers {
+		userIDs = append(userIDs, p.UserID)
+	}
+	cursor, err = userCollection.Find(ctx, bson.M{"_id": bson.M{"$in": userIDs}})
+	if err != nil {
+		return nil, err
+	}
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	names := make(map[primitive.ObjectID]string, len(users))
+	for _, u := range users {
+		names[u.ID] = u.Name
+	}
+
+	payees := make(map[primitive.ObjectID]ledger.Payee, len(photographers))
+	for _, p := range photographers {
+		payees[p.ID] = ledger.Payee{Name: names[p.UserID], Phone: p.Phone}
+	}
+	return payees, nil
+}
@@ -0,0 +1,382 @@
+package handlers
+
+import (
+	"context"
+	"mime/multipart"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"manajemen-fotografi-api/auth"
+	"manajemen-fotografi-api/events"
+	"manajemen-fotografi-api/logging"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/query"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/storage"
+	"manajemen-fotografi-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Batas lampiran per pesan
+const (
+	maxMessageAttachments   = 5
+	maxMessageAttachmentLen = 5 << 20
+)
+
+var messageCollection = repositories.New("messages")
+
+// messageImageTypes adalah jenis gambar yang boleh dilampirkan, dicek dari isi file
+var messageImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+	"image/gif":  true,
+}
+
+// messageListSpec mengatur urutan dan pagination GET /api/bookings/:id/messages.
+// Default dari yang terbaru, halaman berikutnya lewat ?cursor.
+var messageListSpec = query.Spec{
+	Filters: map[string]query.Filter{
+		"sender_id": {Field: "sender_id", Type: query.ObjectID, Op: query.Eq},
+		"from":      {Field: "created_at", Type: query.Time, Op: query.Gte},
+	},
+	Sorts:       []string{"created_at"},
+	DefaultSort: "-created_at",
+}
+
+// bookingThread membaca booking dan memastikan yang login adalah client atau fotografernya.
+// Admin pun tidak bisa membaca thread. Mengembalikan booking dan user ID para peserta.
+func bookingThread(ctx context.Context, id primitive.ObjectID) (models.Booking, []primitive.ObjectID, error) {
+	var booking models.Booking
+	if err := bookingHandlerCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&booking); err != nil {
+		return booking, nil, utils.NotFoundOr(err, utils.ErrBookingNotFound)
+	}
+	actor, ok := auth.FromContext(ctx)
+	if !ok {
+		return booking, nil, utils.ErrUnauthenticated
+	}
+	participants := bookingRecipients(ctx, booking)
+	if !slices.Contains(participants, actor.UserID) {
+		return booking, nil, utils.ErrForbidden
+	}
+	return booking, participants, nil
+}
+
+// others adalah peserta thread selain user yang login
+func others(participants []primitive.ObjectID, self primitive.ObjectID) []primitive.ObjectID {
+	var out []primitive.ObjectID
+	for _, id := range participants {
+		if id != self {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// GetBookingMessages menampilkan pesan di thread booking, terbaru lebih dulu
+func GetBookingMessages(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+	q, err := query.Parse(c, messageListSpec)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	if _, _, err := bookingThread(ctx, id); err != nil {
+		return utils.Error(c, err)
+	}
+
+	q.Filter["booking_id"] = id
+	messages, meta, err := query.Find[models.Message](ctx, messageCollection, q)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("message.fetch_failed", nil).Wrap(err))
+	}
+
+	query.SetLinks(c, q, meta)
+	return utils.SuccessList(c, messages, meta)
+}
+
+// CreateBookingMessage mengirim pesan ke thread booking. Body berupa JSON {"text": ...}
+// atau multipart dengan field text dan file gambar "attachments".
+func CreateBookingMessage(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+
+	// Hanya teks yang dibaca dari body; lampiran selalu berasal dari upload
+	var input struct {
+		Text string `json:"text"`
+	}
+	var files []*multipart.FileHeader
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		form, err := c.MultipartForm()
+		if err != nil {
+			return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
+		}
+		input.Text = c.FormValue("text")
+		files = form.File["attachments"]
+	} else if err := c.BodyParser(&input); err != nil {
+		return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
+	}
+
+	message := models.Message{Text: strings.TrimSpace(input.Text)}
+	if message.Text == "" && len(files) == 0 {
+		return utils.Error(c, utils.Invalid("text", "message.empty"))
+	}
+	if err := utils.ValidateFields(message, "Text"); err != nil {
+		return utils.Error(c, err)
+	}
+	if err := checkMessageAttachments(files); err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 30*time.Second)
+	defer cancel()
+
+	// Akses dicek sebelum upload supaya file tidak tersimpan untuk pesan yang pasti ditolak
+	_, participants, err := bookingThread(ctx, id)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+	actor, _ := auth.FromContext(ctx)
+
+	for _, file := range files {
+		url, err := storage.Default.Save(ctx, "message_attachment", file)
+		if err != nil {
+			deleteAttachments(ctx, message.Attachments)
+			return utils.Error(c, utils.ErrUpload.WithKey("message.attachment_upload_failed", nil).Wrap(err))
+		}
+		message.Attachments = append(message.Attachments, models.MessageAttachment{
+			URL:         url,
+			Name:        file.Filename,
+			ContentType: file.Header.Get(fiber.HeaderContentType),
+			Size:        file.Size,
+		})
+	}
+
+	message.ID = primitive.NewObjectID()
+	message.BookingID = id
+	message.SenderID = actor.UserID
+	message.SenderRole = actor.Role
+	message.ReadBy = []models.MessageRead{}
+	message.CreatedAt = time.Now()
+
+	if _, err := messageCollection.InsertOne(ctx, message); err != nil {
+		deleteAttachments(ctx, message.Attachments)
+		return utils.Error(c, utils.ErrDatabase.WithKey("message.save_failed", nil).Wrap(err))
+	}
+
+	events.Publish(ctx, events.MessageCreated, others(participants, actor.UserID), map[string]interface{}{"message": message})
+
+	return utils.Success(c, fiber.StatusCreated, message)
+}
+
+// checkMessageAttachments membatasi jumlah, ukuran dan jenis lampiran. Jenis dibaca dari
+// isi file, bukan dari Content-Type yang dikirim client.
+func checkMessageAttachments(files []*multipart.FileHeader) error {
+	if len(files) > maxMessageAttachments {
+		return utils.InvalidWith("attachments", "validation.max", map[string]string{
+			"field": "attachments",
+			"param": strconv.Itoa(maxMessageAttachments),
+		})
+	}
+	for _, file := range files {
+		if file.Size > maxMessageAttachmentLen {
+			return utils.InvalidWith("attachments", "message.attachment_too_large", map[string]string{
+				"field": "attachments",
+				"param": "5MB",
+			})
+		}
+		src, err := file.Open()
+		if err != nil {
+			return utils.ErrUpload.Wrap(err)
+		}
+		head := make([]byte, 512)
+		n, _ := src.Read(head)
+		src.Close()
+
+		contentType := http.DetectContentType(head[:n])
+		if !messageImageTypes[contentType] {
+			return utils.Invalid("attachments", "message.image_only")
+		}
+		file.Header.Set(fiber.HeaderContentType, contentType)
+	}
+	return nil
+}
+
+// deleteAttachments membersihkan file yang sudah terupload saat pesan gagal disimpan
+func deleteAttachments(ctx context.Context, attachments []models.MessageAttachment) {
+	for _, a := range attachments {
+		if err := storage.Default.Delete(ctx, a.URL); err != nil {
+			logging.FromContext(ctx).WarnContext(ctx, "gagal menghapus lampiran pesan", "url", a.URL, "error", err)
+		}
+	}
+}
+
+// MarkBookingMessagesRead menandai semua pesan dari peserta lain di thread sebagai sudah dibaca
+func MarkBookingMessagesRead(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	_, participants, err := bookingThread(ctx, id)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+	actor, _ := auth.FromContext(ctx)
+
+	now := time.Now()
+	res, err := messageCollection.UpdateMany(ctx, bson.M{
+		"booking_id":      id,
+		"sender_id":       bson.M{"$ne": actor.UserID},
+		"read_by.user_id": bson.M{"$ne": actor.UserID},
+	}, bson.M{"$push": bson.M{"read_by": models.MessageRead{UserID: actor.UserID, At: now}}})
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("message.save_failed", nil).Wrap(err))
+	}
+
+	// Pengirim melihat read receipt secara realtime
+	if res.ModifiedCount > 0 {
+		events.Publish(ctx, events.MessagesRead, others(participants, actor.UserID), map[string]interface{}{
+			"booking_id": id,
+			"user_id":    actor.UserID,
+			"at":         now,
+		})
+	}
+
+	return utils.Success(c, fiber.StatusOK, fiber.Map{"read": res.ModifiedCount})
+}
+
+// unreadThread adalah jumlah pesan belum dibaca pada satu booking
+type unreadThread struct {
+	BookingID     primitive.ObjectID `bson:"_id" json:"booking_id"`
+	Unread        int64              `bson:"unread" json:"unread"`
+	LastMessageAt time.Time          `bson:"last_message_at" json:"last_message_at"`
+}
+
+// GetUnreadMessages menghitung pesan belum dibaca per booking untuk user yang login,
+// baik sebagai client maupun fotografer
+func GetUnreadMessages(c *fiber.Ctx) error {
+	actor, ok := auth.FromContext(c.UserContext())
+	if !ok {
+		return utils.Error(c, utils.ErrUnauthenticated)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	bookingIDs, err := participantBookings(ctx, actor.UserID)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("message.fetch_failed", nil).Wrap(err))
+	}
+
+	threads := []unreadThread{}
+	var total int64
+	if len(bookingIDs) > 0 {
+		cursor, err := messageCollection.Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{
+				"booking_id":      bson.M{"$in": bookingIDs},
+				"sender_id":       bson.M{"$ne": actor.UserID},
+				"read_by.user_id": bson.M{"$ne": actor.UserID},
+			}}},
+			{{Key: "$group", Value: bson.M{
+				"_id":             "$booking_id",
+				"unread":          bson.M{"$sum": 1},
+				"last_message_at": bson.M{"$max": "$created_at"},
+			}}},
+			{{Key: "$sort", Value: bson.M{"last_message_at": -1}}},
+		})
+		if err != nil {
+			return utils.Error(c, utils.ErrDatabase.WithKey("message.fetch_failed", nil).Wrap(err))
+		}
+		if err := cursor.All(ctx, &threads); err != nil {
+			return utils.Error(c, utils.ErrDatabase.WithKey("message.fetch_failed", nil).Wrap(err))
+		}
+		for _, t := range threads {
+			total += t.Unread
+		}
+	}
+
+	return utils.Success(c, fiber.StatusOK, fiber.Map{"total": total, "bookings": threads})
+}
+
+// participantBookings adalah ID booking aktif tempat user menjadi client atau fotografer
+func participantBookings(ctx context.Context, userID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	var or bson.A
+	for field, coll := range map[string]repositories.Collection{
+		"client_id":       clientCollection,
+		"photographer_id": photographerCollection,
+	} {
+		ids, err := idsOf(ctx, coll, bson.M{"user_id": userID})
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) > 0 {
+			or = append(or, bson.M{field: bson.M{"$in": ids}})
+		}
+	}
+	if len(or) == 0 {
+		return nil, nil
+	}
+	return idsOf(ctx, bookingHandlerCollection, bson.M{"$or": or})
+}
+
+// idsOf mengambil _id semua dokumen yang cocok dengan filter
+func idsOf(ctx context.Context, coll repositories.Collection, filter bson.M) ([]primitive.ObjectID, error) {
+	cursor, err := coll.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, 0, len(docs))
+	for _, d := range docs {
+		ids = append(ids, d.ID)
+	}
+	return ids, nil
+}
+
+// seedNoteMessage menyalin catatan booking sebagai pesan pertama thread atas nama client.
+// Booking sudah tersimpan, jadi kegagalan hanya dicatat ke log.
+func seedNoteMessage(ctx context.Context, booking models.Booking) {
+	if strings.TrimSpace(booking.Note) == "" {
+		return
+	}
+	sender, _ := profileUserID(ctx, clientCollection, booking.ClientID)
+	_, err := messageCollection.InsertOne(ctx, models.Message{
+		ID:         primitive.NewObjectID(),
+		BookingID:  booking.ID,
+		SenderID:   sender,
+		SenderRole: models.RoleClient,
+		Text:       booking.Note,
+		FromNote:   true,
+		ReadBy:     []models.MessageRead{},
+		CreatedAt:  booking.CreatedAt,
+	})
+	if err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "gagal membuat pesan dari catatan booking",
+			"booking_id", booking.ID.Hex(), "error", err)
+	}
+}
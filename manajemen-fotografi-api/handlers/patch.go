@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"manajemen-fotografi-api/patch"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// patchResource berisi aturan PATCH (JSON Merge Patch) untuk satu jenis dokumen
+type patchResource[T versioned] struct {
+	coll      repositories.Collection
+	fields    []string // field JSON yang boleh diubah lewat PATCH
+	notFound  *utils.AppError
+	duplicate *utils.AppError // nil jika tidak ada index unik yang bisa dilanggar
+	failed    *utils.AppError
+	touch     func(doc *T)                                      // mengisi updated_at dan menyeragamkan field, setelah validasi
+	check     func(ctx context.Context, before T, doc *T) error // pemeriksaan tambahan setelah merge, boleh nil
+	saved     func(ctx context.Context, before, after T)        // dipanggil setelah tersimpan, misal kirim event, boleh nil
+}
+
+// patchDocument membaca dokumen, menerapkan merge patch dari body lalu menyimpan hanya
+// field yang dikirim. Dokumen hasil merge divalidasi utuh, sehingga null pada field wajib
+// ditolak. Update memakai versi dokumen yang dibaca, jadi perubahan lain di antara baca
+// dan tulis tetap dijawab 412 walau If-Match berisi *.
+func patchDocument[T versioned](c *fiber.Ctx, r patchResource[T]) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+
+	version, err := utils.IfMatch(c)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var doc T
+	if err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&doc); err != nil {
+		return utils.Error(c, utils.NotFoundOr(err, r.notFound))
+	}
+	current := doc.CurrentVersion()
+	if version != utils.AnyVersion && version != current {
+		return utils.Error(c, utils.ErrPreconditionFailed)
+	}
+
+	before := doc
+	fields, err := patch.Apply(&doc, c.Body(), r.fields...)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+	if err := utils.Validate(doc); err != nil {
+		return utils.Error(c, err)
+	}
+	if r.check != nil {
+		if err := r.check(ctx, before, &doc); err != nil {
+			return utils.Error(c, err)
+		}
+	}
+
+	r.touch(&doc)
+	update, err := patch.Update(doc, append(fields, "updated_at")...)
+	if err != nil {
+		return utils.Error(c, utils.ErrInternal.Wrap(err))
+	}
+
+	var updated T
+	if err := updateVersioned(ctx, c, r.coll, id, current, update, &updated,
+		r.notFound, r.duplicate, r.failed); err != nil {
+		return utils.Error(c, err)
+	}
+	if r.saved != nil {
+		r.saved(ctx, before, updated)
+	}
+
+	return utils.Success(c, fiber.StatusOK, updated)
+}
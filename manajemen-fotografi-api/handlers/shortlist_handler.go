@@ -0,0 +1,391 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"manajemen-fotografi-api/integrity"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/query"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/shortlist"
+	"manajemen-fotografi-api/utils"
+	"manajemen-fotografi-api/waitlist"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var shortlistCollection = repositories.New(shortlist.Collection)
+
+// shortlistListSpec adalah whitelist sort untuk daftar shortlist client
+var shortlistListSpec = query.Spec{
+	Sorts:       []string{"created_at", "updated_at", "name"},
+	DefaultSort: "-updated_at",
+}
+
+// shortlistInput adalah body POST dan PUT /api/shortlists
+type shortlistInput struct {
+	Name string `json:"name" validate:"required,notblank,max=100"`
+}
+
+// shortlistItemInput adalah body POST /api/shortlists/:id/items. photographer_id wajib untuk
+// kind photographer dan gallery_id wajib untuk kind gallery_image.
+type shortlistItemInput struct {
+	Kind           string              `json:"kind" validate:"required,oneof=photographer gallery_image"`
+	PhotographerID *primitive.ObjectID `json:"photographer_id"`
+	GalleryID      *primitive.ObjectID `json:"gallery_id"`
+	Note           string              `json:"note" validate:"max=300"`
+}
+
+// availabilityDate membaca ?date=YYYY-MM-DD (WIB), nil jika tidak dikirim
+func availabilityDate(c *fiber.Ctx) (*time.Time, error) {
+	raw := c.Query("date")
+	if raw == "" {
+		return nil, nil
+	}
+	date, err := time.ParseInLocation(time.DateOnly, raw, waitlist.Location)
+	if err != nil {
+		return nil, utils.Invalid("date", "query.invalid_date")
+	}
+	return &date, nil
+}
+
+// ownedShortlist mengambil shortlist milik client
+func ownedShortlist(ctx context.Context, id, clientID primitive.ObjectID) (models.Shortlist, error) {
+	var list models.Shortlist
+	err := shortlistCollection.FindOne(ctx, bson.M{"_id": id, "client_id": clientID}).Decode(&list)
+	if err != nil {
+		return list, utils.NotFoundOr(err, utils.ErrShortlistNotFound)
+	}
+	list.Shared = list.ShareTokenHash != ""
+	return list, nil
+}
+
+// CreateShortlist membuat shortlist kosong untuk client yang login
+func CreateShortlist(c *fiber.Ctx) error {
+	var input shortlistInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
+	}
+	if err := utils.Validate(input); err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	client, err := currentClient(ctx)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	now := time.Now()
+	list := models.Shortlist{
+		ID:        primitive.NewObjectID(),
+		ClientID:  client.ID,
+		Name:      strings.TrimSpace(input.Name),
+		Items:     []models.ShortlistItem{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if _, err := shortlistCollection.InsertOne(ctx, list); err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("shortlist.save_failed", nil).Wrap(err))
+	}
+	return utils.SuccessMessage(c, fiber.StatusCreated, "shortlist.created", list)
+}
+
+// GetMyShortlists menampilkan shortlist milik client yang login
+func GetMyShortlists(c *fiber.Ctx) error {
+	q, err := query.Parse(c, shortlistListSpec)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	client, err := currentClient(ctx)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	q.Filter["client_id"] = client.ID
+	lists, meta, err := query.Find[models.Shortlist](ctx, shortlistCollection, q)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("shortlist.fetch_failed", nil).Wrap(err))
+	}
+	for i := range lists {
+		lists[i].Shared = lists[i].ShareTokenHash != ""
+	}
+
+	query.SetLinks(c, q, meta)
+	return utils.SuccessList(c, lists, meta)
+}
+
+// GetShortlist menampilkan satu shortlist milik client. Dengan ?date=YYYY-MM-DD setiap item
+// diberi tanda available sesuai jadwal fotografernya pada tanggal tersebut.
+func GetShortlist(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+	date, err := availabilityDate(c)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	client, err := currentClient(ctx)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+	list, err := ownedShortlist(ctx, id, client.ID)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+	if date != nil {
+		if err := shortlist.Availability(ctx, list.Items, waitlist.Day(*date)); err != nil {
+			return utils.Error(c, utils.ErrDatabase.WithKey("shortlist.fetch_failed", nil).Wrap(err))
+		}
+	}
+	return utils.Success(c, fiber.StatusOK, list)
+}
+
+// UpdateShortlist mengganti nama shortlist
+func UpdateShortlist(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+	var input shortlistInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
+	}
+	if err := utils.Validate(input); err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	client, err := currentClient(ctx)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	var list models.Shortlist
+	err = shortlistCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "client_id": client.ID},
+		bson.M{"$set": bson.M{"name": strings.TrimSpace(input.Name), "updated_at": time.Now()}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&list)
+	if err != nil {
+		return utils.Error(c, utils.NotFoundOr(err, utils.ErrShortlistNotFound))
+	}
+	list.Shared = list.ShareTokenHash != ""
+	return utils.SuccessMessage(c, fiber.StatusOK, "shortlist.updated", list)
+}
+
+// DeleteShortlist menghapus shortlist beserta link berbaginya
+func DeleteShortlist(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	client, err := currentClient(ctx)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+	if err := shortlist.Delete(ctx, id, client.ID); err != nil {
+		return utils.Error(c, err)
+	}
+	return utils.SuccessMessage(c, fiber.StatusOK, "shortlist.deleted", nil)
+}
+
+// AddShortlistItem menyimpan fotografer atau satu gambar galeri ke shortlist.
+// Untuk gambar galeri, URL gambar dan fotografernya disalin dari galeri saat ini.
+func AddShortlistItem(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+	var input shortlistItemInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
+	}
+	if err := utils.Validate(input); err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	client, err := currentClient(ctx)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	item := models.ShortlistItem{Kind: input.Kind, Note: strings.TrimSpace(input.Note)}
+	switch input.Kind {
+	case models.ShortlistPhotographer:
+		if input.PhotographerID == nil {
+			return utils.Error(c, utils.Invalid("photographer_id", "shortlist.target_required"))
+		}
+		if err := integrity.Exists(ctx, integrity.Photographer("photographer_id", *input.PhotographerID)); err != nil {
+			return utils.Error(c, err)
+		}
+		item.PhotographerID = *input.PhotographerID
+	case models.ShortlistGalleryImage:
+		if input.GalleryID == nil {
+			return utils.Error(c, utils.Invalid("gallery_id", "shortlist.target_required"))
+		}
+		var gallery models.Gallery
+		if err := galleryCollection.FindOne(ctx, bson.M{"_id": *input.GalleryID}).Decode(&gallery); err != nil {
+			return utils.Error(c, utils.NotFoundOr(err, utils.Invalid("gallery_id", "integrity.not_found")))
+		}
+		item.GalleryID = &gallery.ID
+		item.PhotographerID = gallery.PhotographerID
+		item.ImageURL = gallery.ImageURL
+	}
+
+	list, err := shortlist.AddItem(ctx, id, client.ID, item, time.Now())
+	if err != nil {
+		return utils.Error(c, err)
+	}
+	list.Shared = list.ShareTokenHash != ""
+	return utils.SuccessMessage(c, fiber.StatusCreated, "shortlist.item_added", list)
+}
+
+// RemoveShortlistItem menghapus satu item dari shortlist
+func RemoveShortlistItem(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+	itemID, err := primitive.ObjectIDFromHex(c.Params("itemId"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	client, err := currentClient(ctx)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	list, err := shortlist.RemoveItem(ctx, id, client.ID, itemID, time.Now())
+	if err != nil {
+		return utils.Error(c, err)
+	}
+	list.Shared = list.ShareTokenHash != ""
+	return utils.SuccessMessage(c, fiber.StatusOK, "shortlist.item_removed", list)
+}
+
+// ShareShortlist membuat link berbagi read-only. Token hanya ditampilkan sekali; membuat
+// link baru membatalkan link sebelumnya.
+func ShareShortlist(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	client, err := currentClient(ctx)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	token, hash, err := shortlist.NewToken()
+	if err != nil {
+		return utils.Error(c, utils.ErrInternal.Wrap(err))
+	}
+	res, err := shortlistCollection.UpdateOne(ctx, bson.M{"_id": id, "client_id": client.ID}, bson.M{
+		"$set": bson.M{"share_token_hash": hash},
+	})
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("shortlist.save_failed", nil).Wrap(err))
+	}
+	if res.MatchedCount == 0 {
+		return utils.Error(c, utils.ErrShortlistNotFound)
+	}
+
+	return utils.SuccessMessage(c, fiber.StatusCreated, "shortlist.share_created", fiber.Map{
+		"token": token,
+		"url":   c.BaseURL() + "/api/shortlists/shared/" + token,
+	})
+}
+
+// RevokeShortlistShare menonaktifkan link berbagi shortlist
+func RevokeShortlistShare(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	client, err := currentClient(ctx)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	res, err := shortlistCollection.UpdateOne(ctx, bson.M{"_id": id, "client_id": client.ID}, bson.M{
+		"$unset": bson.M{"share_token_hash": ""},
+	})
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("shortlist.save_failed", nil).Wrap(err))
+	}
+	if res.MatchedCount == 0 {
+		return utils.Error(c, utils.ErrShortlistNotFound)
+	}
+	return utils.SuccessMessage(c, fiber.StatusOK, "shortlist.share_revoked", nil)
+}
+
+// GetSharedShortlist menampilkan shortlist lewat link berbagi tanpa login, misalnya untuk
+// pasangan client. Hanya nama dan item yang ditampilkan; ?date= berlaku seperti GetShortlist.
+func GetSharedShortlist(c *fiber.Ctx) error {
+	token := c.Params("token")
+	if token == "" {
+		return utils.Error(c, utils.ErrShortlistNotFound)
+	}
+	date, err := availabilityDate(c)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	// Lookup memakai hash token yang acak, jadi tidak perlu perbandingan waktu konstan
+	var list models.Shortlist
+	if err := shortlistCollection.FindOne(ctx, bson.M{"share_token_hash": shortlist.HashToken(token)}).Decode(&list); err != nil {
+		return utils.Error(c, utils.NotFoundOr(err, utils.ErrShortlistNotFound))
+	}
+	if date != nil {
+		if err := shortlist.Availability(ctx, list.Items, waitlist.Day(*date)); err != nil {
+			return utils.Error(c, utils.ErrDatabase.WithKey("shortlist.fetch_failed", nil).Wrap(err))
+		}
+	}
+
+	return utils.Success(c, fiber.StatusOK, fiber.Map{
+		"id":         list.ID,
+		"name":       list.Name,
+		"items":      list.Items,
+		"updated_at": list.UpdatedAt,
+	})
+}
@@ -2,112 +2,161 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"time"
 
-	"manajemen-fotografi-api/config"
+	"manajemen-fotografi-api/coupons"
+	"manajemen-fotografi-api/events"
+	"manajemen-fotografi-api/integrity"
+	"manajemen-fotografi-api/logging"
 	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/query"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
 
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-
-
 var (
-	transactionCollection = config.GetTransactionCollection()
-	bookingCollection     = config.GetBookingCollection()
+	transactionCollection = repositories.New("transactions")
+	bookingCollection     = repositories.New("bookings")
 )
 
+// transactionListSpec adalah whitelist filter dan sort untuk GET /api/transaction/transactions
+var transactionListSpec = query.Spec{
+	Filters: map[string]query.Filter{
+		"booking_id":   {Field: "booking_id", Type: query.ObjectID, Op: query.Eq},
+		"method":       {Field: "method", Type: query.String, Op: query.Eq},
+		"status":       {Field: "status", Type: query.String, Op: query.Eq},
+		"min_total":    {Field: "total", Type: query.Number, Op: query.Gte},
+		"max_total":    {Field: "total", Type: query.Number, Op: query.Lte},
+		"created_from": {Field: "created_at", Type: query.Time, Op: query.Gte},
+		"created_to":   {Field: "created_at", Type: query.Time, Op: query.Lte},
+	},
+	Sorts:       []string{"created_at", "total"},
+	DefaultSort: "-created_at",
+}
 
 func CreateDummyTransaction(c *fiber.Ctx) error {
 	var trx models.Transaction
 
 	if err := c.BodyParser(&trx); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format data salah"})
-	}
-
-	// Validasi data transaksi wajib
-	if trx.BookingID.IsZero() || trx.Method == "" || trx.Total <= 0 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Data transaksi tidak lengkap"})
+		return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
 	}
 
-	// Validasi metode pembayaran
-	validMethods := map[string]bool{
-		"transfer": true,
-		"ewallet":  true,
+	// Validasi booking_id, metode pembayaran dan total
+	if err := utils.Validate(trx); err != nil {
+		return utils.Error(c, err)
 	}
 
-	if !validMethods[trx.Method] {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Metode pembayaran tidak valid"})
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
-	// Cek apakah booking tersedia dan statusnya pending
+	// Klaim booking dulu: hanya satu checkout yang bisa mengubah pending menjadi confirmed,
+	// checkout lain yang bersamaan ditolak sebelum memakai kupon atau menyimpan transaksi
+	now := time.Now()
 	var booking models.Booking
-	err := bookingCollection.FindOne(ctx, bson.M{"_id": trx.BookingID}).Decode(&booking)
-	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Booking tidak ditemukan"})
+	err := bookingCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": trx.BookingID, "status": models.BookingStatusPending},
+		bson.M{"$set": bson.M{
+			"status":     models.BookingStatusConfirmed,
+			"updated_at": now,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&booking)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if n, err := bookingCollection.CountDocuments(ctx, bson.M{"_id": trx.BookingID}); err != nil || n == 0 {
+			return utils.Error(c, utils.NotFoundOr(err, utils.ErrBookingNotFound))
+		}
+		return utils.Error(c, utils.ErrBookingNotPending)
 	}
-
-	if booking.Status != models.BookingStatusPending {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Booking tidak dalam status pending"})
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.Wrap(err))
 	}
 
-	now := time.Now()
 	trx.ID = primitive.NewObjectID()
 	trx.Status = "paid"
 	trx.CreatedAt = now
 	trx.UpdatedAt = now
+	trx.Version = 1
+
+	// Total dari client adalah harga sebelum potongan; kupon dipakai di sini supaya
+	// batas pemakaiannya tercatat bersamaan dengan transaksi
+	trx.Subtotal, trx.Discount = 0, 0
+	var redemption *models.CouponRedemption
+	if trx.CouponCode != "" {
+		redeemed, quote, err := coupons.Redeem(ctx, trx.CouponCode, coupons.Checkout{
+			ClientID:       booking.ClientID,
+			PhotographerID: booking.PhotographerID,
+			BookingID:      booking.ID,
+			Subtotal:       trx.Total,
+			At:             now,
+		}, trx.ID)
+		if err != nil {
+			unclaimBooking(ctx, booking.ID)
+			return utils.Error(c, err)
+		}
+		redemption = &redeemed
+		trx.CouponCode = quote.Code
+		trx.Subtotal = quote.Subtotal
+		trx.Discount = quote.Discount
+		trx.Total = quote.Total
+	}
 
 	_, err = transactionCollection.InsertOne(ctx, trx)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal menyimpan transaksi"})
+		if redemption != nil {
+			coupons.Release(ctx, *redemption)
+		}
+		unclaimBooking(ctx, booking.ID)
+		return utils.Error(c, utils.ErrDatabase.WithKey("transaction.save_failed", nil).Wrap(err))
 	}
 
-	// Update status booking menjadi confirmed
-	updateResult, err := bookingCollection.UpdateOne(ctx, bson.M{"_id": trx.BookingID}, bson.M{
-		"$set": bson.M{
-			"status":     models.BookingStatusConfirmed,
-			"updated_at": now,
-		},
+	previous := models.BookingStatusPending
+	events.Publish(ctx, events.TransactionPaid, bookingRecipients(ctx, booking), map[string]interface{}{
+		"transaction": trx,
+		"booking_id":  booking.ID,
 	})
-	if err != nil {
-		// Warning: transaksi sudah tersimpan tapi update booking gagal
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"message":     "Transaksi berhasil, tapi gagal update status booking",
-			"transaction": trx,
-		})
-	}
+	publishBookingStatus(ctx, previous, booking)
 
-	if updateResult.MatchedCount == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Booking tidak ditemukan saat update status"})
-	}
+	return utils.SuccessMessage(c, fiber.StatusCreated, "transaction.created", trx)
+}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"message":     "Transaksi berhasil (dummy), booking dikonfirmasi",
-		"transaction": trx,
-	})
+// unclaimBooking mengembalikan booking yang sudah diklaim checkout ke pending
+// karena pembayarannya gagal, supaya client bisa mencoba lagi
+func unclaimBooking(ctx context.Context, id primitive.ObjectID) {
+	_, err := bookingCollection.UpdateOne(context.WithoutCancel(ctx),
+		bson.M{"_id": id, "status": models.BookingStatusConfirmed},
+		bson.M{"$set": bson.M{"status": models.BookingStatusPending, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		logging.FromContext(ctx).Error("Gagal mengembalikan status booking ke pending",
+			"booking_id", id.Hex(),
+			"error", err,
+		)
+	}
 }
 
 func GetAllTransactions(c *fiber.Ctx) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	cursor, err := transactionCollection.Find(ctx, bson.M{})
+	q, err := query.Parse(c, transactionListSpec)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal mengambil data"})
+		return utils.Error(c, err)
 	}
-	defer cursor.Close(ctx)
 
-	var transactions []models.Transaction
-	if err := cursor.All(ctx, &transactions); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal decode data"})
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	transactions, meta, err := query.Find[models.Transaction](ctx, transactionCollection, q)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("transaction.fetch_failed", nil).Wrap(err))
 	}
 
-	return c.JSON(transactions)
+	query.SetLinks(c, q, meta)
+	return utils.SuccessList(c, transactions, meta)
 }
 
 // Contoh tambahan: dapatkan transaksi berdasarkan ID
@@ -115,17 +164,35 @@ func GetTransactionByID(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := primitive.ObjectIDFromHex(idParam)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID tidak valid"})
+		return utils.Error(c, utils.ErrInvalidID)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
 	var trx models.Transaction
 	err = transactionCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&trx)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Transaksi tidak ditemukan"})
+		return utils.Error(c, utils.NotFoundOr(err, utils.ErrTransactionNotFound))
+	}
+
+	utils.SetETag(c, trx.Version)
+	return utils.Success(c, fiber.StatusOK, trx)
+}
+
+// DeleteTransaction memindahkan transaksi ke trash
+func DeleteTransaction(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	if err := integrity.Delete(ctx, "transactions", id, utils.ErrTransactionNotFound); err != nil {
+		return utils.Error(c, err)
 	}
 
-	return c.JSON(trx)
+	return utils.SuccessMessage(c, fiber.StatusOK, "transaction.deleted", nil)
 }
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"manajemen-fotografi-api/integrity"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/query"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// trashListSpec adalah whitelist filter dan sort untuk GET /api/trash/:collection
+var trashListSpec = query.Spec{
+	Filters: map[string]query.Filter{
+		"deleted_by":   {Field: "deleted_by", Type: query.ObjectID, Op: query.Eq},
+		"deleted_from": {Field: "deleted_at", Type: query.Time, Op: query.Gte},
+		"deleted_to":   {Field: "deleted_at", Type: query.Time, Op: query.Lte},
+	},
+	Sorts:       []string{"deleted_at"},
+	DefaultSort: "-deleted_at",
+}
+
+// GetTrash menampilkan dokumen yang sudah dihapus dan masih bisa dipulihkan, khusus admin
+func GetTrash(c *fiber.Ctx) error {
+	name := c.Params("collection")
+	// Pesan hanya boleh dibaca peserta booking; pesan ikut dipulihkan bersama booking-nya
+	if !slices.Contains(repositories.SoftDeleteCollections, name) || name == messageCollection.Name() {
+		return utils.Error(c, utils.ErrRouteNotFound)
+	}
+
+	q, err := query.Parse(c, trashListSpec)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+	q.Filter = bson.M{"$and": bson.A{q.Filter, repositories.Deleted}}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	docs, meta, err := findTrash(ctx, name, q)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("trash.fetch_failed", nil).Wrap(err))
+	}
+
+	query.SetLinks(c, q, meta)
+	return utils.SuccessList(c, docs, meta)
+}
+
+// findTrash membaca trash ke model masing-masing collection, bukan bson.M,
+// supaya field json:"-" seperti calendar_token_hash tidak ikut terkirim
+func findTrash(ctx context.Context, name string, q *query.Query) (any, *utils.Meta, error) {
+	coll := repositories.Unscoped(name)
+	switch name {
+	case "bookings":
+		return query.Find[models.Booking](ctx, coll, q)
+	case "galleries":
+		return query.Find[models.Gallery](ctx, coll, q)
+	case "clients":
+		return query.Find[models.Client](ctx, coll, q)
+	case "photographers":
+		return query.Find[models.Photographer](ctx, coll, q)
+	case "transactions":
+		return query.Find[models.Transaction](ctx, coll, q)
+	}
+	return nil, nil, utils.ErrRouteNotFound
+}
+
+// restoreHandler membuat handler POST /:id/restore untuk collection yang mendukung trash
+func restoreHandler(collection string, notFound *utils.AppError, messageKey string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := primitive.ObjectIDFromHex(c.Params("id"))
+		if err != nil {
+			return utils.Error(c, utils.ErrInvalidID)
+		}
+
+		ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+		defer cancel()
+
+		if err := integrity.Restore(ctx, collection, id, notFound); err != nil {
+			return utils.Error(c, err)
+		}
+		return utils.SuccessMessage(c, fiber.StatusOK, messageKey, nil)
+	}
+}
+
+// Handler restore per entitas
+var (
+	RestoreBooking      = restoreHandler("bookings", utils.ErrBookingNotFound, "booking.restored")
+	RestoreGallery      = restoreHandler("galleries", utils.ErrGalleryNotFound, "gallery.restored")
+	RestoreClient       = restoreHandler("clients", utils.ErrClientNotFound, "client.restored")
+	RestorePhotographer = restoreHandler("photographers", utils.ErrPhotographerNotFound, "photographer.restored")
+	RestoreTransaction  = restoreHandler("transactions", utils.ErrTransactionNotFound, "transaction.restored")
+)
@@ -2,89 +2,53 @@ package handlers
 
 import (
 	"context"
+	"strings"
 	"time"
-	"regexp"
-    "unicode"
 
-	"manajemen-fotografi-api/config"
+	"manajemen-fotografi-api/auth"
+	"manajemen-fotografi-api/i18n"
+	"manajemen-fotografi-api/logging"
+	"manajemen-fotografi-api/middlewares"
 	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/notifications"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/session"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var userCollection = config.GetCollection("users")
-
-// Fungsi validasi email menggunakan regex sederhana
-func isValidEmail(email string) bool {
-	re := regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
-	return re.MatchString(email)
-}
-
-// Fungsi validasi nama (3-50 karakter, hanya huruf dan spasi)
-func isValidName(name string) bool {
-	if len(name) < 3 || len(name) > 50 {
-		return false
-	}
-	for _, r := range name {
-		if !(unicode.IsLetter(r) || unicode.IsSpace(r)) {
-			return false
-		}
-	}
-	return true
-}
+var userCollection = repositories.New("users")
 
 // RegisterUser handler untuk registrasi user baru dengan validasi input
 func RegisterUser(c *fiber.Ctx) error {
 	type registerInput struct {
-		Name     string `json:"name"`
-		Email    string `json:"email"`
-		Password string `json:"password"`
-		Role     string `json:"role"`
+		Name     string `json:"name" validate:"required,person_name"`
+		Email    string `json:"email" validate:"required,email"`
+		Password string `json:"password" validate:"required,min=8"`
+		Role     string `json:"role" validate:"required,oneof=client photographer"`
+		Language string `json:"language" validate:"omitempty,oneof=id en"`
 	}
 
 	var input registerInput
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format data salah"})
-	}
-
-	// Validasi input
-	if !isValidEmail(input.Email) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format email tidak valid"})
+		return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
 	}
 
-	if len(input.Password) < 8 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Password minimal 8 karakter"})
-	}
-
-	if !isValidName(input.Name) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Nama harus 3-50 karakter dan hanya berisi huruf dan spasi"})
-	}
-
-	// Validasi role
-	if input.Role != models.RoleClient && input.Role != models.RolePhotographer {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Role tidak valid"})
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	// Email disimpan huruf kecil agar index unik tidak bisa diakali dengan kapitalisasi
+	input.Email = normalizeEmail(input.Email)
 
-	// cek apakah email sudah terdaftar
-	count, err := userCollection.CountDocuments(ctx, bson.M{"email": input.Email})
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal cek email"})
-	}
-	if count > 0 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Email sudah terdaftar"})
+	// Validasi input, semua kesalahan dikembalikan sekaligus
+	if err := utils.Validate(input); err != nil {
+		return utils.Error(c, err)
 	}
 
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memproses password"})
+		return utils.Error(c, utils.ErrInternal.WithKey("user.password_hash_failed", nil).Wrap(err))
 	}
 
 	user := models.User{
@@ -93,68 +57,161 @@ func RegisterUser(c *fiber.Ctx) error {
 		Email:     input.Email,
 		Password:  string(hashedPassword),
 		Role:      input.Role,
+		Language:  input.Language,
 		CreatedAt: time.Now().Unix(),
 		UpdatedAt: time.Now().Unix(),
 	}
 
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	// Email ganda ditolak oleh index unik users.email, bukan dengan cek terpisah yang rawan race
 	_, err = userCollection.InsertOne(ctx, user)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal menyimpan user"})
+		return utils.Error(c, utils.DuplicateOr(err, utils.ErrEmailTaken, utils.ErrDatabase.WithKey("user.save_failed", nil)))
+	}
+
+	// Email selamat datang masuk outbox, kegagalan tidak membatalkan registrasi
+	err = notifications.Notify(ctx, user, notifications.Registration,
+		map[string]string{"role": user.Role}, "registration:"+user.ID.Hex())
+	if err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "gagal membuat email registrasi", "user_id", user.ID.Hex(), "error", err)
 	}
 
 	user.Password = ""
-	return c.Status(fiber.StatusCreated).JSON(user)
+	return utils.Success(c, fiber.StatusCreated, user)
 }
 
 // LoginUser handler untuk login dengan validasi input email dan password
 func LoginUser(c *fiber.Ctx) error {
 	type loginInput struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
+		Email    string `json:"email" validate:"required,email"`
+		Password string `json:"password" validate:"required,min=8"`
 	}
 
 	var input loginInput
 	if err := c.BodyParser(&input); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format data salah"})
+		return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
 	}
 
-	// Validasi input login
-	if !isValidEmail(input.Email) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format email tidak valid"})
-	}
+	input.Email = normalizeEmail(input.Email)
 
-	if len(input.Password) < 8 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Password minimal 8 karakter"})
+	// Validasi input login
+	if err := utils.Validate(input); err != nil {
+		return utils.Error(c, err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
 	var user models.User
 	err := userCollection.FindOne(ctx, bson.M{"email": input.Email}).Decode(&user)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Email atau password salah"})
+		return utils.Error(c, utils.ErrInvalidCredentials)
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password))
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Email atau password salah"})
+		return utils.Error(c, utils.ErrInvalidCredentials)
 	}
 
 	user.Password = ""
 
-	return c.JSON(fiber.Map{
-		"message": "Login berhasil",
-		"user":    user,
-	})
+	// Simpan user ke session; ID session diganti untuk mencegah session fixation
+	sess, err := middlewares.Session(c)
+	if err != nil {
+		return utils.Error(c, utils.ErrInternal.WithKey("user.login_failed", nil).Wrap(err))
+	}
+	if err := sess.Regenerate(); err != nil {
+		return utils.Error(c, utils.ErrInternal.WithKey("user.login_failed", nil).Wrap(err))
+	}
+	sess.Set(auth.SessionUserID, user.ID.Hex())
+	sess.Set(auth.SessionRole, user.Role)
+	if user.Language != "" {
+		sess.Set(auth.SessionLanguage, user.Language)
+	}
+	if err := sess.Save(); err != nil {
+		return utils.Error(c, utils.ErrInternal.WithKey("user.login_failed", nil).Wrap(err))
+	}
+
+	// Preferensi bahasa user mengalahkan Accept-Language. Request berikutnya memakai
+	// bahasa yang disimpan di session oleh SetupSession.
+	if user.Language != "" {
+		c.SetUserContext(i18n.WithLang(c.UserContext(), user.Language))
+	}
+
+	return utils.SuccessMessage(c, fiber.StatusOK, "user.login_success", user)
 }
 
+// normalizeEmail menyeragamkan email sebelum disimpan atau dicari
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
 
 // LogoutUser handler untuk logout
 func LogoutUser(c *fiber.Ctx) error {
-	sess := c.Locals("session").(*session.Session)
+	sess, err := middlewares.Session(c)
+	if err != nil {
+		return utils.Error(c, utils.ErrInternal.WithKey("user.logout_failed", nil).Wrap(err))
+	}
 	if err := sess.Destroy(); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal logout"})
+		return utils.Error(c, utils.ErrInternal.WithKey("user.logout_failed", nil).Wrap(err))
+	}
+	return utils.SuccessMessage(c, fiber.StatusOK, "user.logout_success", nil)
+}
+
+// GetNotificationPreferences menampilkan kanal untuk setiap jenis notifikasi milik user
+// yang login, termasuk default email untuk jenis yang belum diatur
+func GetNotificationPreferences(c *fiber.Ctx) error {
+	actor, _ := auth.FromContext(c.UserContext())
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var user models.User
+	if err := userCollection.FindOne(ctx, bson.M{"_id": actor.UserID}).Decode(&user); err != nil {
+		return utils.Error(c, utils.NotFoundOr(err, utils.ErrUserNotFound))
+	}
+
+	return utils.Success(c, fiber.StatusOK, effectivePreferences(user.Notifications))
+}
+
+// UpdateNotificationPreferences mengganti pilihan kanal per jenis notifikasi, misal
+// {"booking_confirmed": ["email", "whatsapp"], "gallery_delivered": []}.
+// Jenis yang tidak dikirim kembali ke default email.
+func UpdateNotificationPreferences(c *fiber.Ctx) error {
+	actor, _ := auth.FromContext(c.UserContext())
+
+	var prefs models.NotificationPreferences
+	if err := c.BodyParser(&prefs); err != nil {
+		return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
+	}
+	if err := utils.ValidateFields(models.User{Notifications: prefs}, "Notifications"); err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	result, err := userCollection.UpdateOne(ctx, bson.M{"_id": actor.UserID}, bson.M{"$set": bson.M{
+		"notification_preferences": prefs,
+		"updated_at":               time.Now().Unix(),
+	}})
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("user.save_failed", nil).Wrap(err))
+	}
+	if result.MatchedCount == 0 {
+		return utils.Error(c, utils.ErrUserNotFound)
+	}
+
+	return utils.SuccessMessage(c, fiber.StatusOK, "user.preferences_updated", effectivePreferences(prefs))
+}
+
+// effectivePreferences mengisi jenis notifikasi yang belum diatur dengan kanal default
+func effectivePreferences(prefs models.NotificationPreferences) models.NotificationPreferences {
+	effective := models.NotificationPreferences{}
+	for _, kind := range notifications.Kinds {
+		effective[kind] = prefs.Channels(kind)
 	}
-	return c.JSON(fiber.Map{"message": "Logout berhasil"})
+	return effective
 }
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// versioned adalah dokumen yang punya nomor versi, lihat models.Versioned
+type versioned interface {
+	CurrentVersion() int64
+}
+
+// updateVersioned menjalankan update hanya jika versi dokumen sama dengan version dari If-Match,
+// lalu men-decode dokumen baru ke out (pointer ke model) dan menulis ETag versinya.
+// Error sudah dipetakan: versi beda menjadi 412, dokumen tidak ada menjadi notFound,
+// pelanggaran index unik menjadi duplicate (jika diisi) dan sisanya fallback.
+func updateVersioned(ctx context.Context, c *fiber.Ctx, coll repositories.Collection, id primitive.ObjectID,
+	version int64, update bson.M, out interface{}, notFound, duplicate, fallback *utils.AppError) error {
+	err := repositories.UpdateVersioned(ctx, coll, id, version, update, out)
+	switch {
+	case err == nil:
+		if doc, ok := out.(versioned); ok {
+			utils.SetETag(c, doc.CurrentVersion())
+		}
+		return nil
+	case errors.Is(err, repositories.ErrVersionConflict):
+		return utils.ErrPreconditionFailed.Wrap(err)
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return notFound
+	case duplicate != nil:
+		return utils.DuplicateOr(err, duplicate, fallback)
+	}
+	return fallback.Wrap(err)
+}
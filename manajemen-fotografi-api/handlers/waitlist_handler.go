@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"manajemen-fotografi-api/auth"
+	"manajemen-fotografi-api/integrity"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/query"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
+	"manajemen-fotografi-api/waitlist"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var waitlistCollection = repositories.New(waitlist.Collection)
+
+// waitlistListSpec adalah whitelist filter dan sort untuk daftar antrean waitlist
+var waitlistListSpec = query.Spec{
+	Filters: map[string]query.Filter{
+		"status":          {Field: "status", Type: query.String, Op: query.Eq},
+		"photographer_id": {Field: "photographer_id", Type: query.ObjectID, Op: query.Eq},
+		"date_from":       {Field: "date", Type: query.Time, Op: query.Gte},
+		"date_to":         {Field: "date", Type: query.Time, Op: query.Lte},
+	},
+	Sorts:       []string{"date", "created_at"},
+	DefaultSort: "date",
+}
+
+// waitlistInput adalah body POST /api/photographer/:id/waitlist, date berupa YYYY-MM-DD (WIB)
+type waitlistInput struct {
+	Date string `json:"date" validate:"required"`
+}
+
+// currentClient mengambil profil client milik user yang login
+func currentClient(ctx context.Context) (models.Client, error) {
+	var client models.Client
+	actor, ok := auth.FromContext(ctx)
+	if !ok {
+		return client, utils.ErrUnauthenticated
+	}
+	if err := clientCollection.FindOne(ctx, bson.M{"user_id": actor.UserID}).Decode(&client); err != nil {
+		return client, utils.NotFoundOr(err, utils.ErrClientNotFound)
+	}
+	return client, nil
+}
+
+// JoinWaitlist memasukkan client yang login ke antrean tanggal fotografer yang sudah penuh
+func JoinWaitlist(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+	var input waitlistInput
+	if err := c.BodyParser(&input); err != nil {
+		return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
+	}
+	if err := utils.Validate(input); err != nil {
+		return utils.Error(c, err)
+	}
+	date, err := time.ParseInLocation(time.DateOnly, input.Date, waitlist.Location)
+	if err != nil {
+		return utils.Error(c, utils.Invalid("date", "query.invalid_date"))
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	client, err := currentClient(ctx)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+	if err := integrity.Exists(ctx, integrity.Photographer("photographer_id", id)); err != nil {
+		return utils.Error(c, err)
+	}
+
+	entry, err := waitlist.Join(ctx, id, client.ID, date, time.Now())
+	if err != nil {
+		return utils.Error(c, err)
+	}
+	return utils.SuccessMessage(c, fiber.StatusCreated, "waitlist.joined", entry)
+}
+
+// GetMyWaitlist menampilkan antrean dan tawaran milik client yang login
+func GetMyWaitlist(c *fiber.Ctx) error {
+	q, err := query.Parse(c, waitlistListSpec)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	client, err := currentClient(ctx)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	q.Filter["client_id"] = client.ID
+	entries, meta, err := query.Find[models.WaitlistEntry](ctx, waitlistCollection, q)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("waitlist.fetch_failed", nil).Wrap(err))
+	}
+
+	query.SetLinks(c, q, meta)
+	return utils.SuccessList(c, entries, meta)
+}
+
+// LeaveWaitlist mengeluarkan client dari antrean. Jika client sedang memegang tawaran,
+// tawaran dianggap ditolak dan langsung diberikan ke antrean berikutnya.
+func LeaveWaitlist(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	client, err := currentClient(ctx)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	entry, err := waitlist.Leave(ctx, id, client.ID, time.Now())
+	if err != nil {
+		return utils.Error(c, err)
+	}
+	return utils.SuccessMessage(c, fiber.StatusOK, "waitlist.left", entry)
+}
+
+// GetPhotographerWaitlist menampilkan antrean aktif untuk fotografer, hanya untuk fotografer
+// tersebut dan admin. Urutan default mengikuti tanggal lalu waktu client mendaftar.
+func GetPhotographerWaitlist(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+	q, err := query.Parse(c, waitlistListSpec)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	if _, err := ownedPhotographer(ctx, id); err != nil {
+		return utils.Error(c, err)
+	}
+
+	q.Filter["photographer_id"] = id
+	if _, ok := q.Filter["status"]; !ok {
+		q.Filter["active"] = true
+	}
+	entries, meta, err := query.Find[models.WaitlistEntry](ctx, waitlistCollection, q)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("waitlist.fetch_failed", nil).Wrap(err))
+	}
+
+	query.SetLinks(c, q, meta)
+	return utils.SuccessList(c, entries, meta)
+}
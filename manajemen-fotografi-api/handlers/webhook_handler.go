@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"manajemen-fotografi-api/auth"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/query"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
+	"manajemen-fotografi-api/webhooks"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	webhookCollection  = repositories.Unscoped(webhooks.Collection)
+	deliveryCollection = repositories.Unscoped(webhooks.DeliveryCollection)
+)
+
+// webhookListSpec adalah whitelist filter dan sort untuk GET /api/admin/webhooks
+var webhookListSpec = query.Spec{
+	Filters: map[string]query.Filter{
+		"event": {Field: "events", Type: query.String, Op: query.Eq},
+	},
+	Sorts:       []string{"created_at", "name"},
+	DefaultSort: "-created_at",
+}
+
+// deliveryListSpec adalah whitelist filter dan sort untuk GET /api/admin/webhook-deliveries.
+// Filter status=dead menampilkan isi dead-letter.
+var deliveryListSpec = query.Spec{
+	Filters: map[string]query.Filter{
+		"webhook_id": {Field: "webhook_id", Type: query.ObjectID, Op: query.Eq},
+		"event_id":   {Field: "event_id", Type: query.ObjectID, Op: query.Eq},
+		"event_type": {Field: "event_type", Type: query.String, Op: query.Eq},
+		"status":     {Field: "status", Type: query.String, Op: query.Eq},
+		"from":       {Field: "created_at", Type: query.Time, Op: query.Gte},
+		"to":         {Field: "created_at", Type: query.Time, Op: query.Lte},
+	},
+	Sorts:       []string{"created_at", "next_attempt_at"},
+	DefaultSort: "-created_at",
+}
+
+// CreateWebhook mendaftarkan endpoint partner. Secret dibuat server dan hanya
+// dikembalikan di response ini.
+func CreateWebhook(c *fiber.Ctx) error {
+	var webhook models.Webhook
+	if err := c.BodyParser(&webhook); err != nil {
+		return utils.Error(c, utils.ErrInvalidBody.Wrap(err))
+	}
+	if err := utils.ValidateFields(webhook, "Name", "URL", "Events"); err != nil {
+		return utils.Error(c, err)
+	}
+
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		return utils.Error(c, utils.ErrInternal.Wrap(err))
+	}
+
+	actor, _ := auth.FromContext(c.UserContext())
+	webhook.ID = primitive.NewObjectID()
+	webhook.Secret = secret
+	webhook.Active = true
+	webhook.CreatedBy = actor.UserID
+	webhook.CreatedAt = time.Now()
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	if _, err := webhookCollection.InsertOne(ctx, webhook); err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("webhook.save_failed", nil).Wrap(err))
+	}
+
+	return utils.Success(c, fiber.StatusCreated, webhook)
+}
+
+// GetAllWebhooks menampilkan webhook terdaftar tanpa secret
+func GetAllWebhooks(c *fiber.Ctx) error {
+	q, err := query.Parse(c, webhookListSpec)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	hooks, meta, err := query.Find[models.Webhook](ctx, webhookCollection, q)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("webhook.fetch_failed", nil).Wrap(err))
+	}
+	for i := range hooks {
+		hooks[i].Secret = ""
+	}
+
+	query.SetLinks(c, q, meta)
+	return utils.SuccessList(c, hooks, meta)
+}
+
+// DeleteWebhook menghapus webhook. Delivery yang masih pending akan masuk dead-letter
+// pada percobaan berikutnya, log yang sudah ada tetap disimpan.
+func DeleteWebhook(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	result, err := webhookCollection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("webhook.save_failed", nil).Wrap(err))
+	}
+	if result.DeletedCount == 0 {
+		return utils.Error(c, utils.ErrWebhookNotFound)
+	}
+
+	return utils.SuccessMessage(c, fiber.StatusOK, "webhook.deleted", nil)
+}
+
+// GetWebhookDeliveries menampilkan log pengiriman beserta setiap percobaannya
+func GetWebhookDeliveries(c *fiber.Ctx) error {
+	q, err := query.Parse(c, deliveryListSpec)
+	if err != nil {
+		return utils.Error(c, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	deliveries, meta, err := query.Find[models.WebhookDelivery](ctx, deliveryCollection, q)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("webhook.fetch_failed", nil).Wrap(err))
+	}
+
+	query.SetLinks(c, q, meta)
+	return utils.SuccessList(c, deliveries, meta)
+}
+
+// RetryWebhookDelivery mengembalikan delivery dari dead-letter ke antrean untuk dikirim
+// secepatnya. Riwayat percobaan lama tetap disimpan, jatah percobaan dihitung ulang.
+func RetryWebhookDelivery(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return utils.Error(c, utils.ErrInvalidID)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
+	defer cancel()
+
+	var delivery models.WebhookDelivery
+	err = deliveryCollection.FindOne(ctx, bson.M{"_id": id, "status": models.WebhookDeliveryDead}).Decode(&delivery)
+	if err != nil {
+		return utils.Error(c, utils.NotFoundOr(err, utils.ErrWebhookDeliveryNotFound))
+	}
+
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.NextAttemptAt = time.Now()
+	delivery.RetryFrom = len(delivery.Attempts)
+	result, err := deliveryCollection.UpdateOne(ctx,
+		bson.M{"_id": id, "status": models.WebhookDeliveryDead},
+		bson.M{"$set": bson.M{
+			"status":          delivery.Status,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"retry_from":      delivery.RetryFrom,
+			"locked_until":    nil,
+		}},
+	)
+	if err != nil {
+		return utils.Error(c, utils.ErrDatabase.WithKey("webhook.save_failed", nil).Wrap(err))
+	}
+	if result.MatchedCount == 0 {
+		// Sudah di-retry request lain di antara FindOne dan UpdateOne
+		return utils.Error(c, utils.ErrWebhookDeliveryNotFound)
+	}
+
+	return utils.SuccessMessage(c, fiber.StatusOK, "webhook.requeued", delivery)
+}
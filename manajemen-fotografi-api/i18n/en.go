@@ -0,0 +1,232 @@
+package i18n
+
+// english adalah katalog bahasa Inggris, kuncinya harus sama dengan katalog Indonesia
+var english = map[string]string{
+	// Kode error umum
+	"INTERNAL_ERROR":        "An internal server error occurred",
+	"DATABASE_ERROR":        "Failed to process data",
+	"UPLOAD_FAILED":         "Failed to store the file",
+	"TIMEOUT":               "The request timed out",
+	"ROUTE_NOT_FOUND":       "Endpoint not found",
+	"NOT_FOUND":             "Data not found",
+	"HTTP_ERROR":            "The request could not be processed",
+	"INVALID_ID":            "Invalid ID",
+	"INVALID_USER_ID":       "Invalid user ID",
+	"INVALID_BODY":          "Malformed request body",
+	"VALIDATION_FAILED":     "Invalid data",
+	"DUPLICATE":             "Data already exists",
+	"DELETE_RESTRICTED":     "Data is still in use and cannot be deleted",
+	"PRECONDITION_REQUIRED": "The If-Match header is required",
+	"PRECONDITION_FAILED":   "The data was changed by someone else, reload and try again",
+	"UNAUTHENTICATED":       "Please log in first",
+	"FORBIDDEN":             "You do not have access to this data",
+
+	// Kode error per entitas
+	"USER_NOT_FOUND":              "User not found",
+	"EMAIL_ALREADY_REGISTERED":    "Email is already registered",
+	"INVALID_CREDENTIALS":         "Incorrect email or password",
+	"CLIENT_NOT_FOUND":            "Client not found",
+	"CLIENT_ALREADY_EXISTS":       "User already has a client profile",
+	"PHOTOGRAPHER_NOT_FOUND":      "Photographer not found",
+	"PHOTOGRAPHER_ALREADY_EXISTS": "User already has a photographer profile",
+	"BOOKING_NOT_FOUND":           "Booking not found",
+	"BOOKING_NOT_PENDING":         "Booking is not pending",
+	"GALLERY_NOT_FOUND":           "Gallery not found",
+	"TRANSACTION_NOT_FOUND":       "Transaction not found",
+	"WEBHOOK_NOT_FOUND":           "Webhook not found",
+	"WEBHOOK_DELIVERY_NOT_FOUND":  "Webhook delivery not found or not in the dead-letter list",
+	"JOB_NOT_FOUND":               "Job not found or not in failed state",
+	"CALENDAR_TOKEN_INVALID":      "Invalid calendar token",
+	"BOOKING_SLOT_BUSY":           "The photographer is not available on that date",
+	"ANALYTICS_METRIC_NOT_FOUND":  "Analytics report not found",
+	"LEDGER_PAYMENT_NOT_FOUND":    "The transaction payment has not been recorded in the ledger",
+	"REFUND_EXCEEDS_PAYMENT":      "Total refunds exceed the payment amount",
+	"COMMISSION_RATE_NOT_FOUND":   "The photographer has no custom commission rate",
+	"PAYOUT_NOT_FOUND":            "Payout batch not found",
+	"COUPON_NOT_FOUND":            "Coupon not found",
+	"COUPON_ALREADY_EXISTS":       "Coupon code is already taken",
+	"BOOKING_SLOT_HELD":           "This date is currently offered to another client from the waitlist",
+	"WAITLIST_NOT_FOUND":          "Waitlist entry not found",
+	"WAITLIST_ALREADY_JOINED":     "You are already on the waitlist for this date",
+	"WAITLIST_DATE_AVAILABLE":     "The date is still available, please book directly",
+	"WAITLIST_CLOSED":             "The waitlist entry is no longer active",
+	"SHORTLIST_NOT_FOUND":         "Shortlist not found",
+	"SHORTLIST_ITEM_NOT_FOUND":    "Shortlist item not found",
+	"SHORTLIST_ITEM_EXISTS":       "The item is already in this shortlist",
+
+	// Pesan operasi
+	"user.password_hash_failed":        "Failed to process password",
+	"user.save_failed":                 "Failed to save user",
+	"user.login_failed":                "Failed to log in",
+	"user.logout_failed":               "Failed to log out",
+	"user.login_success":               "Logged in successfully",
+	"user.logout_success":              "Logged out successfully",
+	"user.preferences_updated":         "Notification preferences saved",
+	"client.fetch_failed":              "Failed to fetch clients",
+	"client.save_failed":               "Failed to save client",
+	"client.update_failed":             "Failed to update client",
+	"client.deleted":                   "Client deleted",
+	"client.restored":                  "Client restored",
+	"photographer.fetch_failed":        "Failed to fetch photographers",
+	"photographer.save_failed":         "Failed to save photographer",
+	"photographer.update_failed":       "Failed to update photographer",
+	"photographer.photo_upload_failed": "Failed to store profile photo",
+	"photographer.deleted":             "Photographer deleted",
+	"photographer.restored":            "Photographer restored",
+	"booking.fetch_failed":             "Failed to fetch bookings",
+	"booking.save_failed":              "Failed to save booking",
+	"booking.update_failed":            "Failed to update booking",
+	"booking.updated":                  "Booking updated",
+	"booking.deleted":                  "Booking deleted",
+	"booking.restored":                 "Booking restored",
+	"gallery.fetch_failed":             "Failed to fetch galleries",
+	"gallery.save_failed":              "Failed to save gallery",
+	"gallery.update_failed":            "Failed to update gallery",
+	"gallery.updated":                  "Gallery updated",
+	"gallery.deleted":                  "Gallery deleted",
+	"gallery.restored":                 "Gallery restored",
+	"transaction.fetch_failed":         "Failed to fetch transactions",
+	"transaction.save_failed":          "Failed to save transaction",
+	"transaction.created":              "Payment recorded (dummy), booking confirmed",
+	"transaction.restored":             "Transaction restored",
+	"transaction.deleted":              "Transaction deleted",
+	"trash.fetch_failed":               "Failed to fetch trash",
+	"audit.fetch_failed":               "Failed to fetch audit log",
+	"event.replay_failed":              "Failed to fetch missed events",
+	"webhook.fetch_failed":             "Failed to fetch webhooks",
+	"webhook.save_failed":              "Failed to save webhook",
+	"webhook.deleted":                  "Webhook deleted",
+	"webhook.requeued":                 "Webhook delivery requeued",
+	"job.fetch_failed":                 "Failed to fetch jobs",
+	"job.requeued":                     "Job rescheduled",
+	"calendar.token_created":           "Calendar token created, save the feed URL because the token is only shown once",
+	"calendar.fetch_failed":            "Failed to build calendar feed",
+	"calendar.name":                    "Photography bookings",
+	"calendar.summary":                 "Photo session: {client}",
+	"busy.fetch_failed":                "Failed to fetch busy times",
+	"busy.import_failed":               "Failed to import busy times",
+	"busy.imported":                    "Busy times imported",
+	"message.fetch_failed":             "Failed to fetch messages",
+	"message.save_failed":              "Failed to save message",
+	"message.attachment_upload_failed": "Failed to save message attachment",
+	"message.empty":                    "Message must contain text or an attachment",
+	"message.image_only":               "{field} may only contain JPEG, PNG, WebP or GIF images",
+	"message.attachment_too_large":     "{field} must be at most {param} per file",
+	"analytics.fetch_failed":           "Failed to build analytics report",
+	"ledger.fetch_failed":              "Failed to fetch ledger",
+	"ledger.save_failed":               "Failed to save journal",
+	"ledger.refund_created":            "Refund recorded successfully",
+	"ledger.adjustment_created":        "Balance adjustment recorded successfully",
+	"commission.fetch_failed":          "Failed to fetch commission rates",
+	"commission.save_failed":           "Failed to save commission rate",
+	"commission.saved":                 "Commission rate saved successfully",
+	"commission.deleted":               "Custom commission rate removed, the photographer now uses the default rate",
+	"payout.fetch_failed":              "Failed to fetch payout batches",
+	"payout.save_failed":               "Failed to create payout batch",
+	"payout.created":                   "Payout batch created successfully",
+	"coupon.fetch_failed":              "Failed to fetch coupons",
+	"coupon.save_failed":               "Failed to save coupon",
+	"coupon.created":                   "Coupon created successfully",
+	"coupon.updated":                   "Coupon updated successfully",
+	"waitlist.fetch_failed":            "Failed to fetch waitlist",
+	"waitlist.joined":                  "Joined the waitlist, we will let you know if this date opens up",
+	"waitlist.left":                    "Left the waitlist",
+	"shortlist.fetch_failed":           "Failed to fetch shortlist",
+	"shortlist.save_failed":            "Failed to save shortlist",
+	"shortlist.created":                "Shortlist created",
+	"shortlist.updated":                "Shortlist updated",
+	"shortlist.deleted":                "Shortlist deleted",
+	"shortlist.item_added":             "Added to shortlist",
+	"shortlist.item_removed":           "Removed from shortlist",
+	"shortlist.share_created":          "Shortlist share link created",
+	"shortlist.share_revoked":          "Shortlist share link disabled",
+
+	// Aturan validasi, {field} = nama field, {param} = parameter aturan
+	"validation.required":       "{field} is required",
+	"validation.min_string":     "{field} must be at least {param} characters",
+	"validation.min":            "{field} must be at least {param}",
+	"validation.max_string":     "{field} must be at most {param} characters",
+	"validation.max":            "{field} must be at most {param}",
+	"validation.gt":             "{field} must be greater than {param}",
+	"validation.email":          "Invalid email format",
+	"validation.url":            "{field} must be a valid URL",
+	"validation.oneof":          "{field} must be one of: {param}",
+	"validation.phone_id":       "Invalid phone number",
+	"validation.objectid":       "{field} must be a valid ID",
+	"validation.booking_status": "Invalid booking status",
+	"validation.person_name":    "Name must be 3-50 characters and contain only letters and spaces",
+	"validation.json_array":     "{field} must be a JSON array",
+	"validation.invalid":        "{field} is invalid",
+
+	// Integritas relasi antar entitas
+	"integrity.not_found":                  "{field} refers to data that does not exist",
+	"integrity.role_mismatch":              "{field} must belong to a user with role {role}",
+	"integrity.restricted.active_bookings": "Cannot be deleted while it still has {count} active booking(s)",
+	"integrity.restricted.bookings":        "Cannot be deleted while it still has {count} booking(s)",
+	"integrity.restricted.messages":        "Cannot be deleted while it still has {count} message(s)",
+	"integrity.restricted.galleries":       "Cannot be deleted while it still has {count} galler(ies)",
+	"integrity.restricted.transactions":    "Cannot be deleted because it already has {count} transaction(s)",
+
+	// Parameter query list
+	"query.positive_number":  "{field} must be a number greater than 0",
+	"query.cursor_with_page": "cursor cannot be combined with page",
+	"query.invalid_cursor":   "Invalid cursor",
+	"query.invalid_date":     "{field} must be a YYYY-MM-DD or RFC3339 date",
+	"query.invalid_number":   "{field} must be a number",
+
+	// Calendar import (ICS)
+	"calendar.invalid_ics":   "{field} is not a valid iCalendar (.ics) file",
+	"calendar.file_required": "{field} must contain an .ics file",
+
+	// Analytics report parameters
+	"analytics.invalid_timezone": "{field} must be an IANA time zone name, e.g. Asia/Jakarta",
+	"analytics.invalid_range":    "{field} must be before to",
+	"analytics.range_too_long":   "The report range may be at most {param} days",
+
+	// Batch payout
+	"payout.future_cutoff": "{field} must not be in the future",
+
+	// Kode promo
+	"coupon.not_found":      "{field} is not recognised",
+	"coupon.inactive":       "{field} is no longer active",
+	"coupon.not_started":    "{field} is not valid yet",
+	"coupon.expired":        "{field} has expired",
+	"coupon.not_applicable": "{field} does not apply to this photographer",
+	"coupon.min_spend":      "{field} only applies to transactions of at least Rp{param}",
+	"coupon.used_up":        "{field} has been fully redeemed",
+	"coupon.client_limit":   "{field} has reached your usage limit",
+	"coupon.percent_max":    "{field} may be at most 100 for percentage discounts",
+	"coupon.invalid_window": "{field} must be after starts_at",
+
+	// Waitlist
+	"waitlist.past_date": "{field} is in the past",
+
+	// Shortlist
+	"shortlist.full":            "{field} can hold at most {param} items",
+	"shortlist.target_required": "{field} is required for this item kind",
+
+	// PATCH (JSON Merge Patch)
+	"patch.field_not_allowed": "{field} cannot be changed",
+
+	// Template email dan notifikasi
+	"email.greeting":                  "Hi {name},",
+	"email.footer":                    "Thank you for using our service.",
+	"email.registration.subject":      "Welcome to Manajemen Fotografi",
+	"email.registration.body":         "Your {role} account has been created. Log in to start using the service.",
+	"email.booking_created.subject":   "New booking for {date}",
+	"email.booking_created.body":      "A booking for {date} at {location} has been created and is awaiting confirmation.",
+	"email.booking_confirmed.subject": "Booking on {date} confirmed",
+	"email.booking_confirmed.body":    "Your booking for {date} at {location} has been confirmed.",
+	"email.booking_cancelled.subject": "Booking on {date} cancelled",
+	"email.booking_cancelled.body":    "The booking for {date} at {location} has been cancelled.",
+	"email.booking_reminder.subject":  "Reminder: booking on {date}",
+	"email.booking_reminder.body":     "Your booking at {location} is in {days} day(s), on {date}.",
+	"email.booking_message.subject":   "New message about booking {date}",
+	"email.booking_message.body":      "There is a new message about the booking on {date} at {location}: \"{preview}\"",
+	"email.waitlist_offer.subject":    "{date} is now available for you",
+	"email.waitlist_offer.body":       "The date {date} you were waiting for is now free. Please book before {expires}, after that the offer passes to the next person in line.",
+	"email.payment_receipt.subject":   "Payment receipt for your {date} booking",
+	"email.payment_receipt.body":      "We have received your payment of {amount} via {method}.",
+	"email.gallery_delivered.subject": "Gallery \"{title}\" is ready",
+	"email.gallery_delivered.body":    "Your photos are now available at {url}.",
+}
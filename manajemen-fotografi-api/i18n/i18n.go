@@ -0,0 +1,131 @@
+package i18n
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Bahasa yang didukung
+const (
+	Indonesian = "id"
+	English    = "en"
+
+	// Default dipakai jika bahasa tidak dikenali atau kunci belum diterjemahkan
+	Default = Indonesian
+)
+
+// catalogs menyimpan pesan per bahasa, dengan kunci kode error (BOOKING_NOT_FOUND),
+// kunci pesan (booking.save_failed), aturan validasi (validation.required)
+// dan template notifikasi (email.booking_created.subject).
+var catalogs = map[string]map[string]string{
+	Indonesian: indonesian,
+	English:    english,
+}
+
+type ctxKey struct{}
+
+// Supported mengembalikan daftar bahasa yang didukung, default paling depan
+func Supported() []string {
+	return []string{Indonesian, English}
+}
+
+// Normalize mengubah tag bahasa (misal "en-US", "id_ID") menjadi bahasa yang didukung.
+// String kosong dikembalikan jika bahasa tidak didukung.
+func Normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	if _, ok := catalogs[lang]; ok {
+		return lang
+	}
+	return ""
+}
+
+// Resolve memilih bahasa: preferensi user lebih diutamakan daripada Accept-Language
+func Resolve(preference, acceptLanguage string) string {
+	if lang := Normalize(preference); lang != "" {
+		return lang
+	}
+	if lang := FromAcceptLanguage(acceptLanguage); lang != "" {
+		return lang
+	}
+	return Default
+}
+
+// FromAcceptLanguage memilih bahasa yang didukung dengan bobot q tertinggi dari
+// header Accept-Language, misal "en-US,en;q=0.9,id;q=0.8". String kosong jika tidak ada.
+func FromAcceptLanguage(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, q := part, 1.0
+		if i := strings.Index(part, ";"); i >= 0 {
+			tag = part[:i]
+			if v, ok := strings.CutPrefix(strings.TrimSpace(part[i+1:]), "q="); ok {
+				parsed, err := strconv.ParseFloat(v, 64)
+				if err != nil {
+					continue
+				}
+				q = parsed
+			}
+		}
+		if lang := Normalize(tag); lang != "" && q > bestQ {
+			best, bestQ = lang, q
+		}
+	}
+	return best
+}
+
+// Has mengecek apakah kunci ada di katalog bahasa default
+func Has(key string) bool {
+	_, ok := catalogs[Default][key]
+	return ok
+}
+
+// Keys mengembalikan semua kunci katalog sebuah bahasa, terurut
+func Keys(lang string) []string {
+	keys := make([]string, 0, len(catalogs[lang]))
+	for k := range catalogs[lang] {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// T menerjemahkan kunci ke bahasa tertentu. Placeholder {nama} diganti dengan params.
+// Jika kunci tidak ada di bahasa tersebut dipakai bahasa default, lalu kunci itu sendiri.
+func T(lang, key string, params map[string]string) string {
+	msg, ok := catalogs[lang][key]
+	if !ok {
+		msg, ok = catalogs[Default][key]
+	}
+	if !ok {
+		msg = key
+	}
+
+	if len(params) == 0 {
+		return msg
+	}
+	pairs := make([]string, 0, len(params)*2)
+	for k, v := range params {
+		pairs = append(pairs, "{"+k+"}", v)
+	}
+	return strings.NewReplacer(pairs...).Replace(msg)
+}
+
+// WithLang menyimpan bahasa request ke context
+func WithLang(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, lang)
+}
+
+// FromContext mengambil bahasa dari context, atau bahasa default jika belum ada
+func FromContext(ctx context.Context) string {
+	if ctx != nil {
+		if lang, ok := ctx.Value(ctxKey{}).(string); ok && lang != "" {
+			return lang
+		}
+	}
+	return Default
+}
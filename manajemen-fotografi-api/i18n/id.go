@@ -0,0 +1,232 @@
+package i18n
+
+// indonesian adalah katalog bahasa Indonesia, sekaligus bahasa default
+var indonesian = map[string]string{
+	// Kode error umum
+	"INTERNAL_ERROR":        "Terjadi kesalahan pada server",
+	"DATABASE_ERROR":        "Gagal memproses data",
+	"UPLOAD_FAILED":         "Gagal menyimpan file",
+	"TIMEOUT":               "Permintaan melebihi batas waktu",
+	"ROUTE_NOT_FOUND":       "Endpoint tidak ditemukan",
+	"NOT_FOUND":             "Data tidak ditemukan",
+	"HTTP_ERROR":            "Permintaan tidak dapat diproses",
+	"INVALID_ID":            "ID tidak valid",
+	"INVALID_USER_ID":       "User ID tidak valid",
+	"INVALID_BODY":          "Format data salah",
+	"VALIDATION_FAILED":     "Data tidak valid",
+	"DUPLICATE":             "Data sudah ada",
+	"DELETE_RESTRICTED":     "Data masih dipakai dan tidak bisa dihapus",
+	"PRECONDITION_REQUIRED": "Header If-Match wajib dikirim",
+	"PRECONDITION_FAILED":   "Data sudah diubah pihak lain, muat ulang lalu coba lagi",
+	"UNAUTHENTICATED":       "Silakan login terlebih dahulu",
+	"FORBIDDEN":             "Anda tidak memiliki akses ke data ini",
+
+	// Kode error per entitas
+	"USER_NOT_FOUND":              "User tidak ditemukan",
+	"EMAIL_ALREADY_REGISTERED":    "Email sudah terdaftar",
+	"INVALID_CREDENTIALS":         "Email atau password salah",
+	"CLIENT_NOT_FOUND":            "Client tidak ditemukan",
+	"CLIENT_ALREADY_EXISTS":       "User sudah memiliki profil client",
+	"PHOTOGRAPHER_NOT_FOUND":      "Fotografer tidak ditemukan",
+	"PHOTOGRAPHER_ALREADY_EXISTS": "User sudah memiliki profil fotografer",
+	"BOOKING_NOT_FOUND":           "Booking tidak ditemukan",
+	"BOOKING_NOT_PENDING":         "Booking tidak dalam status pending",
+	"GALLERY_NOT_FOUND":           "Galeri tidak ditemukan",
+	"TRANSACTION_NOT_FOUND":       "Transaksi tidak ditemukan",
+	"WEBHOOK_NOT_FOUND":           "Webhook tidak ditemukan",
+	"WEBHOOK_DELIVERY_NOT_FOUND":  "Delivery webhook tidak ditemukan atau tidak berada di dead-letter",
+	"JOB_NOT_FOUND":               "Job tidak ditemukan atau tidak berstatus gagal",
+	"CALENDAR_TOKEN_INVALID":      "Token kalender tidak valid",
+	"BOOKING_SLOT_BUSY":           "Fotografer sedang tidak tersedia pada tanggal tersebut",
+	"ANALYTICS_METRIC_NOT_FOUND":  "Laporan analitik tidak ditemukan",
+	"LEDGER_PAYMENT_NOT_FOUND":    "Pembayaran transaksi belum tercatat di buku besar",
+	"REFUND_EXCEEDS_PAYMENT":      "Total refund melebihi nominal pembayaran",
+	"COMMISSION_RATE_NOT_FOUND":   "Fotografer tidak memiliki tarif komisi khusus",
+	"PAYOUT_NOT_FOUND":            "Batch payout tidak ditemukan",
+	"COUPON_NOT_FOUND":            "Kupon tidak ditemukan",
+	"COUPON_ALREADY_EXISTS":       "Kode kupon sudah dipakai",
+	"BOOKING_SLOT_HELD":           "Tanggal ini sedang ditawarkan ke client lain dari waitlist",
+	"WAITLIST_NOT_FOUND":          "Antrean waitlist tidak ditemukan",
+	"WAITLIST_ALREADY_JOINED":     "Anda sudah berada di waitlist tanggal ini",
+	"WAITLIST_DATE_AVAILABLE":     "Tanggal masih tersedia, silakan booking langsung",
+	"WAITLIST_CLOSED":             "Antrean waitlist sudah tidak aktif",
+	"SHORTLIST_NOT_FOUND":         "Shortlist tidak ditemukan",
+	"SHORTLIST_ITEM_NOT_FOUND":    "Item shortlist tidak ditemukan",
+	"SHORTLIST_ITEM_EXISTS":       "Item sudah ada di shortlist ini",
+
+	// Pesan operasi
+	"user.password_hash_failed":        "Gagal memproses password",
+	"user.save_failed":                 "Gagal menyimpan user",
+	"user.login_failed":                "Gagal login",
+	"user.logout_failed":               "Gagal logout",
+	"user.login_success":               "Login berhasil",
+	"user.logout_success":              "Logout berhasil",
+	"user.preferences_updated":         "Preferensi notifikasi berhasil disimpan",
+	"client.fetch_failed":              "Gagal mengambil data client",
+	"client.save_failed":               "Gagal menyimpan client",
+	"client.update_failed":             "Gagal update client",
+	"client.deleted":                   "Client berhasil dihapus",
+	"client.restored":                  "Client berhasil dipulihkan",
+	"photographer.fetch_failed":        "Gagal mengambil data fotografer",
+	"photographer.save_failed":         "Gagal menyimpan fotografer",
+	"photographer.update_failed":       "Gagal update fotografer",
+	"photographer.photo_upload_failed": "Gagal menyimpan foto profil",
+	"photographer.deleted":             "Fotografer berhasil dihapus",
+	"photographer.restored":            "Fotografer berhasil dipulihkan",
+	"booking.fetch_failed":             "Gagal mengambil data booking",
+	"booking.save_failed":              "Gagal menyimpan booking",
+	"booking.update_failed":            "Gagal update booking",
+	"booking.updated":                  "Booking diperbarui",
+	"booking.deleted":                  "Booking dihapus",
+	"booking.restored":                 "Booking dipulihkan",
+	"gallery.fetch_failed":             "Gagal mengambil galeri",
+	"gallery.save_failed":              "Gagal menyimpan galeri",
+	"gallery.update_failed":            "Gagal update galeri",
+	"gallery.updated":                  "Galeri berhasil diperbarui",
+	"gallery.deleted":                  "Galeri berhasil dihapus",
+	"gallery.restored":                 "Galeri berhasil dipulihkan",
+	"transaction.fetch_failed":         "Gagal mengambil data transaksi",
+	"transaction.save_failed":          "Gagal menyimpan transaksi",
+	"transaction.created":              "Transaksi berhasil (dummy), booking dikonfirmasi",
+	"transaction.restored":             "Transaksi berhasil dipulihkan",
+	"transaction.deleted":              "Transaksi berhasil dihapus",
+	"trash.fetch_failed":               "Gagal mengambil data trash",
+	"audit.fetch_failed":               "Gagal mengambil log audit",
+	"event.replay_failed":              "Gagal mengambil event yang terlewat",
+	"webhook.fetch_failed":             "Gagal mengambil data webhook",
+	"webhook.save_failed":              "Gagal menyimpan webhook",
+	"webhook.deleted":                  "Webhook berhasil dihapus",
+	"webhook.requeued":                 "Delivery webhook dimasukkan kembali ke antrean",
+	"job.fetch_failed":                 "Gagal mengambil data job",
+	"job.requeued":                     "Job dijadwalkan ulang",
+	"calendar.token_created":           "Token kalender dibuat, simpan URL feed karena token hanya ditampilkan sekali",
+	"calendar.fetch_failed":            "Gagal membuat feed kalender",
+	"calendar.name":                    "Jadwal booking fotografi",
+	"calendar.summary":                 "Sesi foto: {client}",
+	"busy.fetch_failed":                "Gagal mengambil jadwal sibuk",
+	"busy.import_failed":               "Gagal mengimpor jadwal sibuk",
+	"busy.imported":                    "Jadwal sibuk berhasil diimpor",
+	"message.fetch_failed":             "Gagal mengambil pesan",
+	"message.save_failed":              "Gagal menyimpan pesan",
+	"message.attachment_upload_failed": "Gagal menyimpan lampiran pesan",
+	"message.empty":                    "Pesan harus berisi teks atau lampiran",
+	"message.image_only":               "{field} hanya boleh berisi gambar JPEG, PNG, WebP atau GIF",
+	"message.attachment_too_large":     "{field} maksimal {param} per file",
+	"analytics.fetch_failed":           "Gagal membuat laporan analitik",
+	"ledger.fetch_failed":              "Gagal mengambil buku besar",
+	"ledger.save_failed":               "Gagal menyimpan jurnal",
+	"ledger.refund_created":            "Refund berhasil dicatat",
+	"ledger.adjustment_created":        "Koreksi saldo berhasil dicatat",
+	"commission.fetch_failed":          "Gagal mengambil tarif komisi",
+	"commission.save_failed":           "Gagal menyimpan tarif komisi",
+	"commission.saved":                 "Tarif komisi berhasil disimpan",
+	"commission.deleted":               "Tarif komisi khusus dihapus, fotografer kembali memakai tarif default",
+	"payout.fetch_failed":              "Gagal mengambil batch payout",
+	"payout.save_failed":               "Gagal membuat batch payout",
+	"payout.created":                   "Batch payout berhasil dibuat",
+	"coupon.fetch_failed":              "Gagal mengambil kupon",
+	"coupon.save_failed":               "Gagal menyimpan kupon",
+	"coupon.created":                   "Kupon berhasil dibuat",
+	"coupon.updated":                   "Kupon berhasil diperbarui",
+	"waitlist.fetch_failed":            "Gagal mengambil waitlist",
+	"waitlist.joined":                  "Berhasil masuk waitlist, kami kabari jika tanggal ini kosong",
+	"waitlist.left":                    "Berhasil keluar dari waitlist",
+	"shortlist.fetch_failed":           "Gagal mengambil shortlist",
+	"shortlist.save_failed":            "Gagal menyimpan shortlist",
+	"shortlist.created":                "Shortlist berhasil dibuat",
+	"shortlist.updated":                "Shortlist berhasil diperbarui",
+	"shortlist.deleted":                "Shortlist berhasil dihapus",
+	"shortlist.item_added":             "Berhasil ditambahkan ke shortlist",
+	"shortlist.item_removed":           "Berhasil dihapus dari shortlist",
+	"shortlist.share_created":          "Link berbagi shortlist berhasil dibuat",
+	"shortlist.share_revoked":          "Link berbagi shortlist dinonaktifkan",
+
+	// Aturan validasi, {field} = nama field, {param} = parameter aturan
+	"validation.required":       "{field} wajib diisi",
+	"validation.min_string":     "{field} minimal {param} karakter",
+	"validation.min":            "{field} minimal {param}",
+	"validation.max_string":     "{field} maksimal {param} karakter",
+	"validation.max":            "{field} maksimal {param}",
+	"validation.gt":             "{field} harus lebih dari {param}",
+	"validation.email":          "Format email tidak valid",
+	"validation.url":            "{field} harus berupa URL yang valid",
+	"validation.oneof":          "{field} harus salah satu dari: {param}",
+	"validation.phone_id":       "Nomor telepon tidak valid",
+	"validation.objectid":       "{field} wajib diisi dengan ID yang valid",
+	"validation.booking_status": "Status booking tidak valid",
+	"validation.person_name":    "Nama harus 3-50 karakter dan hanya berisi huruf dan spasi",
+	"validation.json_array":     "Format {field} salah, harus berupa array JSON",
+	"validation.invalid":        "{field} tidak valid",
+
+	// Integritas relasi antar entitas
+	"integrity.not_found":                  "{field} menunjuk data yang tidak ada",
+	"integrity.role_mismatch":              "{field} harus milik user dengan role {role}",
+	"integrity.restricted.active_bookings": "Tidak bisa dihapus karena masih memiliki {count} booking aktif",
+	"integrity.restricted.bookings":        "Tidak bisa dihapus karena masih memiliki {count} booking",
+	"integrity.restricted.messages":        "Tidak bisa dihapus karena masih memiliki {count} pesan",
+	"integrity.restricted.galleries":       "Tidak bisa dihapus karena masih memiliki {count} galeri",
+	"integrity.restricted.transactions":    "Tidak bisa dihapus karena sudah memiliki {count} transaksi",
+
+	// Parameter query list
+	"query.positive_number":  "{field} harus berupa angka lebih dari 0",
+	"query.cursor_with_page": "cursor tidak bisa dipakai bersama page",
+	"query.invalid_cursor":   "Cursor tidak valid",
+	"query.invalid_date":     "{field} harus berupa tanggal YYYY-MM-DD atau RFC3339",
+	"query.invalid_number":   "{field} harus berupa angka",
+
+	// Impor kalender (ICS)
+	"calendar.invalid_ics":   "{field} bukan file iCalendar (.ics) yang valid",
+	"calendar.file_required": "{field} wajib berisi file .ics",
+
+	// Parameter laporan analitik
+	"analytics.invalid_timezone": "{field} harus berupa nama zona waktu IANA, misal Asia/Jakarta",
+	"analytics.invalid_range":    "{field} harus sebelum to",
+	"analytics.range_too_long":   "Rentang laporan maksimal {param} hari",
+
+	// Batch payout
+	"payout.future_cutoff": "{field} tidak boleh di masa depan",
+
+	// Kode promo
+	"coupon.not_found":      "{field} tidak dikenal",
+	"coupon.inactive":       "{field} sudah tidak aktif",
+	"coupon.not_started":    "{field} belum berlaku",
+	"coupon.expired":        "{field} sudah kedaluwarsa",
+	"coupon.not_applicable": "{field} tidak berlaku untuk fotografer ini",
+	"coupon.min_spend":      "{field} hanya berlaku untuk transaksi minimal Rp{param}",
+	"coupon.used_up":        "{field} sudah habis dipakai",
+	"coupon.client_limit":   "{field} sudah mencapai batas pemakaian Anda",
+	"coupon.percent_max":    "{field} maksimal 100 untuk potongan persen",
+	"coupon.invalid_window": "{field} harus setelah starts_at",
+
+	// Waitlist
+	"waitlist.past_date": "{field} sudah lewat",
+
+	// Shortlist
+	"shortlist.full":            "{field} maksimal {param} item",
+	"shortlist.target_required": "{field} wajib diisi sesuai jenis item",
+
+	// PATCH (JSON Merge Patch)
+	"patch.field_not_allowed": "{field} tidak bisa diubah",
+
+	// Template email dan notifikasi
+	"email.greeting":                  "Halo {name},",
+	"email.footer":                    "Terima kasih telah menggunakan layanan kami.",
+	"email.registration.subject":      "Selamat datang di Manajemen Fotografi",
+	"email.registration.body":         "Akun Anda sebagai {role} berhasil dibuat. Silakan login untuk mulai menggunakan layanan.",
+	"email.booking_created.subject":   "Booking baru untuk {date}",
+	"email.booking_created.body":      "Booking untuk tanggal {date} di {location} telah dibuat dan menunggu konfirmasi.",
+	"email.booking_confirmed.subject": "Booking {date} dikonfirmasi",
+	"email.booking_confirmed.body":    "Booking untuk tanggal {date} di {location} telah dikonfirmasi.",
+	"email.booking_cancelled.subject": "Booking {date} dibatalkan",
+	"email.booking_cancelled.body":    "Booking untuk tanggal {date} di {location} telah dibatalkan.",
+	"email.booking_reminder.subject":  "Pengingat: booking {date}",
+	"email.booking_reminder.body":     "Booking Anda di {location} tinggal {days} hari lagi, pada {date}.",
+	"email.booking_message.subject":   "Pesan baru untuk booking {date}",
+	"email.booking_message.body":      "Ada pesan baru pada booking {date} di {location}: \"{preview}\"",
+	"email.waitlist_offer.subject":    "Tanggal {date} tersedia untuk Anda",
+	"email.waitlist_offer.body":       "Tanggal {date} yang Anda tunggu kini kosong. Silakan booking sebelum {expires}, setelah itu tawaran diberikan ke antrean berikutnya.",
+	"email.payment_receipt.subject":   "Bukti pembayaran booking {date}",
+	"email.payment_receipt.body":      "Pembayaran sebesar {amount} melalui {method} telah kami terima.",
+	"email.gallery_delivered.subject": "Galeri \"{title}\" sudah tersedia",
+	"email.gallery_delivered.body":    "Foto Anda sudah dapat dilihat di {url}.",
+}
@@ -0,0 +1,121 @@
+package ical
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType adalah media type feed iCalendar
+const ContentType = "text/calendar; charset=utf-8"
+
+// Status VEVENT
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusTentative = "TENTATIVE"
+	StatusCancelled = "CANCELLED"
+)
+
+// maxLineOctets adalah panjang baris maksimal sebelum dilipat (RFC 5545 bagian 3.1)
+const maxLineOctets = 75
+
+// Calendar adalah satu VCALENDAR
+type Calendar struct {
+	ProdID string
+	Name   string // X-WR-CALNAME, nama kalender yang ditampilkan aplikasi
+	Events []Event
+}
+
+// Event adalah satu VEVENT. Sequence dinaikkan setiap event berubah supaya aplikasi
+// kalender mengganti salinan lamanya.
+type Event struct {
+	UID         string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	Summary     string
+	Location    string
+	Description string
+	Status      string
+	Sequence    int64
+	Modified    time.Time
+	Transparent bool   // TRANSP:TRANSPARENT, event yang tidak membuat sibuk
+	RRule       string // aturan pengulangan mentah, hanya diisi saat parsing
+}
+
+// Encode menulis kalender dalam format iCalendar dengan akhir baris CRLF
+func Encode(cal Calendar) []byte {
+	var b bytes.Buffer
+	line := func(name, value string) { writeLine(&b, name+":"+value) }
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", cal.ProdID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if cal.Name != "" {
+		line("X-WR-CALNAME", Escape(cal.Name))
+	}
+	for _, e := range cal.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", e.UID)
+		line("DTSTAMP", formatUTC(e.Modified))
+		if e.AllDay {
+			line("DTSTART;VALUE=DATE", e.Start.Format("20060102"))
+			line("DTEND;VALUE=DATE", e.End.Format("20060102"))
+		} else {
+			line("DTSTART", formatUTC(e.Start))
+			line("DTEND", formatUTC(e.End))
+		}
+		line("SUMMARY", Escape(e.Summary))
+		if e.Location != "" {
+			line("LOCATION", Escape(e.Location))
+		}
+		if e.Description != "" {
+			line("DESCRIPTION", Escape(e.Description))
+		}
+		if e.Status != "" {
+			line("STATUS", e.Status)
+		}
+		line("SEQUENCE", strconv.FormatInt(e.Sequence, 10))
+		line("LAST-MODIFIED", formatUTC(e.Modified))
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return b.Bytes()
+}
+
+func formatUTC(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// Escape meng-escape teks sesuai RFC 5545: backslash, titik koma, koma dan baris baru
+func Escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+// writeLine menulis satu content line dan melipatnya per 75 oktet tanpa memotong karakter UTF-8
+func writeLine(b *bytes.Buffer, s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		limit = maxLineOctets - 1 // spasi pelipat ikut dihitung
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+}
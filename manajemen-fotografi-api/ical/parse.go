@@ -0,0 +1,201 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxParseLine membatasi panjang satu baris agar file rusak tidak menghabiskan memori
+const maxParseLine = 1 << 20
+
+// durationRegex membaca DURATION sederhana seperti PT1H30M, P1D atau P1W
+var durationRegex = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// property adalah satu content line yang sudah dipisah: NAME;PARAM=...:VALUE
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse membaca semua VEVENT dari data iCalendar. Waktu tanpa zona (floating) dan TZID
+// yang tidak dikenal dibaca dalam loc. Komponen lain (VALARM, VTIMEZONE, VTODO) diabaikan.
+func Parse(r io.Reader, loc *time.Location) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		events  []Event
+		current *Event
+		depth   int // kedalaman komponen di dalam VEVENT, misal VALARM
+		hasEnd  bool
+		dur     time.Duration
+		calSeen bool
+	)
+	for i, raw := range lines {
+		if raw == "" {
+			continue
+		}
+		p, err := parseProperty(raw)
+		if err != nil {
+			return nil, fmt.Errorf("baris %d: %w", i+1, err)
+		}
+
+		switch {
+		case p.name == "BEGIN" && p.value == "VCALENDAR":
+			calSeen = true
+		case p.name == "BEGIN" && p.value == "VEVENT" && current == nil:
+			current, depth, hasEnd, dur = &Event{}, 0, false, 0
+		case p.name == "BEGIN" && current != nil:
+			depth++
+		case p.name == "END" && current != nil && depth > 0:
+			depth--
+		case p.name == "END" && p.value == "VEVENT" && current != nil:
+			if current.Start.IsZero() {
+				return nil, fmt.Errorf("baris %d: VEVENT tanpa DTSTART", i+1)
+			}
+			if !hasEnd {
+				switch {
+				case dur > 0:
+					current.End = current.Start.Add(dur)
+				case current.AllDay:
+					current.End = current.Start.AddDate(0, 0, 1)
+				default:
+					current.End = current.Start
+				}
+			}
+			events = append(events, *current)
+			current = nil
+		case current != nil && depth == 0:
+			if err := current.set(p, loc, &hasEnd, &dur); err != nil {
+				return nil, fmt.Errorf("baris %d: %w", i+1, err)
+			}
+		}
+	}
+
+	if !calSeen {
+		return nil, fmt.Errorf("bukan data iCalendar")
+	}
+	if current != nil {
+		return nil, fmt.Errorf("VEVENT tidak ditutup")
+	}
+	return events, nil
+}
+
+func (e *Event) set(p property, loc *time.Location, hasEnd *bool, dur *time.Duration) error {
+	var err error
+	switch p.name {
+	case "UID":
+		e.UID = p.value
+	case "SUMMARY":
+		e.Summary = unescape(p.value)
+	case "LOCATION":
+		e.Location = unescape(p.value)
+	case "DESCRIPTION":
+		e.Description = unescape(p.value)
+	case "STATUS":
+		e.Status = strings.ToUpper(p.value)
+	case "TRANSP":
+		e.Transparent = strings.EqualFold(p.value, "TRANSPARENT")
+	case "RRULE":
+		e.RRule = p.value
+	case "SEQUENCE":
+		e.Sequence, _ = strconv.ParseInt(p.value, 10, 64)
+	case "DTSTART":
+		e.Start, e.AllDay, err = parseTime(p, loc)
+	case "DTEND":
+		e.End, _, err = parseTime(p, loc)
+		*hasEnd = err == nil
+	case "DURATION":
+		*dur, err = parseDuration(p.value)
+	}
+	return err
+}
+
+// unfold membaca baris dan menyambung baris lanjutan (diawali spasi atau tab)
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxParseLine)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseProperty memisah NAME;PARAM=VALUE;...:VALUE, titik dua di dalam tanda kutip diabaikan
+func parseProperty(line string) (property, error) {
+	inQuote, colon := false, -1
+	for i, r := range line {
+		if r == '"' {
+			inQuote = !inQuote
+		} else if r == ':' && !inQuote {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return property{}, fmt.Errorf("baris tidak valid")
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	p := property{name: strings.ToUpper(parts[0]), params: map[string]string{}, value: line[colon+1:]}
+	for _, param := range parts[1:] {
+		if k, v, ok := strings.Cut(param, "="); ok {
+			p.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return p, nil
+}
+
+// parseTime membaca DATE (seharian) atau DATE-TIME dalam UTC, TZID atau floating
+func parseTime(p property, loc *time.Location) (time.Time, bool, error) {
+	if p.params["VALUE"] == "DATE" || len(p.value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", p.value, loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(p.value, "Z") {
+		t, err := time.Parse("20060102T150405Z", p.value)
+		return t, false, err
+	}
+	if tzid := p.params["TZID"]; tzid != "" {
+		if tz, err := time.LoadLocation(tzid); err == nil {
+			loc = tz
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", p.value, loc)
+	return t, false, err
+}
+
+func parseDuration(value string) (time.Duration, error) {
+	m := durationRegex.FindStringSubmatch(value)
+	if m == nil {
+		return 0, fmt.Errorf("DURATION tidak valid: %s", value)
+	}
+	n := func(s string) time.Duration {
+		v, _ := strconv.Atoi(s)
+		return time.Duration(v)
+	}
+	d := n(m[2])*7*24*time.Hour + n(m[3])*24*time.Hour + n(m[4])*time.Hour + n(m[5])*time.Minute + n(m[6])*time.Second
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+func unescape(s string) string {
+	return strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n").Replace(s)
+}
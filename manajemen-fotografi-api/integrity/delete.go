@@ -0,0 +1,233 @@
+package integrity
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"manajemen-fotografi-api/logging"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Policy menentukan nasib dokumen anak saat dokumen induk dihapus
+type Policy string
+
+const (
+	Restrict  Policy = "restrict"  // tolak penghapusan selama masih ada dokumen anak
+	Cascade   Policy = "cascade"   // hapus dokumen anak
+	Anonymize Policy = "anonymize" // pertahankan riwayat, referensi diganti DeletedRef saat purge
+)
+
+// DeletedRef menggantikan referensi ke dokumen yang sudah dihapus pada riwayat yang dianonimkan.
+// ObjectID nol tetap bertipe objectId sehingga lolos JSON schema validator.
+var DeletedRef = primitive.NilObjectID
+
+// Rule adalah relasi induk -> anak beserta kebijakan hapusnya.
+// Filter membatasi dokumen anak yang terkena aturan, misal hanya booking aktif.
+type Rule struct {
+	Name       string
+	Collection string
+	Field      string
+	Filter     bson.M
+	Policy     Policy
+}
+
+var activeBookings = bson.M{"status": bson.M{"$in": bson.A{models.BookingStatusPending, models.BookingStatusConfirmed}}}
+
+// rules adalah satu-satunya tempat kebijakan hapus antar entitas.
+// Aturan dievaluasi berurutan: semua Restrict dicek dulu sebelum ada data yang diubah.
+// Kebijakan bisa diganti lewat env DELETE_POLICY_<INDUK>_<NAMA>, misal
+// DELETE_POLICY_PHOTOGRAPHERS_GALLERIES=anonymize.
+var rules = map[string][]Rule{
+	"clients": {
+		{Name: "active_bookings", Collection: "bookings", Field: "client_id", Filter: activeBookings, Policy: Restrict},
+		{Name: "bookings", Collection: "bookings", Field: "client_id", Policy: Anonymize},
+	},
+	"photographers": {
+		{Name: "active_bookings", Collection: "bookings", Field: "photographer_id", Filter: activeBookings, Policy: Restrict},
+		{Name: "galleries", Collection: "galleries", Field: "photographer_id", Policy: Cascade},
+		{Name: "bookings", Collection: "bookings", Field: "photographer_id", Policy: Anonymize},
+	},
+	"bookings": {
+		{Name: "transactions", Collection: "transactions", Field: "booking_id", Policy: Restrict},
+		{Name: "messages", Collection: "messages", Field: "booking_id", Policy: Cascade},
+	},
+}
+
+// Rules mengembalikan aturan hapus untuk collection induk setelah override env diterapkan
+func Rules(parent string) []Rule {
+	out := make([]Rule, 0, len(rules[parent]))
+	for _, r := range rules[parent] {
+		env := "DELETE_POLICY_" + strings.ToUpper(parent+"_"+r.Name)
+		switch p := Policy(strings.ToLower(os.Getenv(env))); p {
+		case Restrict, Cascade, Anonymize:
+			r.Policy = p
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// parents adalah referensi ke induk yang harus masih aktif saat dokumen dipulihkan dari trash
+var parents = map[string][]Ref{
+	"bookings":     {Client("client_id", DeletedRef), Photographer("photographer_id", DeletedRef)},
+	"galleries":    {Photographer("photographer_id", DeletedRef)},
+	"transactions": {Booking("booking_id", DeletedRef)},
+}
+
+// uniqueRefs adalah field unik di antara dokumen aktif (index unik parsial). Profil yang
+// dipulihkan bentrok jika user yang sama sudah membuat profil baru selama profil lama di trash.
+var uniqueRefs = map[string]struct {
+	Field     string
+	Duplicate *utils.AppError
+}{
+	"clients":       {Field: "user_id", Duplicate: utils.ErrClientExists},
+	"photographers": {Field: "user_id", Duplicate: utils.ErrPhotographerExists},
+}
+
+// Delete memindahkan dokumen induk ke trash setelah menerapkan aturan hapus.
+// Restrict dicek terhadap dokumen anak yang aktif, Cascade ikut memindahkan anak ke trash
+// dengan waktu hapus yang sama. Anonymize baru diterapkan saat Purge, supaya Restore
+// tetap bisa mengembalikan riwayat secara utuh.
+// notFound dikembalikan jika dokumen induk tidak ada atau sudah di trash.
+func Delete(ctx context.Context, parent string, id primitive.ObjectID, notFound *utils.AppError) error {
+	parentColl := collection(parent)
+
+	count, err := parentColl.CountDocuments(ctx, bson.M{"_id": id})
+	if err != nil {
+		return utils.ErrDatabase.Wrap(err)
+	}
+	if count == 0 {
+		return notFound
+	}
+
+	applicable := Rules(parent)
+	for _, r := range applicable {
+		if r.Policy != Restrict {
+			continue
+		}
+		n, err := collection(r.Collection).CountDocuments(ctx, r.filter(id))
+		if err != nil {
+			return utils.ErrDatabase.Wrap(err)
+		}
+		if n > 0 {
+			return utils.ErrDeleteRestricted.WithKey("integrity.restricted."+r.Name, map[string]string{
+				"count": strconv.FormatInt(n, 10),
+			})
+		}
+	}
+
+	// Urutan anak dulu baru induk, sehingga kegagalan di tengah tidak meninggalkan anak yatim
+	ctx = repositories.WithDeletedAt(ctx, time.Now())
+	for _, r := range applicable {
+		if r.Policy != Cascade {
+			continue
+		}
+		res, err := collection(r.Collection).DeleteMany(ctx, r.filter(id))
+		if err != nil {
+			return utils.ErrDatabase.Wrap(err)
+		}
+		logging.FromContext(ctx).InfoContext(ctx, "hapus berantai",
+			"parent", parent, "collection", r.Collection, "deleted", res.DeletedCount)
+	}
+
+	if _, err := parentColl.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		return utils.ErrDatabase.Wrap(err)
+	}
+	return nil
+}
+
+// Restore mengembalikan dokumen dari trash beserta anak yang ikut terhapus bersamanya (Cascade).
+// Induk dokumen (misal fotografer dari sebuah galeri) harus masih aktif, dan profil tidak
+// boleh dipulihkan jika user-nya sudah punya profil aktif lain.
+func Restore(ctx context.Context, parent string, id primitive.ObjectID, notFound *utils.AppError) error {
+	unscoped := repositories.Unscoped(parent)
+
+	var doc bson.M
+	err := unscoped.FindOne(ctx, bson.M{"_id": id, "deleted_at": repositories.Deleted["deleted_at"]}).Decode(&doc)
+	if err != nil {
+		return utils.NotFoundOr(err, notFound)
+	}
+
+	var refs []Ref
+	for _, ref := range parents[parent] {
+		if refID, ok := doc[ref.Field].(primitive.ObjectID); ok && refID != DeletedRef {
+			ref.ID = refID
+			refs = append(refs, ref)
+		}
+	}
+	if err := Exists(ctx, refs...); err != nil {
+		return err
+	}
+
+	unique, hasUnique := uniqueRefs[parent]
+	if hasUnique {
+		n, err := collection(parent).CountDocuments(ctx, bson.M{
+			unique.Field: doc[unique.Field],
+			"_id":        bson.M{"$ne": id},
+		}, options.Count().SetLimit(1))
+		if err != nil {
+			return utils.ErrDatabase.Wrap(err)
+		}
+		if n > 0 {
+			return unique.Duplicate
+		}
+	}
+
+	restore := bson.M{
+		"$set": bson.M{"deleted_at": nil, "deleted_by": nil},
+		"$inc": bson.M{"version": 1}, // collection unscoped tidak menaikkan version otomatis
+	}
+	for _, r := range Rules(parent) {
+		if r.Policy != Cascade {
+			continue
+		}
+		filter := r.filter(id)
+		filter["deleted_at"] = doc["deleted_at"]
+		if _, err := repositories.Unscoped(r.Collection).UpdateMany(ctx, filter, restore); err != nil {
+			return utils.ErrDatabase.Wrap(err)
+		}
+	}
+
+	if _, err := unscoped.UpdateOne(ctx, bson.M{"_id": id}, restore); err != nil {
+		// Profil baru bisa dibuat di antara pengecekan di atas dan update ini
+		if hasUnique {
+			return utils.DuplicateOr(err, unique.Duplicate, utils.ErrDatabase)
+		}
+		return utils.ErrDatabase.Wrap(err)
+	}
+	return nil
+}
+
+// Purge menghapus permanen dokumen yang sudah di trash. Riwayat dengan kebijakan Anonymize
+// dipertahankan dengan referensi diganti DeletedRef; anak Cascade sudah ada di trash dan
+// dihapus permanen oleh purge miliknya sendiri.
+func Purge(ctx context.Context, parent string, id primitive.ObjectID) error {
+	for _, r := range Rules(parent) {
+		if r.Policy != Anonymize {
+			continue
+		}
+		_, err := repositories.Unscoped(r.Collection).UpdateMany(ctx, r.filter(id), bson.M{"$set": bson.M{r.Field: DeletedRef}})
+		if err != nil {
+			return err
+		}
+	}
+	_, err := repositories.Unscoped(parent).DeleteOne(ctx, bson.M{"_id": id, "deleted_at": repositories.Deleted["deleted_at"]})
+	return err
+}
+
+func (r Rule) filter(id primitive.ObjectID) bson.M {
+	filter := bson.M{r.Field: id}
+	for k, v := range r.Filter {
+		filter[k] = v
+	}
+	return filter
+}
@@ -0,0 +1,83 @@
+package integrity
+
+import (
+	"context"
+	"errors"
+
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// collection diambil saat dipakai, bukan saat package di-load,
+// supaya aturan relasi bisa diuji tanpa koneksi database
+var collection = repositories.New
+
+// Ref adalah satu referensi ke dokumen lain yang harus ada, misal booking.client_id -> clients.
+// Field adalah nama field input yang dilaporkan jika referensi tidak valid.
+type Ref struct {
+	Field      string
+	Collection string
+	ID         primitive.ObjectID
+}
+
+// Client, Photographer dan Booking membuat Ref untuk field input tertentu
+func Client(field string, id primitive.ObjectID) Ref {
+	return Ref{Field: field, Collection: "clients", ID: id}
+}
+
+func Photographer(field string, id primitive.ObjectID) Ref {
+	return Ref{Field: field, Collection: "photographers", ID: id}
+}
+
+func Booking(field string, id primitive.ObjectID) Ref {
+	return Ref{Field: field, Collection: "bookings", ID: id}
+}
+
+// Exists memastikan semua referensi menunjuk dokumen yang ada.
+// Semua referensi yang hilang dilaporkan sekaligus sebagai VALIDATION_FAILED.
+func Exists(ctx context.Context, refs ...Ref) error {
+	var invalid *utils.AppError
+	for _, ref := range refs {
+		count, err := collection(ref.Collection).CountDocuments(ctx, bson.M{"_id": ref.ID}, options.Count().SetLimit(1))
+		if err != nil {
+			return utils.ErrDatabase.Wrap(err)
+		}
+		if count == 0 {
+			invalid = appendInvalid(invalid, ref.Field, "integrity.not_found")
+		}
+	}
+	if invalid != nil {
+		return invalid
+	}
+	return nil
+}
+
+// UserWithRole memastikan user_id menunjuk user yang ada dan memiliki role yang sesuai,
+// misal profil client hanya boleh dibuat untuk user dengan role client
+func UserWithRole(ctx context.Context, userID primitive.ObjectID, role string) error {
+	var user models.User
+	err := collection("users").FindOne(ctx, bson.M{"_id": userID}).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return utils.Invalid("user_id", "integrity.not_found")
+	}
+	if err != nil {
+		return utils.ErrDatabase.Wrap(err)
+	}
+	if user.Role != role {
+		return utils.InvalidWith("user_id", "integrity.role_mismatch", map[string]string{"field": "user_id", "role": role})
+	}
+	return nil
+}
+
+func appendInvalid(err *utils.AppError, field, key string) *utils.AppError {
+	if err == nil {
+		return utils.Invalid(field, key)
+	}
+	return err.WithDetails(utils.Invalid(field, key).Details...)
+}
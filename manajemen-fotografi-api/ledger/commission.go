@@ -0,0 +1,109 @@
+package ledger
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"manajemen-fotografi-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RateCollection menyimpan tarif komisi default dan per fotografer
+const RateCollection = "commission_rates"
+
+// DefaultRate adalah tarif komisi jika belum diatur admin, bisa diganti lewat
+// PLATFORM_COMMISSION_RATE (0 sampai 1)
+const DefaultRate = 0.2
+
+// DefaultRateKey adalah key tarif yang berlaku untuk semua fotografer
+const DefaultRateKey = "default"
+
+// RateKey adalah key tarif khusus satu fotografer
+func RateKey(photographerID primitive.ObjectID) string {
+	return "photographer:" + photographerID.Hex()
+}
+
+// EnvRate membaca PLATFORM_COMMISSION_RATE, default 20%
+func EnvRate() float64 {
+	rate, err := strconv.ParseFloat(os.Getenv("PLATFORM_COMMISSION_RATE"), 64)
+	if err != nil || rate < 0 || rate > 1 {
+		return DefaultRate
+	}
+	return rate
+}
+
+// RateFor mengembalikan tarif komisi fotografer: tarif khusus fotografer, lalu tarif
+// default dari admin, lalu PLATFORM_COMMISSION_RATE
+func RateFor(ctx context.Context, photographerID primitive.ObjectID) (float64, error) {
+	cursor, err := collection(RateCollection).Find(ctx,
+		bson.M{"key": bson.M{"$in": bson.A{RateKey(photographerID), DefaultRateKey}}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	var rates []models.CommissionRate
+	if err := cursor.All(ctx, &rates); err != nil {
+		return 0, err
+	}
+
+	rate := EnvRate()
+	for _, r := range rates {
+		if r.Key != DefaultRateKey {
+			return r.Rate, nil
+		}
+		rate = r.Rate
+	}
+	return rate, nil
+}
+
+// Rates menampilkan semua tarif yang diatur admin
+func Rates(ctx context.Context) ([]models.CommissionRate, error) {
+	cursor, err := collection(RateCollection).Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "key", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	rates := []models.CommissionRate{}
+	return rates, cursor.All(ctx, &rates)
+}
+
+// SetRate menyimpan tarif default (photographerID nil) atau tarif khusus fotografer.
+// Tarif baru hanya berlaku untuk pembayaran berikutnya, jurnal lama menyimpan tarifnya sendiri.
+func SetRate(ctx context.Context, photographerID *primitive.ObjectID, rate float64, actor *primitive.ObjectID) (models.CommissionRate, error) {
+	doc := models.CommissionRate{
+		Key:            DefaultRateKey,
+		PhotographerID: photographerID,
+		Rate:           rate,
+		UpdatedBy:      actor,
+		UpdatedAt:      time.Now(),
+	}
+	if photographerID != nil {
+		doc.Key = RateKey(*photographerID)
+	}
+
+	err := collection(RateCollection).FindOneAndUpdate(ctx,
+		bson.M{"key": doc.Key},
+		bson.M{"$set": bson.M{
+			"photographer_id": doc.PhotographerID,
+			"rate":            doc.Rate,
+			"updated_by":      doc.UpdatedBy,
+			"updated_at":      doc.UpdatedAt,
+		}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&doc)
+	return doc, err
+}
+
+// DeleteRate menghapus tarif khusus fotografer sehingga kembali memakai tarif default.
+// Mengembalikan false jika fotografer tidak punya tarif khusus.
+func DeleteRate(ctx context.Context, photographerID primitive.ObjectID) (bool, error) {
+	result, err := collection(RateCollection).DeleteOne(ctx, bson.M{"key": RateKey(photographerID)})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
@@ -0,0 +1,234 @@
+// Package ledger mencatat pendapatan fotografer secara double-entry: setiap transaksi lunas,
+// refund, koreksi dan payout menjadi jurnal yang debit dan kreditnya selalu seimbang.
+// Saldo fotografer adalah saldo akun photographer_payable miliknya.
+package ledger
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// JournalCollection menyimpan semua jurnal
+const JournalCollection = "ledger_journals"
+
+// Akun buku besar
+const (
+	AccountCash        = "platform_cash"        // kas platform: pembayaran client masuk, refund dan payout keluar
+	AccountPayable     = "photographer_payable" // utang platform ke fotografer
+	AccountCommission  = "platform_commission"  // pendapatan komisi platform
+	AccountAdjustments = "platform_adjustments" // beban/pendapatan dari koreksi manual
+)
+
+// collection diambil saat dipakai, bukan saat package di-load,
+// supaya aturan jurnal bisa diuji tanpa koneksi database
+var collection = repositories.New
+
+// ErrUnbalanced dikembalikan jika total debit dan kredit jurnal tidak sama
+var ErrUnbalanced = errors.New("jurnal tidak seimbang")
+
+// Amount mengubah nominal transaksi ke rupiah bulat
+func Amount(total float64) int64 {
+	return int64(math.Round(total))
+}
+
+// Commission menghitung komisi platform dari amount dengan tarif rate, dibulatkan ke rupiah
+func Commission(amount int64, rate float64) int64 {
+	return int64(math.Round(float64(amount) * rate))
+}
+
+// Balanced memastikan jurnal punya minimal dua baris, setiap baris hanya debit atau kredit
+// dengan nominal positif, dan total debit sama dengan total kredit
+func Balanced(lines []models.LedgerLine) error {
+	if len(lines) < 2 {
+		return ErrUnbalanced
+	}
+	var debit, credit int64
+	for _, line := range lines {
+		if line.Debit < 0 || line.Credit < 0 || (line.Debit == 0) == (line.Credit == 0) {
+			return ErrUnbalanced
+		}
+		debit += line.Debit
+		credit += line.Credit
+	}
+	if debit != credit {
+		return ErrUnbalanced
+	}
+	return nil
+}
+
+// lines menyusun baris jurnal dan membuang baris bernilai nol, misal komisi 0%
+func lines(entries ...models.LedgerLine) []models.LedgerLine {
+	var out []models.LedgerLine
+	for _, line := range entries {
+		if line.Debit != 0 || line.Credit != 0 {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// PaymentLines: kas masuk sebesar gross, dibagi menjadi utang ke fotografer dan komisi platform
+func PaymentLines(gross, commission int64) []models.LedgerLine {
+	return lines(
+		models.LedgerLine{Account: AccountCash, Debit: gross},
+		models.LedgerLine{Account: AccountPayable, Credit: gross - commission},
+		models.LedgerLine{Account: AccountCommission, Credit: commission},
+	)
+}
+
+// RefundLines membalik pembayaran sebesar amount: bagian fotografer dan komisi dikurangi
+// sesuai proporsi pembayaran, kas keluar ke client
+func RefundLines(amount, commission int64) []models.LedgerLine {
+	return lines(
+		models.LedgerLine{Account: AccountPayable, Debit: amount - commission},
+		models.LedgerLine{Account: AccountCommission, Debit: commission},
+		models.LedgerLine{Account: AccountCash, Credit: amount},
+	)
+}
+
+// AdjustmentLines menambah (amount positif) atau mengurangi (negatif) saldo fotografer
+func AdjustmentLines(amount int64) []models.LedgerLine {
+	if amount < 0 {
+		return lines(
+			models.LedgerLine{Account: AccountPayable, Debit: -amount},
+			models.LedgerLine{Account: AccountAdjustments, Credit: -amount},
+		)
+	}
+	return lines(
+		models.LedgerLine{Account: AccountAdjustments, Debit: amount},
+		models.LedgerLine{Account: AccountPayable, Credit: amount},
+	)
+}
+
+// PayoutLines melunasi utang ke fotografer dari kas platform
+func PayoutLines(amount int64) []models.LedgerLine {
+	return lines(
+		models.LedgerLine{Account: AccountPayable, Debit: amount},
+		models.LedgerLine{Account: AccountCash, Credit: amount},
+	)
+}
+
+// Payable adalah perubahan saldo fotografer akibat sebuah jurnal
+func Payable(lines []models.LedgerLine) int64 {
+	var amount int64
+	for _, line := range lines {
+		if line.Account == AccountPayable {
+			amount += line.Credit - line.Debit
+		}
+	}
+	return amount
+}
+
+// post memvalidasi lalu menyimpan jurnal. Key yang sudah ada dianggap duplikat.
+func post(ctx context.Context, journal *models.LedgerJournal) error {
+	if err := Balanced(journal.Lines); err != nil {
+		return err
+	}
+	journal.ID = primitive.NewObjectID()
+	journal.CreatedAt = time.Now()
+	_, err := collection(JournalCollection).InsertOne(ctx, journal)
+	return err
+}
+
+// RecordPayment mencatat jurnal pembayaran untuk transaksi lunas. Aman dipanggil berulang
+// kali (dari event dan rekonsiliasi) karena key jurnal unik per transaksi.
+func RecordPayment(ctx context.Context, trx models.Transaction) error {
+	// Transaksi yang lunas penuh dengan kupon tidak menggerakkan kas, jadi tidak perlu jurnal
+	if trx.Status != models.TransactionStatusPaid || Amount(trx.Total) == 0 {
+		return nil
+	}
+
+	var booking models.Booking
+	err := repositories.Unscoped("bookings").FindOne(ctx, bson.M{"_id": trx.BookingID},
+		options.FindOne().SetProjection(bson.M{"photographer_id": 1}),
+	).Decode(&booking)
+	if err != nil {
+		return err
+	}
+	rate, err := RateFor(ctx, booking.PhotographerID)
+	if err != nil {
+		return err
+	}
+
+	gross := Amount(trx.Total)
+	commission := Commission(gross, rate)
+	journal := models.LedgerJournal{
+		Key:            "payment:" + trx.ID.Hex(),
+		Kind:           models.JournalPayment,
+		PhotographerID: booking.PhotographerID,
+		TransactionID:  &trx.ID,
+		Gross:          gross,
+		Rate:           rate,
+		Lines:          PaymentLines(gross, commission),
+	}
+	if err := post(ctx, &journal); err != nil && !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	return nil
+}
+
+// Refund mencatat pengembalian dana sebesar amount atas transaksi trxID. Total refund tidak
+// bisa melebihi nominal pembayaran, juga saat beberapa refund diproses bersamaan, karena
+// batas itu dicek dan ditambahkan secara atomik pada jurnal pembayaran.
+func Refund(ctx context.Context, trxID primitive.ObjectID, amount int64, memo string, actor *primitive.ObjectID) (models.LedgerJournal, error) {
+	journals := collection(JournalCollection)
+	key := "payment:" + trxID.Hex()
+
+	var payment models.LedgerJournal
+	err := journals.FindOneAndUpdate(ctx,
+		bson.M{
+			"key":   key,
+			"$expr": bson.M{"$lte": bson.A{bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$refunded", 0}}, amount}}, "$gross"}},
+		},
+		bson.M{"$inc": bson.M{"refunded": amount}},
+	).Decode(&payment)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if n, countErr := journals.CountDocuments(ctx, bson.M{"key": key}); countErr == nil && n > 0 {
+			return payment, utils.ErrRefundExceedsPayment
+		}
+		return payment, utils.ErrPaymentNotRecorded
+	}
+	if err != nil {
+		return payment, err
+	}
+
+	journal := models.LedgerJournal{
+		Key:            "refund:" + primitive.NewObjectID().Hex(),
+		Kind:           models.JournalRefund,
+		PhotographerID: payment.PhotographerID,
+		TransactionID:  &trxID,
+		Lines:          RefundLines(amount, Commission(amount, payment.Rate)),
+		Memo:           memo,
+		CreatedBy:      actor,
+	}
+	if err := post(ctx, &journal); err != nil {
+		// Kembalikan kuota refund supaya bisa dicoba lagi
+		_, _ = journals.UpdateOne(ctx, bson.M{"_id": payment.ID}, bson.M{"$inc": bson.M{"refunded": -amount}})
+		return journal, err
+	}
+	return journal, nil
+}
+
+// Adjust mencatat koreksi manual saldo fotografer, amount positif menambah saldo
+func Adjust(ctx context.Context, photographerID primitive.ObjectID, amount int64, memo string, actor *primitive.ObjectID) (models.LedgerJournal, error) {
+	journal := models.LedgerJournal{
+		Key:            "adjustment:" + primitive.NewObjectID().Hex(),
+		Kind:           models.JournalAdjustment,
+		PhotographerID: photographerID,
+		Lines:          AdjustmentLines(amount),
+		Memo:           memo,
+		CreatedBy:      actor,
+	}
+	return journal, post(ctx, &journal)
+}
@@ -0,0 +1,182 @@
+package ledger
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"manajemen-fotografi-api/logging"
+	"manajemen-fotografi-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// PayoutCollection menyimpan batch payout
+const PayoutCollection = "payout_batches"
+
+// GeneratePayouts membuat batch payout untuk semua jurnal yang belum dilunasi dan dibuat
+// sebelum cutoff. Jurnal diklaim dulu dengan payout_id batch ini, baru dijumlahkan, sehingga
+// dua batch yang dibuat bersamaan tidak pernah membayar jurnal yang sama. Fotografer dengan
+// saldo nol atau negatif (misal karena refund setelah payout) tidak dibayar dan jurnalnya
+// dibiarkan untuk batch berikutnya. Jika gagal di tengah jalan, klaim dilepas dan batch
+// ditandai failed supaya jurnalnya ikut batch berikutnya.
+func GeneratePayouts(ctx context.Context, cutoff time.Time, actor *primitive.ObjectID) (models.PayoutBatch, error) {
+	batch := models.PayoutBatch{
+		ID:        primitive.NewObjectID(),
+		Status:    models.PayoutProcessing,
+		Cutoff:    cutoff,
+		Items:     []models.PayoutItem{},
+		CreatedBy: actor,
+		CreatedAt: time.Now(),
+	}
+	if _, err := collection(PayoutCollection).InsertOne(ctx, batch); err != nil {
+		return batch, err
+	}
+
+	if err := generate(ctx, &batch, cutoff, actor); err != nil {
+		abandon(ctx, &batch)
+		return batch, err
+	}
+	return batch, nil
+}
+
+// generate mengklaim jurnal untuk batch, memposting jurnal payout per fotografer,
+// lalu menandai batch ready
+func generate(ctx context.Context, batch *models.PayoutBatch, cutoff time.Time, actor *primitive.ObjectID) error {
+	journals := collection(JournalCollection)
+	_, err := journals.UpdateMany(ctx,
+		bson.M{"payout_id": nil, "kind": bson.M{"$ne": models.JournalPayout}, "created_at": bson.M{"$lt": cutoff}},
+		bson.M{"$set": bson.M{"payout_id": batch.ID}},
+	)
+	if err != nil {
+		return err
+	}
+
+	cursor, err := journals.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"payout_id": batch.ID}}},
+		{{Key: "$unwind", Value: "$lines"}},
+		{{Key: "$match", Value: bson.M{"lines.account": AccountPayable}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      "$photographer_id",
+			"amount":   bson.M{"$sum": bson.M{"$subtract": bson.A{"$lines.credit", "$lines.debit"}}},
+			"journals": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	})
+	if err != nil {
+		return err
+	}
+	var totals []struct {
+		PhotographerID primitive.ObjectID `bson:"_id"`
+		Amount         int64              `bson:"amount"`
+		Journals       int                `bson:"journals"`
+	}
+	if err := cursor.All(ctx, &totals); err != nil {
+		return err
+	}
+
+	for _, t := range totals {
+		if t.Amount <= 0 {
+			_, err := journals.UpdateMany(ctx,
+				bson.M{"payout_id": batch.ID, "photographer_id": t.PhotographerID},
+				bson.M{"$set": bson.M{"payout_id": nil}},
+			)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		journal := models.LedgerJournal{
+			Key:            "payout:" + batch.ID.Hex() + ":" + t.PhotographerID.Hex(),
+			Kind:           models.JournalPayout,
+			PhotographerID: t.PhotographerID,
+			Lines:          PayoutLines(t.Amount),
+			PayoutID:       &batch.ID,
+			CreatedBy:      actor,
+		}
+		if err := post(ctx, &journal); err != nil {
+			return err
+		}
+		batch.Items = append(batch.Items, models.PayoutItem{
+			PhotographerID: t.PhotographerID,
+			Amount:         t.Amount,
+			Journals:       t.Journals,
+			JournalID:      journal.ID,
+		})
+		batch.Total += t.Amount
+	}
+
+	_, err = collection(PayoutCollection).UpdateOne(ctx,
+		bson.M{"_id": batch.ID},
+		bson.M{"$set": bson.M{"status": models.PayoutReady, "items": batch.Items, "total": batch.Total}},
+	)
+	if err != nil {
+		return err
+	}
+	batch.Status = models.PayoutReady
+	return nil
+}
+
+// abandon membatalkan batch yang gagal dibuat: jurnal payout yang sudah diposting dihapus,
+// klaim jurnal dilepas, dan batch ditandai failed. Kegagalan di sini hanya dicatat ke log
+// karena error asal sudah dikembalikan ke pemanggil.
+func abandon(ctx context.Context, batch *models.PayoutBatch) {
+	ctx = context.WithoutCancel(ctx)
+	journals := collection(JournalCollection)
+	_, err := journals.DeleteMany(ctx, bson.M{"payout_id": batch.ID, "kind": models.JournalPayout})
+	if err == nil {
+		_, err = journals.UpdateMany(ctx, bson.M{"payout_id": batch.ID}, bson.M{"$set": bson.M{"payout_id": nil}})
+	}
+	if err == nil {
+		_, err = collection(PayoutCollection).UpdateOne(ctx,
+			bson.M{"_id": batch.ID},
+			bson.M{"$set": bson.M{"status": models.PayoutFailed, "items": []models.PayoutItem{}, "total": 0}},
+		)
+	}
+	if err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "gagal membatalkan batch payout", "payout_id", batch.ID.Hex(), "error", err)
+		return
+	}
+	batch.Status = models.PayoutFailed
+	batch.Items = []models.PayoutItem{}
+	batch.Total = 0
+}
+
+// Payee adalah data penerima payout untuk file bank
+type Payee struct {
+	Name  string
+	Phone string
+}
+
+// CSVHeader adalah kolom file payout untuk bank
+var CSVHeader = []string{"reference", "photographer_id", "name", "phone", "amount", "currency"}
+
+// WriteCSV menulis item batch sebagai CSV untuk diunggah ke bank. Reference berisi ID jurnal
+// payout supaya mutasi bank bisa dicocokkan kembali ke buku besar.
+func WriteCSV(w io.Writer, batch models.PayoutBatch, payees map[primitive.ObjectID]Payee) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(CSVHeader); err != nil {
+		return err
+	}
+	for _, item := range batch.Items {
+		payee := payees[item.PhotographerID]
+		err := out.Write([]string{
+			item.JournalID.Hex(),
+			item.PhotographerID.Hex(),
+			payee.Name,
+			payee.Phone,
+			strconv.FormatInt(item.Amount, 10),
+			"IDR",
+		})
+		if err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
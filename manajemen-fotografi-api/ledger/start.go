@@ -0,0 +1,114 @@
+package ledger
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"manajemen-fotografi-api/events"
+	"manajemen-fotografi-api/logging"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/scheduler"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// JobReconcile mencatat transaksi lunas yang jurnalnya terlewat, misal karena server mati
+// sebelum event diproses
+const JobReconcile = "ledger.reconcile"
+
+// Default rekonsiliasi, bisa diganti lewat LEDGER_RECONCILE_INTERVAL dan LEDGER_RECONCILE_WINDOW
+const (
+	DefaultReconcileInterval = time.Hour
+	DefaultReconcileWindow   = 7 * 24 * time.Hour
+)
+
+// Start mencatat jurnal pembayaran dari event transaction.paid dan mendaftarkan job
+// rekonsiliasi. Harus dipanggil sebelum scheduler.Start.
+func Start(ctx context.Context) {
+	go events.Consume(ctx, "ledger", HandleEvent)
+
+	scheduler.Register(JobReconcile, func(ctx context.Context, job models.Job) error {
+		return Reconcile(ctx, time.Now().Add(-durationFromEnv("LEDGER_RECONCILE_WINDOW", DefaultReconcileWindow)))
+	})
+	if err := scheduler.Every(ctx, JobReconcile, durationFromEnv("LEDGER_RECONCILE_INTERVAL", DefaultReconcileInterval)); err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "gagal mendaftarkan job berulang", "job", JobReconcile, "error", err)
+	}
+}
+
+// HandleEvent mencatat jurnal pembayaran untuk event transaction.paid
+func HandleEvent(ctx context.Context, event models.Event) {
+	if event.Type != events.TransactionPaid {
+		return
+	}
+	var trx models.Transaction
+	raw, err := bson.Marshal(event.Data["transaction"])
+	if err == nil {
+		err = bson.Unmarshal(raw, &trx)
+	}
+	if err == nil {
+		err = RecordPayment(ctx, trx)
+	}
+	if err != nil {
+		// Rekonsiliasi akan mencatatnya pada putaran berikutnya
+		logging.FromContext(ctx).ErrorContext(ctx, "gagal mencatat jurnal pembayaran",
+			"event_id", event.ID.Hex(), "error", err)
+	}
+}
+
+// Reconcile mencatat jurnal untuk transaksi lunas sejak since yang belum punya jurnal pembayaran
+func Reconcile(ctx context.Context, since time.Time) error {
+	cursor, err := collection("transactions").Find(ctx,
+		bson.M{"status": models.TransactionStatusPaid, "created_at": bson.M{"$gte": since}},
+	)
+	if err != nil {
+		return err
+	}
+	var paid []models.Transaction
+	if err := cursor.All(ctx, &paid); err != nil {
+		return err
+	}
+	if len(paid) == 0 {
+		return nil
+	}
+
+	ids := make(bson.A, 0, len(paid))
+	for _, trx := range paid {
+		ids = append(ids, trx.ID)
+	}
+	cursor, err = collection(JournalCollection).Find(ctx,
+		bson.M{"kind": models.JournalPayment, "transaction_id": bson.M{"$in": ids}},
+		options.Find().SetProjection(bson.M{"transaction_id": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	var recorded []models.LedgerJournal
+	if err := cursor.All(ctx, &recorded); err != nil {
+		return err
+	}
+	done := make(map[primitive.ObjectID]bool, len(recorded))
+	for _, journal := range recorded {
+		done[*journal.TransactionID] = true
+	}
+
+	for _, trx := range paid {
+		if done[trx.ID] {
+			continue
+		}
+		if err := RecordPayment(ctx, trx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
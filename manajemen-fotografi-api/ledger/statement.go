@@ -0,0 +1,103 @@
+package ledger
+
+import (
+	"context"
+	"time"
+
+	"manajemen-fotografi-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Statement adalah ringkasan pendapatan fotografer
+type Statement struct {
+	PhotographerID primitive.ObjectID `json:"photographer_id"`
+	Balance        int64              `json:"balance"` // saldo yang belum masuk batch payout
+	Period         Period             `json:"period"`
+}
+
+// Period adalah mutasi dalam rentang laporan. Refund berisi bagian fotografer yang dibalik.
+type Period struct {
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	Gross       int64     `json:"gross"`
+	Commission  int64     `json:"commission"`
+	Earnings    int64     `json:"earnings"`
+	Refunds     int64     `json:"refunds"`
+	Adjustments int64     `json:"adjustments"`
+	Payouts     int64     `json:"payouts"`
+}
+
+// statementRow adalah total satu akun per jenis jurnal, dipisah menurut apakah jurnal
+// jatuh di rentang laporan
+type statementRow struct {
+	ID struct {
+		Kind     string `bson:"kind"`
+		Account  string `bson:"account"`
+		InPeriod bool   `bson:"in_period"`
+	} `bson:"_id"`
+	Debit  int64 `bson:"debit"`
+	Credit int64 `bson:"credit"`
+}
+
+// StatementFor menghitung saldo fotografer dan mutasi pada [from, to)
+func StatementFor(ctx context.Context, photographerID primitive.ObjectID, from, to time.Time) (Statement, error) {
+	statement := Statement{PhotographerID: photographerID, Period: Period{From: from, To: to}}
+
+	cursor, err := collection(JournalCollection).Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"photographer_id": photographerID}}},
+		{{Key: "$unwind", Value: "$lines"}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"kind":    "$kind",
+				"account": "$lines.account",
+				"in_period": bson.M{"$and": bson.A{
+					bson.M{"$gte": bson.A{"$created_at", from}},
+					bson.M{"$lt": bson.A{"$created_at", to}},
+				}},
+			},
+			"debit":  bson.M{"$sum": "$lines.debit"},
+			"credit": bson.M{"$sum": "$lines.credit"},
+		}}},
+	})
+	if err != nil {
+		return statement, err
+	}
+	var rows []statementRow
+	if err := cursor.All(ctx, &rows); err != nil {
+		return statement, err
+	}
+
+	for _, row := range rows {
+		statement.add(row)
+	}
+	return statement, nil
+}
+
+func (s *Statement) add(row statementRow) {
+	net := row.Credit - row.Debit
+	if row.ID.Account == AccountPayable {
+		s.Balance += net
+	}
+	if !row.ID.InPeriod {
+		return
+	}
+
+	p := &s.Period
+	switch {
+	case row.ID.Kind == models.JournalPayment && row.ID.Account == AccountCash:
+		p.Gross += row.Debit
+	case row.ID.Kind == models.JournalPayment && row.ID.Account == AccountCommission:
+		p.Commission += row.Credit
+	case row.ID.Kind == models.JournalPayment && row.ID.Account == AccountPayable:
+		p.Earnings += net
+	case row.ID.Kind == models.JournalRefund && row.ID.Account == AccountPayable:
+		p.Refunds += row.Debit
+	case row.ID.Kind == models.JournalAdjustment && row.ID.Account == AccountPayable:
+		p.Adjustments += net
+	case row.ID.Kind == models.JournalPayout && row.ID.Account == AccountPayable:
+		p.Payouts += row.Debit
+	}
+}
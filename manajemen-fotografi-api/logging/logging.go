@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// RequestIDKey adalah nama field request ID di setiap baris log
+const RequestIDKey = "request_id"
+
+// TraceIDKey adalah nama field trace ID OpenTelemetry di setiap baris log
+const TraceIDKey = "trace_id"
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys berisi nama field yang nilainya tidak boleh muncul di log
+var sensitiveKeys = map[string]bool{
+	"password":      true,
+	"phone":         true,
+	"token":         true,
+	"authorization": true,
+	"cookie":        true,
+	"secret":        true,
+}
+
+type ctxKey struct{}
+
+func init() {
+	slog.SetDefault(New(os.Stdout, levelFromEnv()))
+}
+
+// New membuat logger JSON yang otomatis menyamarkan field sensitif
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redact,
+	}))
+}
+
+// levelFromEnv membaca LOG_LEVEL (debug, info, warn, error), default info
+func levelFromEnv() slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(os.Getenv("LOG_LEVEL"))); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+// redact mengganti nilai field sensitif, termasuk yang berada di dalam group
+func redact(groups []string, a slog.Attr) slog.Attr {
+	if IsSensitive(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+	return a
+}
+
+// IsSensitive mengecek apakah nama field termasuk data sensitif
+func IsSensitive(key string) bool {
+	key = strings.ToLower(key)
+	if sensitiveKeys[key] {
+		return true
+	}
+	for k := range sensitiveKeys {
+		if strings.HasSuffix(key, "_"+k) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithContext menyimpan logger ke context agar bisa dipakai handler dan repository
+func WithContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, logger)
+}
+
+// FromContext mengambil logger dari context, atau logger default jika belum ada
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+			return logger
+		}
+	}
+	return slog.Default()
+}
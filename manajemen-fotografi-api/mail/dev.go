@@ -0,0 +1,47 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"manajemen-fotografi-api/logging"
+)
+
+// FileMailer menyimpan setiap email sebagai file .eml di Dir, bisa dibuka dengan
+// aplikasi email biasa. Dipakai di development untuk memeriksa tampilan email.
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	raw, err := Build(m.From, msg)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405.000000"), filepath.Base(msg.To))
+	return os.WriteFile(filepath.Join(m.Dir, name), raw, 0o644)
+}
+
+// LogMailer hanya mencatat email ke log, default jika MAIL_DRIVER tidak diisi
+type LogMailer struct {
+	From string
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	if _, err := Build(m.From, msg); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "email (tidak dikirim)",
+		"to", msg.To,
+		"subject", msg.Subject,
+		"text", msg.Text,
+	)
+	return nil
+}
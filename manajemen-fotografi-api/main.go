@@ -1,35 +1,184 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 	"manajemen-fotografi-api/config"
+	"manajemen-fotografi-api/events"
+	"manajemen-fotografi-api/ledger"
 	"manajemen-fotografi-api/middlewares"
+	"manajemen-fotografi-api/migrations"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/notifications"
 	"manajemen-fotografi-api/routes" // Import routes
+	"manajemen-fotografi-api/scheduler"
+	"manajemen-fotografi-api/tracing"
+	"manajemen-fotografi-api/trash"
+	"manajemen-fotografi-api/utils"
+	"manajemen-fotografi-api/waitlist"
+	"manajemen-fotografi-api/webhooks"
 
 	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 func main() {
-	// Inisialisasi Fiber
-	app := fiber.New()
+	// Subcommand "migrate" menjalankan migrasi tanpa menyalakan server
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
 
-	// Setup middleware
+	// Subcommand "admin grant <email>" menjadikan user sebagai admin
+	if len(os.Args) > 1 && os.Args[1] == "admin" {
+		runAdmin(os.Args[2:])
+		return
+	}
 
-	middlewares.SetupLogger(app)
-	middlewares.SetupCORS(app)
+	// Setup tracing (exporter diatur lewat OTEL_TRACES_EXPORTER)
+	shutdownTracing, err := tracing.Setup(context.Background())
+	if err != nil {
+		log.Fatal("Gagal setup tracing: ", err)
+	}
+	defer shutdownTracing(context.Background())
 
-	// Koneksi ke database
+	// Inisialisasi Fiber
+	app := fiber.New(fiber.Config{
+		ErrorHandler: utils.ErrorHandler, // error yang lolos dari handler tetap memakai envelope standar
+	})
+
+	// Koneksi ke database (dibutuhkan juga oleh session store)
 	config.ConnectDB()
 
 	if config.MongoDatabase == nil {
 		log.Fatal("MongoDatabase is nil")
 	}
 
+	// Setup middleware
+
+	middlewares.SetupTracing(app)
+	middlewares.SetupLogger(app)
+	middlewares.SetupRecover(app)
+	middlewares.SetupAudit(app)
+	middlewares.SetupLocale(app)
+	middlewares.SetupMetrics(app)
+	middlewares.SetupCORS(app)
+	middlewares.SetupSession(app)
+
+	// Migrasi saat boot hanya jika MIGRATE_ON_BOOT=true
+	if err := migrations.RunOnBoot(context.Background(), config.MongoDatabase); err != nil {
+		log.Fatal("Gagal menjalankan migrasi: ", err)
+	}
+
   
 
+	// Purger trash berjalan di background sampai server berhenti
+	ctx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	trash.Start(ctx)
+	events.Start(ctx)
+	webhooks.Start(ctx)
+	notifications.Start(ctx, notifications.ChannelsFromEnv())
+	ledger.Start(ctx) // mendaftarkan job ke scheduler, jadi harus sebelum scheduler.Start
+	waitlist.Start(ctx)
+	scheduler.Start(ctx)
+
 	// Setup routes
 	routes.SetupRoutes(app) // Menghubungkan semua route yang sudah digabungkan di routes.go
 
+	// Handle 404 untuk route yang tidak terdaftar
+	app.Use(func(c *fiber.Ctx) error {
+		return utils.Error(c, utils.ErrRouteNotFound)
+	})
+
+	// Hentikan server dengan rapi saat menerima sinyal, supaya span sempat di-flush
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+		<-quit
+		stopBackground() // menutup stream SSE supaya shutdown tidak menunggu koneksi yang tidak pernah selesai
+		if err := app.Shutdown(); err != nil {
+			log.Println("Gagal menghentikan server:", err)
+		}
+	}()
+
 	// Menjalankan server
-	log.Fatal(app.Listen(":3000"))
+	if err := app.Listen(":3000"); err != nil {
+		log.Println("Server berhenti:", err)
+	}
+}
+
+// runMigrate menangani "migrate up" (default) dan "migrate status"
+func runMigrate(args []string) {
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	config.ConnectDB()
+	defer config.DisconnectDB()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	switch command {
+	case "up":
+		applied, err := migrations.Up(ctx, config.MongoDatabase)
+		for _, m := range applied {
+			fmt.Printf("applied %03d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("skema sudah terbaru")
+		}
+	case "status":
+		statuses, err := migrations.List(ctx, config.MongoDatabase)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%03d_%-30s %s\n", s.Version, s.Name, applied)
+		}
+	default:
+		log.Fatalf("perintah migrate tidak dikenal: %s (gunakan up atau status)", command)
+	}
+}
+
+// runAdmin menangani "admin grant <email>". Role admin tidak bisa dipilih saat register,
+// jadi hanya bisa diberikan oleh operator yang punya akses ke server.
+func runAdmin(args []string) {
+	if len(args) != 2 || args[0] != "grant" {
+		log.Fatal("penggunaan: admin grant <email>")
+	}
+
+	config.ConnectDB()
+	defer config.DisconnectDB()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	email := strings.ToLower(strings.TrimSpace(args[1]))
+	result, err := config.GetCollection("users").UpdateOne(ctx,
+		bson.M{"email": email},
+		bson.M{"$set": bson.M{"role": models.RoleAdmin, "updated_at": time.Now().Unix()}},
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if result.MatchedCount == 0 {
+		log.Fatalf("user dengan email %s tidak ditemukan", email)
+	}
+	fmt.Printf("%s sekarang admin, login ulang agar role baru berlaku\n", email)
 }
@@ -0,0 +1,78 @@
+package messaging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client dipakai provider HTTP; provider yang lambat dianggap gagal
+var Client = &http.Client{Timeout: 10 * time.Second}
+
+// WhatsAppProvider mengirim pesan teks lewat API bergaya WhatsApp Business Cloud API:
+// POST ke URL endpoint messages (misal https://graph.facebook.com/v19.0/<phone-number-id>/messages)
+// dengan token Bearer. Pesan teks bebas hanya diterima dalam 24 jam sejak pesan terakhir
+// dari user; di luar itu provider biasanya mewajibkan template yang sudah disetujui.
+type WhatsAppProvider struct {
+	URL   string
+	Token string
+}
+
+func (p *WhatsAppProvider) Send(ctx context.Context, msg Message) error {
+	return postJSON(ctx, p.URL, p.Token, map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"recipient_type":    "individual",
+		"to":                strings.TrimPrefix(msg.To, "+"), // API menerima nomor tanpa "+"
+		"type":              "text",
+		"text":              map[string]interface{}{"preview_url": false, "body": msg.Text},
+	})
+}
+
+// SMSGateway mengirim SMS lewat gateway HTTP generik yang menerima JSON
+// {"to", "message", "sender_id"} dengan token Bearer
+type SMSGateway struct {
+	URL      string
+	Token    string
+	SenderID string
+}
+
+func (g *SMSGateway) Send(ctx context.Context, msg Message) error {
+	return postJSON(ctx, g.URL, g.Token, map[string]interface{}{
+		"to":        msg.To,
+		"message":   msg.Text,
+		"sender_id": g.SenderID,
+	})
+}
+
+// postJSON mengirim body JSON dan menganggap hanya status 2xx sebagai berhasil
+func postJSON(ctx context.Context, url, token string, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("provider membalas %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
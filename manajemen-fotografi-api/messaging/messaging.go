@@ -0,0 +1,137 @@
+package messaging
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"manajemen-fotografi-api/logging"
+)
+
+// Message adalah satu pesan WhatsApp atau SMS. To selalu dalam format E.164, misal +6281234567890.
+type Message struct {
+	To   string
+	Text string
+}
+
+// Sender mengirim pesan lewat satu kanal (WhatsApp atau SMS)
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// WhatsAppFromEnv membuat sender WhatsApp dari WHATSAPP_API_URL dan WHATSAPP_TOKEN.
+// Tanpa URL pesan hanya dicatat ke log.
+func WhatsAppFromEnv() Sender {
+	url := os.Getenv("WHATSAPP_API_URL")
+	if url == "" {
+		return &LogSender{Channel: "whatsapp"}
+	}
+	return &WhatsAppProvider{URL: url, Token: os.Getenv("WHATSAPP_TOKEN")}
+}
+
+// SMSFromEnv membuat sender SMS dari SMS_API_URL, SMS_TOKEN dan SMS_SENDER_ID.
+// Tanpa URL pesan hanya dicatat ke log.
+func SMSFromEnv() Sender {
+	url := os.Getenv("SMS_API_URL")
+	if url == "" {
+		return &LogSender{Channel: "sms"}
+	}
+	return &SMSGateway{URL: url, Token: os.Getenv("SMS_TOKEN"), SenderID: os.Getenv("SMS_SENDER_ID")}
+}
+
+// LogSender hanya mencatat pesan ke log, dipakai di development
+type LogSender struct {
+	Channel string
+}
+
+func (s *LogSender) Send(ctx context.Context, msg Message) error {
+	logging.FromContext(ctx).InfoContext(ctx, "pesan (tidak dikirim)",
+		"channel", s.Channel,
+		"to", msg.To,
+		"text", msg.Text,
+	)
+	return nil
+}
+
+// Fake menyimpan pesan yang dikirim di memori, dipakai di test. Err (jika diisi)
+// dikembalikan oleh setiap Send tanpa menyimpan pesan.
+type Fake struct {
+	mu   sync.Mutex
+	sent []Message
+	Err  error
+}
+
+func (f *Fake) Send(ctx context.Context, msg Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return f.Err
+	}
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+// Sent mengembalikan salinan pesan yang sudah dikirim
+func (f *Fake) Sent() []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Message{}, f.sent...)
+}
+
+// throttled membatasi laju kirim sebuah sender dengan token bucket
+type throttled struct {
+	next     Sender
+	interval time.Duration
+
+	mu     sync.Mutex
+	tokens float64
+	burst  float64
+	last   time.Time
+}
+
+// Throttle membatasi sender maksimal perSecond pesan per detik (dengan burst sebanyak
+// perSecond, minimal 1) supaya tidak melewati batas provider. Send menunggu giliran atau
+// berhenti saat ctx dibatalkan. perSecond <= 0 berarti tanpa batas.
+func Throttle(next Sender, perSecond float64) Sender {
+	if perSecond <= 0 {
+		return next
+	}
+	return &throttled{
+		next:     next,
+		interval: time.Duration(float64(time.Second) / perSecond),
+		tokens:   max(1, perSecond),
+		burst:    max(1, perSecond),
+		last:     time.Now(),
+	}
+}
+
+func (t *throttled) Send(ctx context.Context, msg Message) error {
+	for {
+		wait := t.reserve(time.Now())
+		if wait == 0 {
+			return t.next.Send(ctx, msg)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve mengambil satu token, atau mengembalikan lama menunggu sampai token tersedia
+func (t *throttled) reserve(now time.Time) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.tokens = min(t.burst, t.tokens+float64(now.Sub(t.last))/float64(t.interval))
+	t.last = now
+	if t.tokens >= 1 {
+		t.tokens--
+		return 0
+	}
+	return time.Duration((1 - t.tokens) * float64(t.interval))
+}
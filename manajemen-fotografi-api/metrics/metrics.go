@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Metrik HTTP, dicatat oleh middleware untuk setiap route Fiber
+var (
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Jumlah request HTTP per route, method dan status.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latensi request HTTP per route, method dan status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+)
+
+// Metrik MongoDB, dicatat oleh decorator repository
+var (
+	mongoOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mongo_operation_duration_seconds",
+		Help:    "Durasi operasi MongoDB per collection dan operasi.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"collection", "operation", "outcome"})
+)
+
+// Metrik upload file
+var (
+	uploadSizeBytes = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "upload_size_bytes",
+		Help:    "Ukuran file yang diupload.",
+		Buckets: prometheus.ExponentialBuckets(16*1024, 4, 8), // 16KB s/d 256MB
+	}, []string{"kind"})
+
+	uploadDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "upload_duration_seconds",
+		Help:    "Durasi penyimpanan file upload.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"kind", "outcome"})
+)
+
+// Metrik bisnis
+var (
+	bookingsCreatedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "bookings_created_total",
+		Help: "Jumlah booking yang dibuat per status awal.",
+	}, []string{"status"})
+
+	paymentsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "payments_total",
+		Help: "Jumlah transaksi pembayaran per metode dan status.",
+	}, []string{"method", "status"})
+
+	paymentsAmountTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "payments_amount_total",
+		Help: "Total nominal transaksi pembayaran per metode.",
+	}, []string{"method"})
+
+	registrationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "user_registrations_total",
+		Help: "Jumlah registrasi user per role.",
+	}, []string{"role"})
+)
+
+// outcome mengubah error menjadi label "ok" atau "error"
+func outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+// ObserveHTTP mencatat satu request HTTP yang sudah selesai diproses
+func ObserveHTTP(method, route string, status int, elapsed time.Duration) {
+	code := strconv.Itoa(status)
+	httpRequestsTotal.WithLabelValues(method, route, code).Inc()
+	httpRequestDuration.WithLabelValues(method, route, code).Observe(elapsed.Seconds())
+}
+
+// ObserveMongo mencatat durasi satu operasi MongoDB
+func ObserveMongo(collection, operation string, elapsed time.Duration, err error) {
+	mongoOperationDuration.WithLabelValues(collection, operation, outcome(err)).Observe(elapsed.Seconds())
+}
+
+// ObserveUpload mencatat ukuran dan durasi penyimpanan file upload
+func ObserveUpload(kind string, size int64, elapsed time.Duration, err error) {
+	if err == nil {
+		uploadSizeBytes.WithLabelValues(kind).Observe(float64(size))
+	}
+	uploadDuration.WithLabelValues(kind, outcome(err)).Observe(elapsed.Seconds())
+}
+
+// BookingCreated menambah counter booking baru
+func BookingCreated(status string) {
+	bookingsCreatedTotal.WithLabelValues(status).Inc()
+}
+
+// PaymentRecorded menambah counter transaksi pembayaran
+func PaymentRecorded(method, status string, total float64) {
+	paymentsTotal.WithLabelValues(method, status).Inc()
+	paymentsAmountTotal.WithLabelValues(method).Add(total)
+}
+
+// UserRegistered menambah counter registrasi user
+func UserRegistered(role string) {
+	registrationsTotal.WithLabelValues(role).Inc()
+}
@@ -0,0 +1,20 @@
+package middlewares
+
+import (
+	"manajemen-fotografi-api/audit"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupAudit menyimpan IP dan request ID ke context, supaya setiap perubahan data yang
+// dicatat repository ke log audit membawa asal request. Dipasang setelah SetupLogger.
+func SetupAudit(app *fiber.App) {
+	app.Use(func(c *fiber.Ctx) error {
+		requestID, _ := c.Locals("requestid").(string)
+		c.SetUserContext(audit.WithSource(c.UserContext(), audit.Source{
+			IP:        c.IP(),
+			RequestID: requestID,
+		}))
+		return c.Next()
+	})
+}
@@ -0,0 +1,35 @@
+package middlewares
+
+import (
+	"manajemen-fotografi-api/auth"
+	"manajemen-fotografi-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireAuth hanya meneruskan request dari user yang sudah login
+func RequireAuth() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, ok := auth.FromContext(c.UserContext()); !ok {
+			return utils.Error(c, utils.ErrUnauthenticated)
+		}
+		return c.Next()
+	}
+}
+
+// RequireRole hanya meneruskan request dari user yang login dengan salah satu role.
+// Dipasang per group route setelah SetupSession.
+func RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, ok := auth.FromContext(c.UserContext())
+		if !ok {
+			return utils.Error(c, utils.ErrUnauthenticated)
+		}
+		for _, role := range roles {
+			if actor.Role == role {
+				return c.Next()
+			}
+		}
+		return utils.Error(c, utils.ErrForbidden)
+	}
+}
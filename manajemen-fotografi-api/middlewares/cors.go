@@ -8,7 +8,8 @@ import (
 func SetupCORS(app *fiber.App) {
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     "http://localhost:5173", // alamat React Vite.js
-		AllowHeaders:     "Origin, Content-Type, Accept",
-		AllowCredentials: true, // biar cookie/session bisa ikut
+		AllowHeaders:     "Origin, Content-Type, Accept, Accept-Language, If-Match",
+		ExposeHeaders:    "ETag, Link", // dibaca frontend untuk If-Match dan pagination
+		AllowCredentials: true,         // biar cookie/session bisa ikut
 	}))
 }
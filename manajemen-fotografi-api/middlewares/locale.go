@@ -0,0 +1,23 @@
+package middlewares
+
+import (
+	"manajemen-fotografi-api/i18n"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupLocale memilih bahasa response dari query ?lang= atau header Accept-Language,
+// lalu menyimpannya di context agar dipakai envelope error dan pesan sukses.
+// SetupSession mengganti bahasa dengan preferensi user yang login.
+func SetupLocale(app *fiber.App) {
+	app.Use(func(c *fiber.Ctx) error {
+		lang := i18n.Resolve(c.Query("lang"), c.Get(fiber.HeaderAcceptLanguage))
+		c.SetUserContext(i18n.WithLang(c.UserContext(), lang))
+
+		err := c.Next()
+
+		c.Set(fiber.HeaderContentLanguage, i18n.FromContext(c.UserContext()))
+		c.Vary(fiber.HeaderAcceptLanguage)
+		return err
+	})
+}
@@ -1,10 +1,67 @@
 package middlewares
 
 import (
+	"log/slog"
+	"time"
+
+	"manajemen-fotografi-api/logging"
+	"manajemen-fotografi-api/utils"
+
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// RequestIDHeader adalah header yang dipakai untuk korelasi request antar service
+const RequestIDHeader = fiber.HeaderXRequestID
+
+// SetupLogger memasang request ID dan access log JSON berbasis slog.
+// X-Request-ID dari client dipakai ulang, jika kosong dibuatkan yang baru.
 func SetupLogger(app *fiber.App) {
-	app.Use(logger.New())
-}
\ No newline at end of file
+	app.Use(requestid.New(requestid.Config{
+		Header: RequestIDHeader,
+	}))
+
+	app.Use(func(c *fiber.Ctx) error {
+		requestID, _ := c.Locals("requestid").(string)
+
+		// Logger per request dibawa lewat UserContext ke handler dan repository
+		logger := slog.Default().With(logging.RequestIDKey, requestID)
+		if spanCtx := trace.SpanContextFromContext(c.UserContext()); spanCtx.HasTraceID() {
+			logger = logger.With(logging.TraceIDKey, spanCtx.TraceID().String())
+		}
+		c.SetUserContext(logging.WithContext(c.UserContext(), logger))
+
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = utils.StatusOf(err)
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= fiber.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Method()),
+			slog.String("path", c.Path()),
+			slog.String("route", c.Route().Path),
+			slog.Int("status", status),
+			slog.Int64("latency_ms", time.Since(start).Milliseconds()),
+			slog.String("ip", c.IP()),
+			slog.Int("bytes", len(c.Response().Body())),
+		}
+		if err != nil {
+			attrs = append(attrs, slog.Any("error", err))
+		}
+		logger.LogAttrs(c.UserContext(), level, "request selesai", attrs...)
+
+		return err
+	})
+}
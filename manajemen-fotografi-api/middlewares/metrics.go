@@ -0,0 +1,34 @@
+package middlewares
+
+import (
+	"time"
+
+	"manajemen-fotografi-api/metrics"
+	"manajemen-fotografi-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupMetrics mencatat jumlah dan latensi request per route Fiber
+func SetupMetrics(app *fiber.App) {
+	app.Use(func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		// Error dari handler belum diubah menjadi status response oleh error handler
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = utils.StatusOf(err)
+		}
+
+		// Pakai pola route (misal /api/bookings/:id) agar label tidak meledak per ID.
+		// Request yang tidak cocok dengan route manapun masih menunjuk ke middleware ini.
+		route := c.Route().Path
+		if status == fiber.StatusNotFound && route == "/" {
+			route = "unmatched"
+		}
+
+		metrics.ObserveHTTP(c.Method(), route, status, time.Since(start))
+		return err
+	})
+}
@@ -0,0 +1,24 @@
+package middlewares
+
+import (
+	"runtime/debug"
+
+	"manajemen-fotografi-api/logging"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+)
+
+// SetupRecover mengubah panic di handler menjadi error, sehingga response tetap memakai
+// envelope standar dari utils.ErrorHandler. Dipasang setelah SetupLogger supaya stack
+// trace tercatat bersama request ID.
+func SetupRecover(app *fiber.App) {
+	app.Use(recover.New(recover.Config{
+		EnableStackTrace: true,
+		StackTraceHandler: func(c *fiber.Ctx, e interface{}) {
+			ctx := c.UserContext()
+			logging.FromContext(ctx).ErrorContext(ctx, "panic di handler",
+				"panic", e, "stack", string(debug.Stack()))
+		},
+	}))
+}
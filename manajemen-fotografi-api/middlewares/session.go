@@ -0,0 +1,89 @@
+package middlewares
+
+import (
+	"errors"
+	"time"
+
+	"manajemen-fotografi-api/auth"
+	"manajemen-fotografi-api/i18n"
+	"manajemen-fotografi-api/logging"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/session"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// sessionCookie adalah nama cookie yang menyimpan ID session
+const sessionCookie = "session_id"
+
+// sessionStoreKey adalah kunci c.Locals untuk store session, dipakai Session
+const sessionStoreKey = "session_store"
+
+// SetupSession memasang session cookie yang disimpan di MongoDB. Session tersedia lewat
+// Session(c), dan user yang login disimpan ke context lewat auth.WithActor beserta
+// preferensi bahasanya. Dipasang setelah SetupLocale.
+// Request tanpa cookie session (anonim, /metrics, health check) tidak membaca MongoDB.
+func SetupSession(app *fiber.App) {
+	store := session.New(session.Config{
+		Storage:        repositories.NewSessionStorage(),
+		Expiration:     24 * time.Hour,
+		KeyLookup:      "cookie:" + sessionCookie,
+		CookieHTTPOnly: true,
+		CookieSameSite: fiber.CookieSameSiteLaxMode,
+	})
+
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(sessionStoreKey, store)
+		if c.Cookies(sessionCookie) == "" {
+			return c.Next()
+		}
+
+		sess, err := Session(c)
+		if err != nil {
+			return utils.Error(c, utils.ErrInternal.Wrap(err))
+		}
+
+		if actor, ok := actorFromSession(sess); ok {
+			ctx := auth.WithActor(c.UserContext(), actor)
+			logger := logging.FromContext(ctx).With("user_id", actor.UserID.Hex())
+			c.SetUserContext(logging.WithContext(ctx, logger))
+		}
+
+		// Preferensi bahasa user mengalahkan Accept-Language, tapi ?lang= tetap didahulukan
+		if pref, _ := sess.Get(auth.SessionLanguage).(string); pref != "" && c.Query("lang") == "" {
+			lang := i18n.Resolve(pref, c.Get(fiber.HeaderAcceptLanguage))
+			c.SetUserContext(i18n.WithLang(c.UserContext(), lang))
+		}
+		return c.Next()
+	})
+}
+
+// Session mengambil session request. Untuk request tanpa cookie, session baru dibuat
+// saat pertama kali dibutuhkan, misalnya ketika login.
+func Session(c *fiber.Ctx) (*session.Session, error) {
+	if sess, ok := c.Locals("session").(*session.Session); ok {
+		return sess, nil
+	}
+	store, ok := c.Locals(sessionStoreKey).(*session.Store)
+	if !ok {
+		return nil, errors.New("middleware session belum dipasang")
+	}
+	sess, err := store.Get(c)
+	if err != nil {
+		return nil, err
+	}
+	c.Locals("session", sess)
+	return sess, nil
+}
+
+func actorFromSession(sess *session.Session) (auth.Actor, bool) {
+	hex, _ := sess.Get(auth.SessionUserID).(string)
+	userID, err := primitive.ObjectIDFromHex(hex)
+	if err != nil {
+		return auth.Actor{}, false
+	}
+	role, _ := sess.Get(auth.SessionRole).(string)
+	return auth.Actor{UserID: userID, Role: role}, true
+}
@@ -0,0 +1,245 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"manajemen-fotografi-api/logging"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection adalah nama collection pencatat migrasi yang sudah dijalankan
+const Collection = "schema_migrations"
+
+// lockID adalah dokumen di schema_migrations yang dipakai sebagai lock,
+// supaya dua instance yang boot bersamaan tidak menjalankan migrasi yang sama
+const lockID = "lock"
+
+// lockTTL adalah batas umur lock; lock lebih tua dianggap milik proses yang mati
+const lockTTL = 10 * time.Minute
+
+// Migration adalah satu langkah perubahan skema. Version harus unik dan naik terus,
+// Up harus aman dijalankan ulang jika proses berhenti di tengah jalan.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context, db *mongo.Database) error
+}
+
+// Record adalah dokumen di schema_migrations
+type Record struct {
+	Version   int       `bson:"_id" json:"version"`
+	Name      string    `bson:"name" json:"name"`
+	AppliedAt time.Time `bson:"applied_at" json:"applied_at"`
+}
+
+// Status adalah keadaan satu migrasi untuk subcommand "migrate status"
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time // nil jika belum dijalankan
+}
+
+// ErrLocked dikembalikan jika proses lain sedang menjalankan migrasi
+var ErrLocked = errors.New("migrasi sedang dijalankan proses lain")
+
+// ErrDuplicates dikembalikan jika data lama berisi nilai ganda sehingga index unik tidak
+// bisa dipasang. Data tidak dihapus otomatis; rapikan dulu lalu jalankan migrasi lagi.
+var ErrDuplicates = errors.New("data ganda menghalangi index unik")
+
+// duplicateSamples membatasi jumlah contoh nilai ganda yang ditulis di pesan error
+const duplicateSamples = 5
+
+// All mengembalikan semua migrasi terdaftar, urut berdasarkan versi
+func All() []Migration {
+	all := append([]Migration{}, registry...)
+	sort.Slice(all, func(i, j int) bool { return all[i].Version < all[j].Version })
+	return all
+}
+
+// Up menjalankan semua migrasi yang belum tercatat, berurutan.
+// Setiap migrasi yang selesai langsung dicatat, jadi kegagalan di tengah bisa dilanjutkan.
+func Up(ctx context.Context, db *mongo.Database) ([]Migration, error) {
+	coll := db.Collection(Collection)
+	if err := acquireLock(ctx, coll); err != nil {
+		return nil, err
+	}
+	defer releaseLock(coll)
+
+	applied, err := appliedVersions(ctx, coll)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := logging.FromContext(ctx)
+	var done []Migration
+	for _, m := range All() {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+
+		start := time.Now()
+		if err := m.Up(ctx, db); err != nil {
+			return done, fmt.Errorf("migrasi %d_%s gagal: %w", m.Version, m.Name, err)
+		}
+		record := Record{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}
+		if _, err := coll.InsertOne(ctx, record); err != nil {
+			return done, fmt.Errorf("gagal mencatat migrasi %d_%s: %w", m.Version, m.Name, err)
+		}
+
+		logger.InfoContext(ctx, "migrasi dijalankan",
+			"version", m.Version,
+			"name", m.Name,
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// List mengembalikan status semua migrasi terdaftar
+func List(ctx context.Context, db *mongo.Database) ([]Status, error) {
+	applied, err := appliedVersions(ctx, db.Collection(Collection))
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []Status
+	for _, m := range All() {
+		s := Status{Version: m.Version, Name: m.Name}
+		if r, ok := applied[m.Version]; ok {
+			s.AppliedAt = &r.AppliedAt
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+// RunOnBoot menjalankan migrasi saat startup jika MIGRATE_ON_BOOT=true
+func RunOnBoot(ctx context.Context, db *mongo.Database) error {
+	if os.Getenv("MIGRATE_ON_BOOT") != "true" {
+		return nil
+	}
+	_, err := Up(ctx, db)
+	return err
+}
+
+func appliedVersions(ctx context.Context, coll *mongo.Collection) (map[int]Record, error) {
+	// Dokumen lock memakai _id string sehingga tidak ikut terbaca
+	cursor, err := coll.Find(ctx, bson.M{"_id": bson.M{"$type": "number"}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var records []Record
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]Record, len(records))
+	for _, r := range records {
+		applied[r.Version] = r
+	}
+	return applied, nil
+}
+
+func acquireLock(ctx context.Context, coll *mongo.Collection) error {
+	host, _ := os.Hostname()
+	now := time.Now()
+
+	_, err := coll.InsertOne(ctx, bson.M{"_id": lockID, "locked_at": now, "holder": host})
+	if !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+
+	// Ambil alih lock yang sudah kedaluwarsa
+	res, err := coll.UpdateOne(ctx,
+		bson.M{"_id": lockID, "locked_at": bson.M{"$lt": now.Add(-lockTTL)}},
+		bson.M{"$set": bson.M{"locked_at": now, "holder": host}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.ModifiedCount == 0 {
+		return ErrLocked
+	}
+	return nil
+}
+
+func releaseLock(coll *mongo.Collection) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	coll.DeleteOne(ctx, bson.M{"_id": lockID})
+}
+
+// createIndexes membuat index; index yang sudah ada dengan spesifikasi sama diabaikan Mongo
+func createIndexes(ctx context.Context, db *mongo.Database, collection string, models ...mongo.IndexModel) error {
+	_, err := db.Collection(collection).Indexes().CreateMany(ctx, models)
+	return err
+}
+
+// checkDuplicates mencari dokumen yang lolos match dengan nilai key (ekspresi aggregate)
+// yang sama, dan melaporkannya sebagai ErrDuplicates sebelum index unik dipasang
+func checkDuplicates(ctx context.Context, db *mongo.Database, collection, field string, match bson.M, key interface{}) error {
+	cursor, err := db.Collection(collection).Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{"_id": key, "count": bson.M{"$sum": 1}}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+	})
+	if err != nil {
+		return err
+	}
+	var groups []struct {
+		Value interface{} `bson:"_id"`
+		Count int         `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil || len(groups) == 0 {
+		return err
+	}
+
+	samples := make([]string, 0, duplicateSamples)
+	for _, g := range groups[:min(len(groups), duplicateSamples)] {
+		samples = append(samples, fmt.Sprintf("%v (%d dokumen)", g.Value, g.Count))
+	}
+	return fmt.Errorf("%w: %d nilai %s.%s dipakai lebih dari satu dokumen, misal %s",
+		ErrDuplicates, len(groups), collection, field, strings.Join(samples, ", "))
+}
+
+// dropIndex menghapus index berdasarkan nama; index yang tidak ada diabaikan
+func dropIndex(ctx context.Context, db *mongo.Database, collection, name string) error {
+	_, err := db.Collection(collection).Indexes().DropOne(ctx, name)
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && (cmdErr.Name == "IndexNotFound" || cmdErr.Name == "NamespaceNotFound") {
+		return nil
+	}
+	return err
+}
+
+// setValidator memasang JSON schema validator, membuat collection jika belum ada
+func setValidator(ctx context.Context, db *mongo.Database, collection string, schema bson.M) error {
+	err := db.RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: collection},
+		{Key: "validator", Value: bson.M{"$jsonSchema": schema}},
+		{Key: "validationLevel", Value: "moderate"},
+		{Key: "validationAction", Value: "error"},
+	}).Err()
+
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Name == "NamespaceNotFound" {
+		return db.CreateCollection(ctx, collection, options.CreateCollection().
+			SetValidator(bson.M{"$jsonSchema": schema}).
+			SetValidationLevel("moderate").
+			SetValidationAction("error"))
+	}
+	return err
+}
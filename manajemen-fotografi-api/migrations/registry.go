@@ -0,0 +1,586 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"manajemen-fotografi-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// registry berisi semua migrasi. Migrasi yang sudah dirilis tidak boleh diubah,
+// perubahan skema berikutnya ditambahkan sebagai versi baru.
+var registry = []Migration{
+	{Version: 1, Name: "users_unique_email", Up: usersUniqueEmail},
+	{Version: 2, Name: "profiles_unique_user_id", Up: profilesUniqueUserID},
+	{Version: 3, Name: "query_indexes", Up: queryIndexes},
+	{Version: 4, Name: "backfill_booking_status", Up: backfillBookingStatus},
+	{Version: 5, Name: "json_schema_validators", Up: jsonSchemaValidators},
+	{Version: 6, Name: "soft_delete_and_sessions", Up: softDeleteAndSessions},
+	{Version: 7, Name: "backfill_document_version", Up: backfillDocumentVersion},
+	{Version: 8, Name: "audit_logs_and_admin_role", Up: auditLogsAndAdminRole},
+	{Version: 9, Name: "events_replay_indexes", Up: eventsReplayIndexes},
+	{Version: 10, Name: "webhook_indexes", Up: webhookIndexes},
+	{Version: 11, Name: "booking_cancelled_status", Up: bookingCancelledStatus},
+	{Version: 12, Name: "notification_outbox_indexes", Up: notificationOutboxIndexes},
+	{Version: 13, Name: "normalize_phone_e164", Up: normalizePhoneE164},
+	{Version: 14, Name: "jobs_and_booking_no_show", Up: jobsAndBookingNoShow},
+	{Version: 15, Name: "busy_times_indexes", Up: busyTimesIndexes},
+	{Version: 16, Name: "booking_messages", Up: bookingMessages},
+	{Version: 17, Name: "analytics_indexes", Up: analyticsIndexes},
+	{Version: 18, Name: "earnings_ledger", Up: earningsLedger},
+	{Version: 19, Name: "coupons", Up: couponsAndDiscounts},
+	{Version: 20, Name: "waitlist", Up: waitlistIndexes},
+	{Version: 21, Name: "shortlists", Up: shortlistIndexes},
+	{Version: 22, Name: "transactions_method_configurable", Up: transactionMethodSchema},
+}
+
+// usersUniqueEmail menyeragamkan email menjadi huruf kecil lalu memasang index unik,
+// sehingga pengecekan email ganda tidak lagi bergantung pada CountDocuments.
+// Email yang baru sama setelah diseragamkan dilaporkan dulu, sebelum data diubah.
+func usersUniqueEmail(ctx context.Context, db *mongo.Database) error {
+	email := bson.M{"$toLower": bson.M{"$trim": bson.M{"input": "$email"}}}
+	if err := checkDuplicates(ctx, db, "users", "email", bson.M{"email": bson.M{"$type": "string"}}, email); err != nil {
+		return err
+	}
+
+	_, err := db.Collection("users").UpdateMany(ctx,
+		bson.M{"email": bson.M{"$type": "string"}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"email": email}}}},
+	)
+	if err != nil {
+		return err
+	}
+
+	return createIndexes(ctx, db, "users", mongo.IndexModel{
+		Keys:    bson.D{{Key: "email", Value: 1}},
+		Options: options.Index().SetName("email_unique").SetUnique(true),
+	})
+}
+
+// profilesUniqueUserID memastikan satu user hanya punya satu profil client dan satu profil fotografer
+func profilesUniqueUserID(ctx context.Context, db *mongo.Database) error {
+	for _, collection := range []string{"clients", "photographers"} {
+		if err := checkDuplicates(ctx, db, collection, "user_id", bson.M{}, "$user_id"); err != nil {
+			return err
+		}
+		err := createIndexes(ctx, db, collection, mongo.IndexModel{
+			Keys:    bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetName("user_id_unique").SetUnique(true),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryIndexes mendukung filter dan sort default endpoint list
+func queryIndexes(ctx context.Context, db *mongo.Database) error {
+	if err := createIndexes(ctx, db, "bookings",
+		mongo.IndexModel{Keys: bson.D{{Key: "photographer_id", Value: 1}, {Key: "date", Value: 1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "client_id", Value: 1}, {Key: "date", Value: -1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "status", Value: 1}, {Key: "date", Value: 1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "date", Value: 1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+	); err != nil {
+		return err
+	}
+	if err := createIndexes(ctx, db, "galleries",
+		mongo.IndexModel{Keys: bson.D{{Key: "photographer_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+	); err != nil {
+		return err
+	}
+	return createIndexes(ctx, db, "transactions",
+		mongo.IndexModel{Keys: bson.D{{Key: "booking_id", Value: 1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+	)
+}
+
+// backfillBookingStatus mengisi status booking lama yang kosong dengan pending,
+// supaya lolos validator status pada migrasi berikutnya
+func backfillBookingStatus(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("bookings").UpdateMany(ctx,
+		bson.M{"$or": bson.A{bson.M{"status": bson.M{"$exists": false}}, bson.M{"status": ""}}},
+		bson.M{"$set": bson.M{"status": "pending"}},
+	)
+	return err
+}
+
+// jsonSchemaValidators menolak dokumen dengan field wajib yang hilang atau bertipe salah
+// di level database, sebagai lapisan kedua setelah validasi handler
+func jsonSchemaValidators(ctx context.Context, db *mongo.Database) error {
+	schemas := map[string]bson.M{
+		"users": {
+			"bsonType": "object",
+			"required": bson.A{"name", "email", "password", "role"},
+			"properties": bson.M{
+				"email": bson.M{"bsonType": "string"},
+				"role":  bson.M{"enum": bson.A{"client", "photographer"}},
+			},
+		},
+		"clients": {
+			"bsonType": "object",
+			"required": bson.A{"user_id", "name", "phone"},
+			"properties": bson.M{
+				"user_id": bson.M{"bsonType": "objectId"},
+			},
+		},
+		"photographers": {
+			"bsonType": "object",
+			"required": bson.A{"user_id", "phone"},
+			"properties": bson.M{
+				"user_id": bson.M{"bsonType": "objectId"},
+			},
+		},
+		"bookings": {
+			"bsonType": "object",
+			"required": bson.A{"client_id", "photographer_id", "date", "status"},
+			"properties": bson.M{
+				"client_id":       bson.M{"bsonType": "objectId"},
+				"photographer_id": bson.M{"bsonType": "objectId"},
+				"date":            bson.M{"bsonType": "date"},
+				"status":          bson.M{"enum": bson.A{"pending", "confirmed", "done"}},
+			},
+		},
+		"transactions": {
+			"bsonType": "object",
+			"required": bson.A{"booking_id", "method", "total"},
+			"properties": bson.M{
+				"booking_id": bson.M{"bsonType": "objectId"},
+				"method":     bson.M{"enum": bson.A{"transfer", "ewallet"}},
+				"total":      bson.M{"bsonType": bson.A{"double", "int", "long", "decimal"}, "exclusiveMinimum": true, "minimum": 0},
+			},
+		},
+	}
+
+	for _, collection := range []string{"users", "clients", "photographers", "bookings", "transactions"} {
+		if err := setValidator(ctx, db, collection, schemas[collection]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// softDeleteAndSessions menyiapkan trash: deleted_at diisi null pada dokumen lama,
+// index unik user_id hanya berlaku untuk profil aktif, dan session login kedaluwarsa otomatis
+func softDeleteAndSessions(ctx context.Context, db *mongo.Database) error {
+	for _, collection := range []string{"bookings", "galleries", "clients", "photographers", "transactions"} {
+		_, err := db.Collection(collection).UpdateMany(ctx,
+			bson.M{"deleted_at": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"deleted_at": nil, "deleted_by": nil}},
+		)
+		if err != nil {
+			return err
+		}
+		if err := createIndexes(ctx, db, collection, mongo.IndexModel{
+			Keys:    bson.D{{Key: "deleted_at", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Profil yang ada di trash tidak boleh menghalangi user membuat profil baru
+	for _, collection := range []string{"clients", "photographers"} {
+		if err := dropIndex(ctx, db, collection, "user_id_unique"); err != nil {
+			return err
+		}
+		if err := createIndexes(ctx, db, collection, mongo.IndexModel{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetName("user_id_unique_active").SetUnique(true).
+				SetPartialFilterExpression(bson.M{"deleted_at": bson.M{"$type": "null"}}),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return createIndexes(ctx, db, "sessions", mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+}
+
+// backfillDocumentVersion memberi version 1 pada dokumen lama supaya ETag dan If-Match
+// berlaku untuk semua dokumen, bukan hanya yang dibuat setelah versioning ada
+func backfillDocumentVersion(ctx context.Context, db *mongo.Database) error {
+	for _, collection := range []string{"bookings", "galleries", "clients", "photographers", "transactions"} {
+		_, err := db.Collection(collection).UpdateMany(ctx,
+			bson.M{"version": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"version": 1}},
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// auditLogsAndAdminRole memasang index untuk query log audit (per entitas, per user dan
+// rentang waktu) dan mengizinkan role admin pada validator users
+func auditLogsAndAdminRole(ctx context.Context, db *mongo.Database) error {
+	if err := createIndexes(ctx, db, "audit_logs",
+		mongo.IndexModel{Keys: bson.D{{Key: "entity", Value: 1}, {Key: "entity_id", Value: 1}, {Key: "at", Value: -1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "actor_id", Value: 1}, {Key: "at", Value: -1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "at", Value: -1}}},
+	); err != nil {
+		return err
+	}
+
+	return setValidator(ctx, db, "users", bson.M{
+		"bsonType": "object",
+		"required": bson.A{"name", "email", "password", "role"},
+		"properties": bson.M{
+			"email": bson.M{"bsonType": "string"},
+			"role":  bson.M{"enum": bson.A{"client", "photographer", "admin"}},
+		},
+	})
+}
+
+// eventsReplayIndexes mempercepat replay Last-Event-ID per user dan menghapus event
+// setelah 7 hari; client yang terputus lebih lama memuat ulang data lewat API biasa
+func eventsReplayIndexes(ctx context.Context, db *mongo.Database) error {
+	return createIndexes(ctx, db, "events",
+		mongo.IndexModel{Keys: bson.D{{Key: "recipients", Value: 1}, {Key: "_id", Value: 1}}},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32((7 * 24 * time.Hour).Seconds())),
+		},
+	)
+}
+
+// webhookIndexes mencegah delivery ganda untuk event yang sama (beberapa instance menerima
+// event yang sama), mempercepat pengambilan delivery jatuh tempo dan pencarian webhook per event
+func webhookIndexes(ctx context.Context, db *mongo.Database) error {
+	if err := createIndexes(ctx, db, "webhooks",
+		mongo.IndexModel{Keys: bson.D{{Key: "events", Value: 1}, {Key: "active", Value: 1}}},
+	); err != nil {
+		return err
+	}
+
+	return createIndexes(ctx, db, "webhook_deliveries",
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "webhook_id", Value: 1}, {Key: "event_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		mongo.IndexModel{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "webhook_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	)
+}
+
+// bookingCancelledStatus mengizinkan status cancelled pada validator bookings
+func bookingCancelledStatus(ctx context.Context, db *mongo.Database) error {
+	return setValidator(ctx, db, "bookings", bson.M{
+		"bsonType": "object",
+		"required": bson.A{"client_id", "photographer_id", "date", "status"},
+		"properties": bson.M{
+			"client_id":       bson.M{"bsonType": "objectId"},
+			"photographer_id": bson.M{"bsonType": "objectId"},
+			"date":            bson.M{"bsonType": "date"},
+			"status":          bson.M{"enum": bson.A{"pending", "confirmed", "done", "cancelled"}},
+		},
+	})
+}
+
+// notificationOutboxIndexes mencegah pesan ganda untuk event yang sama, mempercepat
+// pengambilan pesan jatuh tempo dan menghapus pesan terkirim setelah 30 hari
+func notificationOutboxIndexes(ctx context.Context, db *mongo.Database) error {
+	return createIndexes(ctx, db, "notification_outbox",
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "dedupe_key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		mongo.IndexModel{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		mongo.IndexModel{
+			Keys: bson.D{{Key: "sent_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32((30 * 24 * time.Hour).Seconds())).
+				SetPartialFilterExpression(bson.M{"status": "sent"}),
+		},
+	)
+}
+
+// normalizePhoneE164 mengubah nomor telepon profil lama ke format E.164 (+62...) seperti
+// yang disimpan handler sekarang, dan memasang index untuk batas pesan WhatsApp/SMS per user.
+// Nomor yang tidak valid dibiarkan apa adanya.
+func normalizePhoneE164(ctx context.Context, db *mongo.Database) error {
+	for _, collection := range []string{"clients", "photographers"} {
+		coll := db.Collection(collection)
+		cursor, err := coll.Find(ctx,
+			bson.M{"phone": bson.M{"$not": bson.M{"$regex": `^\+62`}}},
+			options.Find().SetProjection(bson.M{"phone": 1}),
+		)
+		if err != nil {
+			return err
+		}
+		var docs []struct {
+			ID    primitive.ObjectID `bson:"_id"`
+			Phone string             `bson:"phone"`
+		}
+		if err := cursor.All(ctx, &docs); err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			if !utils.IsValidPhone(doc.Phone) {
+				continue
+			}
+			_, err := coll.UpdateOne(ctx, bson.M{"_id": doc.ID},
+				bson.M{"$set": bson.M{"phone": utils.NormalizePhone(doc.Phone)}})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return createIndexes(ctx, db, "notification_outbox", mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "channel", Value: 1}, {Key: "sent_at", Value: -1}},
+	})
+}
+
+// jobsAndBookingNoShow memasang index scheduler (key unik, job jatuh tempo, job selesai
+// dihapus setelah 30 hari), index untuk sweep booking dan mengizinkan status no_show
+func jobsAndBookingNoShow(ctx context.Context, db *mongo.Database) error {
+	if err := createIndexes(ctx, db, "jobs",
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		mongo.IndexModel{Keys: bson.D{{Key: "status", Value: 1}, {Key: "run_at", Value: 1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "name", Value: 1}, {Key: "status", Value: 1}}},
+		mongo.IndexModel{
+			Keys: bson.D{{Key: "finished_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32((30 * 24 * time.Hour).Seconds())).
+				SetPartialFilterExpression(bson.M{"status": "done"}),
+		},
+	); err != nil {
+		return err
+	}
+
+	// Index (status, date) untuk pengingat dan penutupan sudah ada sejak query_indexes
+	if err := createIndexes(ctx, db, "bookings",
+		mongo.IndexModel{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+	); err != nil {
+		return err
+	}
+
+	return setValidator(ctx, db, "bookings", bson.M{
+		"bsonType": "object",
+		"required": bson.A{"client_id", "photographer_id", "date", "status"},
+		"properties": bson.M{
+			"client_id":       bson.M{"bsonType": "objectId"},
+			"photographer_id": bson.M{"bsonType": "objectId"},
+			"date":            bson.M{"bsonType": "date"},
+			"status":          bson.M{"enum": bson.A{"pending", "confirmed", "done", "cancelled", "no_show"}},
+		},
+	})
+}
+
+// busyTimesIndexes memasang index jadwal sibuk untuk pengecekan booking (fotografer dan
+// rentang waktu) serta penggantian hasil impor per sumber
+func busyTimesIndexes(ctx context.Context, db *mongo.Database) error {
+	return createIndexes(ctx, db, "busy_times",
+		mongo.IndexModel{Keys: bson.D{{Key: "photographer_id", Value: 1}, {Key: "start", Value: 1}, {Key: "end", Value: 1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "photographer_id", Value: 1}, {Key: "source", Value: 1}, {Key: "imported_at", Value: 1}}},
+	)
+}
+
+// bookingMessages memasang index thread pesan booking lalu menyalin catatan booking lama
+// sebagai pesan pertama. Index unik from_note membuat salinan tidak dobel jika diulang.
+func bookingMessages(ctx context.Context, db *mongo.Database) error {
+	if err := createIndexes(ctx, db, "messages",
+		mongo.IndexModel{Keys: bson.D{{Key: "booking_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "booking_id", Value: 1}, {Key: "sender_id", Value: 1}, {Key: "read_by.user_id", Value: 1}}},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "booking_id", Value: 1}},
+			Options: options.Index().SetName("booking_note_unique").SetUnique(true).SetPartialFilterExpression(bson.M{"from_note": true}),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "deleted_at", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+	); err != nil {
+		return err
+	}
+
+	cursor, err := db.Collection("bookings").Find(ctx, bson.M{"note": bson.M{"$nin": bson.A{nil, ""}}})
+	if err != nil {
+		return err
+	}
+	var bookings []struct {
+		ID        primitive.ObjectID `bson:"_id"`
+		ClientID  primitive.ObjectID `bson:"client_id"`
+		Note      string             `bson:"note"`
+		CreatedAt time.Time          `bson:"created_at"`
+		DeletedAt interface{}        `bson:"deleted_at"`
+		DeletedBy interface{}        `bson:"deleted_by"`
+	}
+	if err := cursor.All(ctx, &bookings); err != nil {
+		return err
+	}
+	for _, booking := range bookings {
+		var client struct {
+			UserID primitive.ObjectID `bson:"user_id"`
+		}
+		err := db.Collection("clients").FindOne(ctx, bson.M{"_id": booking.ClientID}).Decode(&client)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return err
+		}
+		// Pesan dari booking yang ada di trash ikut berada di trash
+		_, err = db.Collection("messages").InsertOne(ctx, bson.M{
+			"booking_id":  booking.ID,
+			"sender_id":   client.UserID,
+			"sender_role": "client",
+			"text":        booking.Note,
+			"from_note":   true,
+			"read_by":     bson.A{},
+			"created_at":  booking.CreatedAt,
+			"deleted_at":  booking.DeletedAt,
+			"deleted_by":  booking.DeletedBy,
+		})
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// analyticsIndexes memasang index untuk laporan analitik: transaksi lunas per rentang waktu
+// dan booking per fotografer atau client berdasarkan waktu dibuat
+func analyticsIndexes(ctx context.Context, db *mongo.Database) error {
+	if err := createIndexes(ctx, db, "transactions",
+		mongo.IndexModel{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+	); err != nil {
+		return err
+	}
+	return createIndexes(ctx, db, "bookings",
+		mongo.IndexModel{Keys: bson.D{{Key: "photographer_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "client_id", Value: 1}, {Key: "created_at", Value: 1}}},
+	)
+}
+
+// earningsLedger memasang index buku besar: key unik supaya jurnal pembayaran tidak dobel,
+// mutasi per fotografer, dan jurnal yang belum masuk batch payout. Key tarif komisi juga unik.
+func earningsLedger(ctx context.Context, db *mongo.Database) error {
+	if err := createIndexes(ctx, db, "ledger_journals",
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "key", Value: 1}},
+			Options: options.Index().SetName("key_unique").SetUnique(true),
+		},
+		mongo.IndexModel{Keys: bson.D{{Key: "photographer_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "payout_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "transaction_id", Value: 1}}},
+	); err != nil {
+		return err
+	}
+	if err := createIndexes(ctx, db, "payout_batches",
+		mongo.IndexModel{Keys: bson.D{{Key: "created_at", Value: -1}}},
+	); err != nil {
+		return err
+	}
+	return createIndexes(ctx, db, "commission_rates", mongo.IndexModel{
+		Keys:    bson.D{{Key: "key", Value: 1}},
+		Options: options.Index().SetName("key_unique").SetUnique(true),
+	})
+}
+
+// couponsAndDiscounts memasang index kupon (kode unik) dan riwayat pemakaiannya, lalu
+// melonggarkan validator transactions: total boleh 0 jika kupon menanggung seluruh harga
+func couponsAndDiscounts(ctx context.Context, db *mongo.Database) error {
+	if err := createIndexes(ctx, db, "coupons",
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "code", Value: 1}},
+			Options: options.Index().SetName("code_unique").SetUnique(true),
+		},
+		mongo.IndexModel{Keys: bson.D{{Key: "created_at", Value: -1}}},
+	); err != nil {
+		return err
+	}
+	if err := createIndexes(ctx, db, "coupon_redemptions",
+		mongo.IndexModel{Keys: bson.D{{Key: "coupon_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "client_id", Value: 1}}},
+	); err != nil {
+		return err
+	}
+
+	amount := bson.M{"bsonType": bson.A{"double", "int", "long", "decimal"}, "minimum": 0}
+	return setValidator(ctx, db, "transactions", bson.M{
+		"bsonType": "object",
+		"required": bson.A{"booking_id", "method", "total"},
+		"properties": bson.M{
+			"booking_id": bson.M{"bsonType": "objectId"},
+			"method":     bson.M{"enum": bson.A{"transfer", "ewallet"}},
+			"total":      amount,
+			"subtotal":   amount,
+			"discount":   amount,
+		},
+	})
+}
+
+// waitlistIndexes memasang index antrean: satu antrean aktif per client per tanggal,
+// satu tawaran berjalan per tanggal fotografer, serta urutan antrean dan tawaran kedaluwarsa
+func waitlistIndexes(ctx context.Context, db *mongo.Database) error {
+	return createIndexes(ctx, db, "waitlist",
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "photographer_id", Value: 1}, {Key: "date", Value: 1}, {Key: "client_id", Value: 1}},
+			Options: options.Index().SetName("active_client_unique").SetUnique(true).SetPartialFilterExpression(bson.M{"active": true}),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "photographer_id", Value: 1}, {Key: "date", Value: 1}},
+			Options: options.Index().SetName("open_offer_unique").SetUnique(true).SetPartialFilterExpression(bson.M{"offer_open": true}),
+		},
+		mongo.IndexModel{Keys: bson.D{{Key: "photographer_id", Value: 1}, {Key: "date", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "client_id", Value: 1}, {Key: "date", Value: 1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "offer_expires_at", Value: 1}}, Options: options.Index().SetPartialFilterExpression(bson.M{"offer_open": true})},
+	)
+}
+
+// shortlistIndexes mendukung daftar shortlist client, lookup link berbagi dan hitung ulang
+// favorit per fotografer. favorite_count fotografer lama diisi 0 supaya urutan dan cursor
+// pencarian tidak bertemu field yang hilang.
+func shortlistIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("photographers").UpdateMany(ctx,
+		bson.M{"favorite_count": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"favorite_count": 0}},
+	)
+	if err != nil {
+		return err
+	}
+	if err := createIndexes(ctx, db, "photographers",
+		mongo.IndexModel{Keys: bson.D{{Key: "favorite_count", Value: -1}, {Key: "_id", Value: -1}}},
+	); err != nil {
+		return err
+	}
+
+	return createIndexes(ctx, db, "shortlists",
+		mongo.IndexModel{Keys: bson.D{{Key: "client_id", Value: 1}, {Key: "updated_at", Value: -1}}},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "share_token_hash", Value: 1}},
+			Options: options.Index().SetName("share_token_unique").SetUnique(true).SetSparse(true),
+		},
+		mongo.IndexModel{Keys: bson.D{{Key: "items.photographer_id", Value: 1}, {Key: "client_id", Value: 1}}},
+	)
+}
+
+// transactionMethodSchema melepas enum method dari validator transactions. Daftar metode
+// pembayaran diatur lewat PAYMENT_METHODS dan dicek aplikasi, jadi database cukup
+// memastikan method berupa string.
+func transactionMethodSchema(ctx context.Context, db *mongo.Database) error {
+	amount := bson.M{"bsonType": bson.A{"double", "int", "long", "decimal"}, "minimum": 0}
+	return setValidator(ctx, db, "transactions", bson.M{
+		"bsonType": "object",
+		"required": bson.A{"booking_id", "method", "total"},
+		"properties": bson.M{
+			"booking_id": bson.M{"bsonType": "objectId"},
+			"method":     bson.M{"bsonType": "string", "minLength": 1},
+			"total":      amount,
+			"subtotal":   amount,
+			"discount":   amount,
+		},
+	})
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// AuditLog adalah satu catatan perubahan data. Catatan hanya ditambahkan, tidak pernah
+// diubah atau dihapus, supaya bisa dipakai sebagai bukti saat ada sengketa.
+type AuditLog struct {
+	ID        primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Entity    string              `bson:"entity" json:"entity"` // nama collection, misal "bookings"
+	EntityID  primitive.ObjectID  `bson:"entity_id" json:"entity_id"`
+	Action    string              `bson:"action" json:"action"`
+	ActorID   *primitive.ObjectID `bson:"actor_id" json:"actor_id"` // null untuk proses sistem, misal purger trash
+	Role      string              `bson:"role,omitempty" json:"role,omitempty"`
+	IP        string              `bson:"ip,omitempty" json:"ip,omitempty"`
+	RequestID string              `bson:"request_id,omitempty" json:"request_id,omitempty"`
+	At        time.Time           `bson:"at" json:"at"`
+	Changes   []AuditChange       `bson:"changes" json:"changes"`
+}
+
+// AuditChange adalah perubahan satu field. Before kosong untuk create, After kosong untuk delete.
+type AuditChange struct {
+	Field  string      `bson:"field" json:"field"`
+	Before interface{} `bson:"before" json:"before"`
+	After  interface{} `bson:"after" json:"after"`
+}
@@ -10,16 +10,20 @@ const (
 	BookingStatusPending   = "pending"
 	BookingStatusConfirmed = "confirmed"
 	BookingStatusDone      = "done"
+	BookingStatusCancelled = "cancelled"
+	BookingStatusNoShow    = "no_show" // tanggal lewat tanpa pembayaran, diisi scheduler
 )
 
 type Booking struct {
 	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	ClientID       primitive.ObjectID `bson:"client_id" json:"client_id"`
-	PhotographerID primitive.ObjectID `bson:"photographer_id" json:"photographer_id"`
-	Date           time.Time          `bson:"date" json:"date"` // format ISO8601
-	Location       string             `bson:"location" json:"location"`
-	Status         string             `bson:"status" json:"status"` // gunakan konstanta
-	Note           string             `bson:"note,omitempty" json:"note,omitempty"`
+	ClientID       primitive.ObjectID `bson:"client_id" json:"client_id" validate:"objectid"`
+	PhotographerID primitive.ObjectID `bson:"photographer_id" json:"photographer_id" validate:"objectid"`
+	Date           time.Time          `bson:"date" json:"date" validate:"required"` // format ISO8601
+	Location       string             `bson:"location" json:"location" validate:"max=200"`
+	Status         string             `bson:"status" json:"status" validate:"required,booking_status"` // gunakan konstanta
+	Note           string             `bson:"note,omitempty" json:"note,omitempty" validate:"max=1000"`
 	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt      time.Time          `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+	SoftDelete     `bson:",inline"`
+	Versioned      `bson:",inline"`
 }
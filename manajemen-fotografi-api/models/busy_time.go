@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Sumber jadwal sibuk fotografer
+const (
+	BusySourceICS = "ics" // diimpor dari kalender pribadi, diganti seluruhnya setiap impor
+)
+
+// BusyTime adalah rentang waktu saat fotografer tidak bisa dibooking, misal acara dari
+// kalender pribadinya. Judul acara tidak disimpan karena bisa berisi data pribadi.
+type BusyTime struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	PhotographerID primitive.ObjectID `bson:"photographer_id" json:"photographer_id"`
+	Source         string             `bson:"source" json:"source"`
+	UID            string             `bson:"uid,omitempty" json:"uid,omitempty"` // UID VEVENT asal
+	Start          time.Time          `bson:"start" json:"start"`
+	End            time.Time          `bson:"end" json:"end"`
+	AllDay         bool               `bson:"all_day,omitempty" json:"all_day,omitempty"`
+	ImportedAt     time.Time          `bson:"imported_at" json:"imported_at"`
+}
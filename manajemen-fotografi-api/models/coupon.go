@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Jenis potongan kupon
+const (
+	CouponPercent = "percent" // Value dalam persen dari subtotal, dibatasi MaxDiscount jika diisi
+	CouponFixed   = "fixed"   // Value dalam rupiah
+)
+
+// Coupon adalah kode promo yang dipakai client saat membayar booking
+type Coupon struct {
+	ID              primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	Code            string               `bson:"code" json:"code" validate:"required,min=3,max=32,alphanum"` // disimpan huruf besar, misal WISUDA2026
+	Description     string               `bson:"description" json:"description" validate:"max=200"`
+	Type            string               `bson:"type" json:"type" validate:"required,oneof=percent fixed"`
+	Value           float64              `bson:"value" json:"value" validate:"required,gt=0"`
+	MaxDiscount     float64              `bson:"max_discount" json:"max_discount" validate:"min=0"`         // 0 berarti tanpa batas
+	MinSpend        float64              `bson:"min_spend" json:"min_spend" validate:"min=0"`               // subtotal minimal
+	StartsAt        *time.Time           `bson:"starts_at" json:"starts_at"`                                // nil berarti langsung berlaku
+	EndsAt          *time.Time           `bson:"ends_at" json:"ends_at"`                                    // nil berarti tanpa batas akhir
+	UsageLimit      int                  `bson:"usage_limit" json:"usage_limit" validate:"min=0"`           // total pemakaian, 0 berarti tanpa batas
+	PerClientLimit  int                  `bson:"per_client_limit" json:"per_client_limit" validate:"min=0"` // pemakaian per client, 0 berarti tanpa batas
+	PhotographerIDs []primitive.ObjectID `bson:"photographer_ids" json:"photographer_ids"`                  // kosong berarti semua fotografer
+	Active          bool                 `bson:"active" json:"active"`
+	Redeemed        int                  `bson:"redeemed" json:"redeemed"`
+	Usage           map[string]int       `bson:"usage,omitempty" json:"-"` // pemakaian per client (hex ID), untuk batas per client yang atomik
+	CreatedBy       *primitive.ObjectID  `bson:"created_by,omitempty" json:"created_by,omitempty"`
+	CreatedAt       time.Time            `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time            `bson:"updated_at" json:"updated_at"`
+}
+
+// CouponRedemption mencatat satu pemakaian kupon pada transaksi
+type CouponRedemption struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CouponID      primitive.ObjectID `bson:"coupon_id" json:"coupon_id"`
+	Code          string             `bson:"code" json:"code"`
+	ClientID      primitive.ObjectID `bson:"client_id" json:"client_id"`
+	BookingID     primitive.ObjectID `bson:"booking_id" json:"booking_id"`
+	TransactionID primitive.ObjectID `bson:"transaction_id" json:"transaction_id"`
+	Subtotal      float64            `bson:"subtotal" json:"subtotal"`
+	Discount      float64            `bson:"discount" json:"discount"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Event adalah kejadian domain, misal booking dibuat atau pembayaran diterima.
+// ID berupa ObjectID sehingga urut waktu dan bisa dipakai sebagai Last-Event-ID.
+type Event struct {
+	ID         primitive.ObjectID   `bson:"_id" json:"id"`
+	Type       string               `bson:"type" json:"type"`
+	Recipients []primitive.ObjectID `bson:"recipients" json:"-"` // user yang menerima event secara realtime
+	Data       bson.M               `bson:"data" json:"data"`
+	At         time.Time            `bson:"at" json:"at"`
+}
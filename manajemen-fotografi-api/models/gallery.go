@@ -7,11 +7,14 @@ import (
 )
 
 type Gallery struct {
-	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	PhotographerID primitive.ObjectID `bson:"photographer_id" json:"photographer_id"`
-	Title          string             `bson:"title" json:"title"`
-	ImageURL       string             `bson:"image_url" json:"image_url"` // bisa juga []string jika banyak gambar
-	Description    string             `bson:"description,omitempty" json:"description,omitempty"`
-	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt      time.Time          `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+	ID             primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	PhotographerID primitive.ObjectID  `bson:"photographer_id" json:"photographer_id" validate:"objectid"`
+	BookingID      *primitive.ObjectID `bson:"booking_id,omitempty" json:"booking_id,omitempty" validate:"omitempty,objectid"` // booking yang hasilnya dikirim lewat galeri ini, opsional
+	Title          string              `bson:"title" json:"title" validate:"required,max=100"`
+	ImageURL       string              `bson:"image_url" json:"image_url" validate:"required,url_or_path"` // URL absolut atau path /uploads/... (hanya rujukan, tidak ikut di-purge), bisa juga []string jika banyak gambar
+	Description    string              `bson:"description,omitempty" json:"description,omitempty" validate:"max=1000"`
+	CreatedAt      time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time           `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+	SoftDelete     `bson:",inline"`
+	Versioned      `bson:",inline"`
 }
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	JobScheduled = "scheduled"
+	JobRunning   = "running"
+	JobDone      = "done"
+	JobFailed    = "failed" // gagal setelah percobaan maksimal
+)
+
+// Job adalah pekerjaan background yang disimpan di Mongo. Instance yang mengambil job
+// menguncinya sampai LockedUntil, sehingga setiap job hanya dijalankan satu replica.
+// Job berulang (Every > 0) dijadwalkan ulang setelah selesai.
+type Job struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name        string             `bson:"name" json:"name"`
+	Key         string             `bson:"key" json:"key"` // unik, mencegah job yang sama dijadwalkan dua kali
+	Payload     bson.M             `bson:"payload,omitempty" json:"payload,omitempty"`
+	Status      string             `bson:"status" json:"status"`
+	RunAt       time.Time          `bson:"run_at" json:"run_at"`
+	Every       time.Duration      `bson:"every,omitempty" json:"every,omitempty"`
+	Attempts    int                `bson:"attempts" json:"attempts"`
+	MaxAttempts int                `bson:"max_attempts" json:"max_attempts"`
+	LastError   string             `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	LockedBy    string             `bson:"locked_by,omitempty" json:"locked_by,omitempty"`
+	LockedUntil *time.Time         `bson:"locked_until" json:"locked_until,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	FinishedAt  *time.Time         `bson:"finished_at,omitempty" json:"finished_at,omitempty"`
+}
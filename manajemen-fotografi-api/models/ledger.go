@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Jenis jurnal buku besar
+const (
+	JournalPayment    = "payment"    // transaksi lunas: kas masuk, dibagi utang fotografer dan komisi
+	JournalRefund     = "refund"     // pengembalian dana ke client, membalik pembayaran secara proporsional
+	JournalAdjustment = "adjustment" // koreksi manual oleh admin
+	JournalPayout     = "payout"     // pembayaran utang ke fotografer lewat batch payout
+)
+
+// LedgerJournal adalah satu pencatatan double-entry. Semua baris disimpan dalam satu
+// dokumen sehingga jurnal selalu tersimpan utuh dan seimbang. Nominal dalam rupiah bulat.
+type LedgerJournal struct {
+	ID             primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Key            string              `bson:"key" json:"-"` // unik, mencegah jurnal ganda untuk kejadian yang sama
+	Kind           string              `bson:"kind" json:"kind"`
+	PhotographerID primitive.ObjectID  `bson:"photographer_id" json:"photographer_id"`
+	TransactionID  *primitive.ObjectID `bson:"transaction_id,omitempty" json:"transaction_id,omitempty"`
+	Gross          int64               `bson:"gross,omitempty" json:"gross,omitempty"`       // nominal transaksi, hanya jurnal payment
+	Rate           float64             `bson:"rate,omitempty" json:"rate,omitempty"`         // tarif komisi yang dipakai, hanya jurnal payment
+	Refunded       int64               `bson:"refunded,omitempty" json:"refunded,omitempty"` // total refund atas jurnal payment ini
+	Lines          []LedgerLine        `bson:"lines" json:"lines"`
+	Memo           string              `bson:"memo,omitempty" json:"memo,omitempty"`
+	PayoutID       *primitive.ObjectID `bson:"payout_id" json:"payout_id,omitempty"` // batch payout yang melunasi jurnal ini
+	CreatedBy      *primitive.ObjectID `bson:"created_by,omitempty" json:"created_by,omitempty"`
+	CreatedAt      time.Time           `bson:"created_at" json:"created_at"`
+}
+
+// LedgerLine adalah satu baris debit atau kredit pada sebuah akun
+type LedgerLine struct {
+	Account string `bson:"account" json:"account"`
+	Debit   int64  `bson:"debit" json:"debit"`
+	Credit  int64  `bson:"credit" json:"credit"`
+}
+
+// CommissionRate adalah tarif komisi platform, untuk semua fotografer (default) atau
+// satu fotografer tertentu
+type CommissionRate struct {
+	ID             primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Key            string              `bson:"key" json:"key"` // "default" atau "photographer:<id>"
+	PhotographerID *primitive.ObjectID `bson:"photographer_id,omitempty" json:"photographer_id,omitempty"`
+	Rate           float64             `bson:"rate" json:"rate" validate:"min=0,max=1"` // 0.2 berarti 20%
+	UpdatedBy      *primitive.ObjectID `bson:"updated_by,omitempty" json:"updated_by,omitempty"`
+	UpdatedAt      time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// Status batch payout
+const (
+	PayoutProcessing = "processing"
+	PayoutReady      = "ready"  // siap diekspor ke bank
+	PayoutFailed     = "failed" // gagal dibuat, jurnalnya dilepas untuk batch berikutnya
+)
+
+// PayoutBatch adalah sekumpulan pembayaran ke fotografer untuk jurnal sampai Cutoff
+type PayoutBatch struct {
+	ID        primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Status    string              `bson:"status" json:"status"`
+	Cutoff    time.Time           `bson:"cutoff" json:"cutoff"`
+	Items     []PayoutItem        `bson:"items" json:"items"`
+	Total     int64               `bson:"total" json:"total"`
+	CreatedBy *primitive.ObjectID `bson:"created_by,omitempty" json:"created_by,omitempty"`
+	CreatedAt time.Time           `bson:"created_at" json:"created_at"`
+}
+
+// PayoutItem adalah pembayaran ke satu fotografer dalam batch
+type PayoutItem struct {
+	PhotographerID primitive.ObjectID `bson:"photographer_id" json:"photographer_id"`
+	Amount         int64              `bson:"amount" json:"amount"`
+	Journals       int                `bson:"journals" json:"journals"`     // jumlah jurnal yang dilunasi
+	JournalID      primitive.ObjectID `bson:"journal_id" json:"journal_id"` // jurnal payout
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Message adalah satu pesan di thread booking antara client dan fotografer.
+// Pesan pertama thread bisa berupa salinan Booking.Note (FromNote).
+type Message struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	BookingID   primitive.ObjectID  `bson:"booking_id" json:"booking_id"`
+	SenderID    primitive.ObjectID  `bson:"sender_id" json:"sender_id"`
+	SenderRole  string              `bson:"sender_role" json:"sender_role"`
+	Text        string              `bson:"text,omitempty" json:"text,omitempty" validate:"max=2000"`
+	Attachments []MessageAttachment `bson:"attachments,omitempty" json:"attachments,omitempty"`
+	FromNote    bool                `bson:"from_note,omitempty" json:"from_note,omitempty"`
+	ReadBy      []MessageRead       `bson:"read_by" json:"read_by"` // read receipt, pengirim tidak ikut dicatat
+	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+	SoftDelete  `bson:",inline"`
+}
+
+// MessageAttachment adalah gambar yang dilampirkan pada pesan
+type MessageAttachment struct {
+	URL         string `bson:"url" json:"url"`
+	Name        string `bson:"name" json:"name"`
+	ContentType string `bson:"content_type" json:"content_type"`
+	Size        int64  `bson:"size" json:"size"`
+}
+
+// MessageRead mencatat kapan peserta thread membaca pesan
+type MessageRead struct {
+	UserID primitive.ObjectID `bson:"user_id" json:"user_id"`
+	At     time.Time          `bson:"at" json:"at"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Kanal pengiriman notifikasi
+const (
+	NotificationChannelEmail    = "email"
+	NotificationChannelWhatsApp = "whatsapp"
+	NotificationChannelSMS      = "sms"
+)
+
+// NotificationPreferences memetakan jenis notifikasi (booking_created, payment_receipt, ...)
+// ke kanal yang dipilih user. Jenis yang tidak diatur dikirim lewat email; daftar kosong
+// berarti notifikasi tersebut dimatikan.
+type NotificationPreferences map[string][]string
+
+const (
+	NotificationPending = "pending"
+	NotificationSent    = "sent"
+	NotificationFailed  = "failed" // gagal setelah percobaan maksimal
+)
+
+// Notification adalah satu pesan di outbox. Isi pesan dirender saat masuk outbox sehingga
+// pesan yang tertunda (server restart, SMTP mati) tetap terkirim persis seperti saat dibuat.
+type Notification struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID        primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Channel       string             `bson:"channel" json:"channel"`
+	Template      string             `bson:"template" json:"template"`
+	Lang          string             `bson:"lang" json:"lang"`
+	To            string             `bson:"to" json:"to"`
+	Subject       string             `bson:"subject,omitempty" json:"subject,omitempty"`
+	Text          string             `bson:"text" json:"text"`
+	HTML          string             `bson:"html,omitempty" json:"html,omitempty"`
+	DedupeKey     string             `bson:"dedupe_key" json:"-"` // mencegah pesan ganda untuk event yang sama
+	Status        string             `bson:"status" json:"status"`
+	Attempts      int                `bson:"attempts" json:"attempts"`
+	LastError     string             `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	NextAttemptAt time.Time          `bson:"next_attempt_at" json:"next_attempt_at"`
+	LockedUntil   *time.Time         `bson:"locked_until" json:"-"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+	SentAt        *time.Time         `bson:"sent_at,omitempty" json:"sent_at,omitempty"`
+}
+
+// Channels mengembalikan kanal untuk satu jenis notifikasi, email jika belum diatur
+func (p NotificationPreferences) Channels(kind string) []string {
+	if channels, ok := p[kind]; ok {
+		return channels
+	}
+	return []string{NotificationChannelEmail}
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Jenis item shortlist
+const (
+	ShortlistPhotographer = "photographer"  // profil fotografer
+	ShortlistGalleryImage = "gallery_image" // satu gambar galeri, PhotographerID diisi pemilik galeri
+)
+
+// Shortlist adalah daftar favorit bernama milik client. Daftar bisa dibagikan lewat
+// link berisi token; yang disimpan hanya hash SHA-256 token tersebut.
+type Shortlist struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ClientID       primitive.ObjectID `bson:"client_id" json:"client_id"`
+	Name           string             `bson:"name" json:"name" validate:"required,notblank,max=100"`
+	Items          []ShortlistItem    `bson:"items" json:"items"`
+	ShareTokenHash string             `bson:"share_token_hash,omitempty" json:"-"`
+	Shared         bool               `bson:"-" json:"shared"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// ShortlistItem adalah satu fotografer atau gambar galeri di dalam shortlist
+type ShortlistItem struct {
+	ID             primitive.ObjectID  `bson:"_id" json:"id"`
+	Kind           string              `bson:"kind" json:"kind"`
+	PhotographerID primitive.ObjectID  `bson:"photographer_id" json:"photographer_id"`
+	GalleryID      *primitive.ObjectID `bson:"gallery_id,omitempty" json:"gallery_id,omitempty"`
+	ImageURL       string              `bson:"image_url,omitempty" json:"image_url,omitempty"` // salinan saat ditambahkan
+	Note           string              `bson:"note,omitempty" json:"note,omitempty"`
+	AddedAt        time.Time           `bson:"added_at" json:"added_at"`
+	Available      *bool               `bson:"-" json:"available,omitempty"` // hanya diisi jika ?date= dikirim
+}
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SoftDelete menandai dokumen yang sudah dipindah ke trash. Field selalu disimpan
+// (null jika belum dihapus) supaya index unik bisa dibatasi ke dokumen aktif.
+type SoftDelete struct {
+	DeletedAt *time.Time          `bson:"deleted_at" json:"deleted_at,omitempty"`
+	DeletedBy *primitive.ObjectID `bson:"deleted_by" json:"deleted_by,omitempty"`
+}
@@ -13,10 +13,15 @@ const (
 
 type Transaction struct {
 	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	BookingID  primitive.ObjectID `bson:"booking_id" json:"booking_id"`
-	Method     string             `bson:"method" json:"method"`         // contoh: "transfer", "ewallet"
-	Total      float64            `bson:"total" json:"total"`           // misal: 500000
+	BookingID  primitive.ObjectID `bson:"booking_id" json:"booking_id" validate:"objectid"`
+	Method     string             `bson:"method" json:"method" validate:"required,payment_method"` // contoh: "transfer", "ewallet"; daftar diatur PAYMENT_METHODS
+	Total      float64            `bson:"total" json:"total" validate:"required,gt=0"`                       // misal: 500000
+	Subtotal   float64            `bson:"subtotal,omitempty" json:"subtotal,omitempty"`                       // total sebelum potongan kupon
+	Discount   float64            `bson:"discount,omitempty" json:"discount,omitempty"`                       // potongan kupon
+	CouponCode string             `bson:"coupon_code,omitempty" json:"coupon_code,omitempty" validate:"omitempty,max=32"`
 	Status     string             `bson:"status" json:"status"`         // contoh: "paid", "unpaid"
 	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt  time.Time          `bson:"updated_at,omitempty" json:"updated_at,omitempty"` // opsional
+	SoftDelete     `bson:",inline"`
+	Versioned      `bson:",inline"`
 }
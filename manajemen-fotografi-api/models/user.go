@@ -2,19 +2,22 @@ package models
 
 import "go.mongodb.org/mongo-driver/bson/primitive"
 
-// RoleType membatasi hanya dua role yang diizinkan
+// RoleType membatasi role yang diizinkan
 const (
     RoleClient      = "client"
     RolePhotographer = "photographer"
+    RoleAdmin        = "admin" // tidak bisa dipilih saat register, diberikan lewat "go run . admin grant"
 )
 
 // User mewakili akun login pengguna dengan role tertentu.
 type User struct {
     ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-    Name      string             `bson:"name" json:"name"`
-    Email     string             `bson:"email" json:"email"`
+    Name      string             `bson:"name" json:"name" validate:"required,person_name"`
+    Email     string             `bson:"email" json:"email" validate:"required,email"`
     Password  string             `bson:"password" json:"-"` // disembunyikan dari response JSON
-    Role      string             `bson:"role" json:"role"`  // hanya "client" atau "photographer"
+    Role      string             `bson:"role" json:"role" validate:"required,oneof=client photographer"`  // hanya "client" atau "photographer"
+    Language  string             `bson:"language,omitempty" json:"language,omitempty" validate:"omitempty,oneof=id en"` // preferensi bahasa pesan dan notifikasi
+    Notifications NotificationPreferences `bson:"notification_preferences,omitempty" json:"notification_preferences,omitempty" validate:"omitempty,dive,keys,oneof=booking_created booking_confirmed booking_cancelled booking_reminder booking_message waitlist_offer payment_receipt gallery_delivered,endkeys,unique,dive,oneof=email whatsapp sms"` // kanal per jenis notifikasi
     CreatedAt int64              `bson:"created_at" json:"created_at"`
     UpdatedAt int64              `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
 }
@@ -22,25 +25,31 @@ type User struct {
 // Client menyimpan detail tambahan untuk user dengan role "client".
 type Client struct {
     ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-    UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
-    Name      string             `bson:"name" json:"name"`          // field baru
-    Phone     string             `bson:"phone" json:"phone"`
-    Address   string             `bson:"address,omitempty" json:"address,omitempty"`
+    UserID    primitive.ObjectID `bson:"user_id" json:"user_id" validate:"objectid"`
+    Name      string             `bson:"name" json:"name" validate:"required,person_name"` // field baru
+    Phone     string             `bson:"phone" json:"phone" validate:"required,phone_id"`
+    Address   string             `bson:"address,omitempty" json:"address,omitempty" validate:"required,min=5,max=255"`
     CreatedAt int64              `bson:"created_at" json:"created_at"`
     UpdatedAt int64              `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+    SoftDelete                   `bson:",inline"`
+    Versioned                    `bson:",inline"`
 }
 
 
 // Photographer menyimpan detail tambahan untuk user dengan role "photographer".
 type Photographer struct {
     ID           primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
-    UserID       primitive.ObjectID   `bson:"user_id,omitempty" json:"user_id"`
-    Phone        string               `bson:"phone" json:"phone"`
-    Description  string               `bson:"description" json:"description"`
-    Portfolio    []string             `bson:"portfolio" json:"portfolio"`
-    Location     string               `bson:"location" json:"location"`
+    UserID       primitive.ObjectID   `bson:"user_id,omitempty" json:"user_id" validate:"objectid"`
+    Phone        string               `bson:"phone" json:"phone" validate:"required,phone_id"`
+    Description  string               `bson:"description" json:"description" validate:"max=500"`
+    Portfolio    []string             `bson:"portfolio" json:"portfolio" validate:"dive,notblank"`
+    Location     string               `bson:"location" json:"location" validate:"max=200"`
     ProfilePhoto string               `bson:"profile_photo" json:"profile_photo"` // URL path ke foto profil
+    CalendarTokenHash string          `bson:"calendar_token_hash,omitempty" json:"-"` // SHA-256 token feed kalender, token asli tidak disimpan
+    FavoriteCount int                 `bson:"favorite_count" json:"favorite_count"` // jumlah client yang menyimpan fotografer di shortlist
     CreatedAt    int64                `bson:"created_at" json:"created_at"`
     UpdatedAt    int64                `bson:"updated_at" json:"updated_at"`
+    SoftDelete                        `bson:",inline"`
+    Versioned                         `bson:",inline"`
 }
 
@@ -0,0 +1,12 @@
+package models
+
+// Versioned menyimpan nomor versi dokumen untuk optimistic concurrency.
+// Version naik setiap kali dokumen diubah dan dikirim ke client sebagai ETag.
+type Versioned struct {
+	Version int64 `bson:"version" json:"version"`
+}
+
+// CurrentVersion mengembalikan versi dokumen saat ini
+func (v Versioned) CurrentVersion() int64 {
+	return v.Version
+}
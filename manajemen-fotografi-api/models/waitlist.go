@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Status antrean waitlist
+const (
+	WaitlistWaiting  = "waiting"  // menunggu giliran
+	WaitlistOffered  = "offered"  // mendapat tawaran booking sampai OfferExpiresAt
+	WaitlistBooked   = "booked"   // client sudah booking tanggal tersebut
+	WaitlistExpired  = "expired"  // tawaran atau tanggalnya lewat
+	WaitlistDeclined = "declined" // client menolak tawaran
+	WaitlistLeft     = "left"     // client keluar dari antrean sebelum ditawari
+)
+
+// WaitlistEntry adalah antrean client untuk tanggal fotografer yang sudah penuh.
+// Urutan antrean mengikuti CreatedAt.
+type WaitlistEntry struct {
+	ID             primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	PhotographerID primitive.ObjectID  `bson:"photographer_id" json:"photographer_id"`
+	ClientID       primitive.ObjectID  `bson:"client_id" json:"client_id"`
+	Date           time.Time           `bson:"date" json:"date"` // awal hari dalam WIB
+	Status         string              `bson:"status" json:"status"`
+	Active         bool                `bson:"active,omitempty" json:"-"`     // waiting atau offered, untuk index unik per client
+	OfferOpen      bool                `bson:"offer_open,omitempty" json:"-"` // offered, untuk index unik satu tawaran per tanggal
+	OfferedAt      *time.Time          `bson:"offered_at,omitempty" json:"offered_at,omitempty"`
+	OfferExpiresAt *time.Time          `bson:"offer_expires_at,omitempty" json:"offer_expires_at,omitempty"`
+	BookingID      *primitive.ObjectID `bson:"booking_id,omitempty" json:"booking_id,omitempty"`
+	CreatedAt      time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time           `bson:"updated_at" json:"updated_at"`
+}
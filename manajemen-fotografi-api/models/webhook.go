@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead" // gagal setelah percobaan maksimal, masuk dead-letter
+)
+
+// Webhook adalah endpoint partner (misal lab cetak) yang menerima event domain.
+// Secret dipakai menandatangani payload dan hanya ditampilkan sekali saat dibuat.
+type Webhook struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name      string             `bson:"name" json:"name" validate:"required,max=100"`
+	URL       string             `bson:"url" json:"url" validate:"required,url"`
+	Events    []string           `bson:"events" json:"events" validate:"required,min=1,dive,oneof=booking.created booking.status_changed transaction.paid gallery.published"`
+	Secret    string             `bson:"secret" json:"secret,omitempty"`
+	Active    bool               `bson:"active" json:"active"`
+	CreatedBy primitive.ObjectID `bson:"created_by" json:"created_by"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// WebhookDelivery adalah satu event yang harus dikirim ke satu webhook, sekaligus log
+// pengirimannya. Delivery berstatus dead adalah isi dead-letter yang bisa dikirim ulang.
+type WebhookDelivery struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	WebhookID     primitive.ObjectID `bson:"webhook_id" json:"webhook_id"`
+	EventID       primitive.ObjectID `bson:"event_id" json:"event_id"`
+	EventType     string             `bson:"event_type" json:"event_type"`
+	Payload       string             `bson:"payload" json:"payload"` // body JSON persis seperti yang ditandatangani
+	Status        string             `bson:"status" json:"status"`
+	Attempts      []WebhookAttempt   `bson:"attempts" json:"attempts"`
+	RetryFrom     int                `bson:"retry_from" json:"-"` // jumlah percobaan sebelum retry manual terakhir
+	NextAttemptAt time.Time          `bson:"next_attempt_at" json:"next_attempt_at"`
+	LockedUntil   *time.Time         `bson:"locked_until" json:"-"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+	DeliveredAt   *time.Time         `bson:"delivered_at,omitempty" json:"delivered_at,omitempty"`
+}
+
+// WebhookAttempt adalah hasil satu percobaan kirim
+type WebhookAttempt struct {
+	At         time.Time `bson:"at" json:"at"`
+	StatusCode int       `bson:"status_code,omitempty" json:"status_code,omitempty"`
+	Error      string    `bson:"error,omitempty" json:"error,omitempty"`
+	DurationMs int64     `bson:"duration_ms" json:"duration_ms"`
+}
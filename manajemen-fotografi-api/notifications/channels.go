@@ -0,0 +1,109 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"manajemen-fotografi-api/mail"
+	"manajemen-fotografi-api/messaging"
+	"manajemen-fotografi-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Default batas kirim WhatsApp/SMS, bisa diganti lewat MESSAGING_RATE (pesan per detik
+// ke provider) dan MESSAGING_USER_LIMIT (pesan per user per kanal per jam)
+const (
+	DefaultMessagingRate = 5
+	DefaultUserLimit     = 10
+)
+
+// userLimitWindow adalah jendela waktu batas pesan per user
+const userLimitWindow = time.Hour
+
+// Channels berisi pengirim untuk setiap kanal. Pesan untuk kanal yang tidak diisi
+// dianggap gagal dan dicoba lagi sampai batas percobaan.
+type Channels struct {
+	Mail     mail.Mailer
+	WhatsApp messaging.Sender
+	SMS      messaging.Sender
+}
+
+// ChannelsFromEnv memilih pengirim dari environment; WhatsApp dan SMS dibatasi
+// MESSAGING_RATE pesan per detik supaya tidak melewati kuota provider
+func ChannelsFromEnv() Channels {
+	rate, err := strconv.ParseFloat(os.Getenv("MESSAGING_RATE"), 64)
+	if err != nil || rate <= 0 {
+		rate = DefaultMessagingRate
+	}
+	return Channels{
+		Mail:     mail.FromEnv(),
+		WhatsApp: messaging.Throttle(messaging.WhatsAppFromEnv(), rate),
+		SMS:      messaging.Throttle(messaging.SMSFromEnv(), rate),
+	}
+}
+
+// UserLimit membaca MESSAGING_USER_LIMIT, default 10 pesan per user per kanal per jam
+func UserLimit() int {
+	n, err := strconv.Atoi(os.Getenv("MESSAGING_USER_LIMIT"))
+	if err != nil || n < 1 {
+		return DefaultUserLimit
+	}
+	return n
+}
+
+func (c Channels) send(ctx context.Context, n models.Notification) error {
+	var sender messaging.Sender
+	switch n.Channel {
+	case models.NotificationChannelEmail:
+		if c.Mail == nil {
+			return fmt.Errorf("kanal %s tidak dikonfigurasi", n.Channel)
+		}
+		return c.Mail.Send(ctx, mail.Message{To: n.To, Subject: n.Subject, Text: n.Text, HTML: n.HTML})
+	case models.NotificationChannelWhatsApp:
+		sender = c.WhatsApp
+	case models.NotificationChannelSMS:
+		sender = c.SMS
+	}
+	if sender == nil {
+		return fmt.Errorf("kanal %s tidak dikonfigurasi", n.Channel)
+	}
+	return sender.Send(ctx, messaging.Message{To: n.To, Text: n.Text})
+}
+
+// rateLimited mengembalikan lama pesan WhatsApp/SMS harus ditunda karena user sudah
+// menerima UserLimit pesan di kanal yang sama dalam satu jam terakhir, atau 0 jika boleh dikirim.
+// Email tidak dibatasi.
+func rateLimited(ctx context.Context, n models.Notification, now time.Time) (time.Duration, error) {
+	if n.Channel == models.NotificationChannelEmail {
+		return 0, nil
+	}
+
+	filter := bson.M{
+		"user_id": n.UserID,
+		"channel": n.Channel,
+		"status":  models.NotificationSent,
+		"sent_at": bson.M{"$gte": now.Add(-userLimitWindow)},
+	}
+	count, err := collection(Collection).CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	if count < int64(UserLimit()) {
+		return 0, nil
+	}
+
+	// Ditunda sampai pesan tertua di jendela keluar dari hitungan
+	var oldest models.Notification
+	err = collection(Collection).FindOne(ctx, filter,
+		options.FindOne().SetSort(bson.D{{Key: "sent_at", Value: 1}}),
+	).Decode(&oldest)
+	if err != nil || oldest.SentAt == nil {
+		return userLimitWindow, err
+	}
+	return max(oldest.SentAt.Add(userLimitWindow).Sub(now), time.Second), nil
+}
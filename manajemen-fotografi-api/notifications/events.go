@@ -0,0 +1,170 @@
+package notifications
+
+import (
+	"context"
+	"strings"
+
+	"manajemen-fotografi-api/events"
+	"manajemen-fotografi-api/logging"
+	"manajemen-fotografi-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// statusTemplates memetakan status tujuan booking.status_changed ke template.
+// Perubahan status lain tidak dikirim lewat email.
+var statusTemplates = map[string]string{
+	models.BookingStatusConfirmed: BookingConfirmed,
+	models.BookingStatusCancelled: BookingCancelled,
+}
+
+func consume(ctx context.Context) {
+	events.Consume(ctx, "notifications", HandleEvent)
+}
+
+// HandleEvent memasukkan notifikasi untuk penerima event ke outbox. Booking dikabarkan ke
+// client dan fotografer, bukti bayar dan galeri hanya ke client.
+func HandleEvent(ctx context.Context, event models.Event) {
+	logger := logging.FromContext(ctx)
+
+	var err error
+	switch event.Type {
+	case events.BookingCreated:
+		var booking models.Booking
+		if err = decode(event, "booking", &booking); err == nil {
+			err = notifyRecipients(ctx, event, BookingCreated, BookingParams(booking))
+		}
+
+	case events.BookingStatusChanged:
+		var booking models.Booking
+		to, _ := event.Data["to"].(string)
+		template, ok := statusTemplates[to]
+		if !ok {
+			return
+		}
+		if err = decode(event, "booking", &booking); err == nil {
+			err = notifyRecipients(ctx, event, template, BookingParams(booking))
+		}
+
+	case events.TransactionPaid:
+		var trx models.Transaction
+		if err = decode(event, "transaction", &trx); err != nil {
+			break
+		}
+		var booking models.Booking
+		if err = collection("bookings").FindOne(ctx, bson.M{"_id": trx.BookingID}).Decode(&booking); err != nil {
+			break
+		}
+		params := BookingParams(booking)
+		params["amount"] = formatRupiah(trx.Total)
+		params["method"] = trx.Method
+		err = notifyRecipients(ctx, event, PaymentReceipt, params, models.RoleClient)
+
+	case events.MessageCreated:
+		var message models.Message
+		if err = decode(event, "message", &message); err != nil {
+			break
+		}
+		var booking models.Booking
+		if err = collection("bookings").FindOne(ctx, bson.M{"_id": message.BookingID}).Decode(&booking); err != nil {
+			break
+		}
+		params := BookingParams(booking)
+		params["preview"] = messagePreview(message)
+		err = notifyRecipients(ctx, event, BookingMessage, params)
+
+	case events.WaitlistOffered:
+		var entry models.WaitlistEntry
+		if err = decode(event, "entry", &entry); err == nil && entry.OfferExpiresAt != nil {
+			err = notifyRecipients(ctx, event, WaitlistOffer, map[string]string{
+				"date":    formatDay(entry.Date),
+				"expires": formatDate(*entry.OfferExpiresAt),
+			}, models.RoleClient)
+		}
+
+	case events.GalleryPublished:
+		var gallery models.Gallery
+		if err = decode(event, "gallery", &gallery); err == nil {
+			err = notifyRecipients(ctx, event, GalleryDelivered, map[string]string{
+				"title": gallery.Title,
+				"url":   gallery.ImageURL,
+			}, models.RoleClient)
+		}
+	}
+
+	if err != nil {
+		logger.ErrorContext(ctx, "gagal membuat notifikasi untuk event",
+			"event_id", event.ID.Hex(), "type", event.Type, "error", err)
+	}
+}
+
+// previewLength adalah jumlah karakter pesan chat yang ikut dalam notifikasi
+const previewLength = 160
+
+// messagePreview adalah potongan teks pesan, atau nama lampiran jika pesan hanya berisi gambar
+func messagePreview(message models.Message) string {
+	if message.Text == "" {
+		names := make([]string, 0, len(message.Attachments))
+		for _, a := range message.Attachments {
+			names = append(names, "["+a.Name+"]")
+		}
+		return strings.Join(names, " ")
+	}
+	runes := []rune(message.Text)
+	if len(runes) > previewLength {
+		return string(runes[:previewLength]) + "…"
+	}
+	return message.Text
+}
+
+// BookingParams adalah placeholder pesan untuk sebuah booking
+func BookingParams(booking models.Booking) map[string]string {
+	return map[string]string{
+		"date":     formatDate(booking.Date),
+		"location": booking.Location,
+	}
+}
+
+// notifyRecipients memasukkan notifikasi untuk penerima event, hanya untuk role tertentu
+// jika roles diisi
+func notifyRecipients(ctx context.Context, event models.Event, template string, params map[string]string, roles ...string) error {
+	return NotifyUsers(ctx, event.Recipients, template, params, event.ID.Hex(), roles...)
+}
+
+// NotifyUsers memanggil Notify untuk setiap user, hanya untuk role tertentu jika roles diisi.
+// dedupeKey dilengkapi ID user sehingga setiap user menerima satu pesan.
+func NotifyUsers(ctx context.Context, userIDs []primitive.ObjectID, template string, params map[string]string, dedupeKey string, roles ...string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	filter := bson.M{"_id": bson.M{"$in": userIDs}}
+	if len(roles) > 0 {
+		filter["role"] = bson.M{"$in": roles}
+	}
+
+	cursor, err := collection("users").Find(ctx, filter)
+	if err != nil {
+		return err
+	}
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if err := Notify(ctx, user, template, params, dedupeKey+":"+user.ID.Hex()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decode membaca data event (dokumen BSON) ke struct model
+func decode(event models.Event, key string, out interface{}) error {
+	raw, err := bson.Marshal(event.Data[key])
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(raw, out)
+}
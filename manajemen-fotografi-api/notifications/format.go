@@ -0,0 +1,41 @@
+package notifications
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// wib adalah zona waktu tanggal di pesan; fallback ke UTC+7 jika tzdata tidak tersedia
+var wib = loadWIB()
+
+func loadWIB() *time.Location {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		return time.FixedZone("WIB", 7*60*60)
+	}
+	return loc
+}
+
+// formatDate menampilkan tanggal booking dalam WIB, misal "17-08-2025 09:00 WIB"
+func formatDate(t time.Time) string {
+	return t.In(wib).Format("02-01-2006 15:04 MST")
+}
+
+// formatDay menampilkan tanggal tanpa jam dalam WIB, misal "17-08-2025"
+func formatDay(t time.Time) string {
+	return t.In(wib).Format("02-01-2006")
+}
+
+// formatRupiah menampilkan nominal dengan pemisah ribuan titik, misal "Rp 1.500.000"
+func formatRupiah(amount float64) string {
+	digits := strconv.FormatInt(int64(amount+0.5), 10)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+	return "Rp " + b.String()
+}
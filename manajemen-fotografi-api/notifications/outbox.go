@@ -0,0 +1,255 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"manajemen-fotografi-api/i18n"
+	"manajemen-fotografi-api/logging"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection adalah outbox notifikasi
+const Collection = "notification_outbox"
+
+// Default pengiriman, bisa diganti lewat NOTIFY_MAX_ATTEMPTS dan NOTIFY_POLL_INTERVAL
+const (
+	DefaultMaxAttempts  = 5
+	DefaultPollInterval = 10 * time.Second
+)
+
+// Batas backoff antar percobaan kirim
+const (
+	baseBackoff = time.Minute
+	maxBackoff  = time.Hour
+)
+
+// lease adalah lama pesan dikunci satu instance selama dikirim
+const lease = time.Minute
+
+// batchSize membatasi jumlah pesan yang dikirim dalam satu putaran
+const batchSize = 50
+
+// collection diambil saat dipakai, bukan saat package di-load,
+// supaya render dan pengiriman bisa diuji tanpa koneksi database
+var collection = repositories.New
+
+// MaxAttempts membaca NOTIFY_MAX_ATTEMPTS, default 5 percobaan
+func MaxAttempts() int {
+	n, err := strconv.Atoi(os.Getenv("NOTIFY_MAX_ATTEMPTS"))
+	if err != nil || n < 1 {
+		return DefaultMaxAttempts
+	}
+	return n
+}
+
+func pollInterval() time.Duration {
+	d, err := time.ParseDuration(os.Getenv("NOTIFY_POLL_INTERVAL"))
+	if err != nil || d <= 0 {
+		return DefaultPollInterval
+	}
+	return d
+}
+
+// Backoff adalah jeda sebelum percobaan berikutnya: 1 menit, 2 menit, 4 menit, maksimal 1 jam
+func Backoff(attempt int) time.Duration {
+	d := baseBackoff
+	for i := 1; i < attempt && d < maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxBackoff)
+}
+
+// Notify merender template dalam bahasa pilihan user lalu memasukkannya ke outbox untuk
+// setiap kanal yang dipilih user. dedupeKey yang sama hanya menghasilkan satu pesan per
+// kanal, misal saat event yang sama diproses beberapa instance.
+func Notify(ctx context.Context, user models.User, template string, params map[string]string, dedupeKey string) error {
+	lang := i18n.Resolve(user.Language, "")
+	all := map[string]string{"name": user.Name}
+	for k, v := range params {
+		all[k] = v
+	}
+
+	content, err := Render(lang, template, all)
+	if err != nil {
+		return err
+	}
+
+	channels := []string{models.NotificationChannelEmail}
+	if template != Registration {
+		channels = user.Notifications.Channels(template)
+	}
+
+	var phone string
+	now := time.Now()
+	for _, channel := range channels {
+		n := models.Notification{
+			ID:            primitive.NewObjectID(),
+			UserID:        user.ID,
+			Channel:       channel,
+			Template:      template,
+			Lang:          lang,
+			To:            user.Email,
+			Subject:       content.Subject,
+			Text:          content.Text,
+			HTML:          content.HTML,
+			DedupeKey:     channel + ":" + dedupeKey,
+			Status:        models.NotificationPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+
+		if channel != models.NotificationChannelEmail {
+			if phone == "" {
+				if phone, err = phoneOf(ctx, user); err != nil {
+					// Tanpa nomor telepon kanal ini dilewati, kanal lain tetap dikirim
+					logging.FromContext(ctx).WarnContext(ctx, "nomor telepon user tidak ditemukan",
+						"user_id", user.ID.Hex(), "channel", channel, "error", err)
+					continue
+				}
+			}
+			n.To, n.Subject, n.HTML = phone, "", ""
+			if channel == models.NotificationChannelSMS {
+				n.Text = content.Short
+			}
+		}
+
+		_, err := collection(Collection).InsertOne(ctx, n)
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// phoneOf mengambil nomor telepon dari profil client atau fotografer milik user, dalam format E.164
+func phoneOf(ctx context.Context, user models.User) (string, error) {
+	profiles := map[string]string{
+		models.RoleClient:       "clients",
+		models.RolePhotographer: "photographers",
+	}
+	name, ok := profiles[user.Role]
+	if !ok {
+		return "", fmt.Errorf("role %s tidak memiliki profil", user.Role)
+	}
+
+	var profile struct {
+		Phone string `bson:"phone"`
+	}
+	err := collection(name).FindOne(ctx, bson.M{"user_id": user.ID},
+		options.FindOne().SetProjection(bson.M{"phone": 1}),
+	).Decode(&profile)
+	if err != nil {
+		return "", err
+	}
+	if !utils.IsValidPhone(profile.Phone) {
+		return "", fmt.Errorf("nomor telepon profil tidak valid")
+	}
+	return utils.NormalizePhone(profile.Phone), nil
+}
+
+// Start mengubah event domain menjadi notifikasi dan mengirim isi outbox di background
+// sampai ctx dibatalkan
+func Start(ctx context.Context, channels Channels) {
+	go consume(ctx)
+
+	go func() {
+		ticker := time.NewTicker(pollInterval())
+		defer ticker.Stop()
+		for {
+			ProcessDue(ctx, channels, time.Now())
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// ProcessDue mengirim pesan yang jatuh tempo pada now, maksimal satu batch.
+// Mengembalikan jumlah pesan yang diproses.
+func ProcessDue(ctx context.Context, channels Channels, now time.Time) int {
+	logger := logging.FromContext(ctx)
+	outbox := collection(Collection)
+
+	processed := 0
+	for ; processed < batchSize && ctx.Err() == nil; processed++ {
+		var n models.Notification
+		err := outbox.FindOneAndUpdate(ctx,
+			bson.M{
+				"status":          models.NotificationPending,
+				"next_attempt_at": bson.M{"$lte": now},
+				"$or":             bson.A{bson.M{"locked_until": nil}, bson.M{"locked_until": bson.M{"$lt": now}}},
+			},
+			bson.M{"$set": bson.M{"locked_until": now.Add(lease)}},
+			options.FindOneAndUpdate().SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).SetReturnDocument(options.After),
+		).Decode(&n)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			logger.ErrorContext(ctx, "gagal mengambil notifikasi dari outbox", "error", err)
+			break
+		}
+
+		if err := deliver(ctx, channels, n, now); err != nil {
+			logger.ErrorContext(ctx, "gagal menyimpan hasil kirim notifikasi", "notification_id", n.ID.Hex(), "error", err)
+		}
+	}
+	return processed
+}
+
+// deliver mengirim satu pesan dan mencatat hasilnya. Pesan yang terkena batas per user
+// ditunda tanpa dihitung sebagai percobaan.
+func deliver(ctx context.Context, channels Channels, n models.Notification, now time.Time) error {
+	wait, err := rateLimited(ctx, n, now)
+	if err != nil {
+		return err
+	}
+	if wait > 0 {
+		_, err := collection(Collection).UpdateOne(ctx, bson.M{"_id": n.ID}, bson.M{"$set": bson.M{
+			"locked_until":    nil,
+			"next_attempt_at": now.Add(wait),
+		}})
+		return err
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	err = channels.send(sendCtx, n)
+	cancel()
+
+	sentAt := time.Now()
+	attempts := n.Attempts + 1
+	set := bson.M{"locked_until": nil, "attempts": attempts}
+	switch {
+	case err == nil:
+		set["status"] = models.NotificationSent
+		set["sent_at"] = sentAt
+		set["last_error"] = ""
+	case attempts >= MaxAttempts():
+		set["status"] = models.NotificationFailed
+		set["last_error"] = err.Error()
+	default:
+		set["next_attempt_at"] = sentAt.Add(Backoff(attempts))
+		set["last_error"] = err.Error()
+	}
+	if err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "kirim notifikasi gagal",
+			"notification_id", n.ID.Hex(), "channel", n.Channel, "attempt", attempts, "error", err)
+	}
+
+	_, err = collection(Collection).UpdateOne(ctx, bson.M{"_id": n.ID}, bson.M{"$set": set})
+	return err
+}
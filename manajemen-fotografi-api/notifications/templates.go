@@ -0,0 +1,91 @@
+package notifications
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"text/template"
+
+	"manajemen-fotografi-api/i18n"
+)
+
+// Nama template, sesuai kunci katalog email.<nama>.subject dan email.<nama>.body
+const (
+	Registration     = "registration"
+	BookingCreated   = "booking_created"
+	BookingConfirmed = "booking_confirmed"
+	BookingCancelled = "booking_cancelled"
+	BookingReminder  = "booking_reminder"
+	BookingMessage   = "booking_message"
+	WaitlistOffer    = "waitlist_offer"
+	PaymentReceipt   = "payment_receipt"
+	GalleryDelivered = "gallery_delivered"
+)
+
+// Kinds adalah jenis notifikasi yang kanalnya bisa diatur user. Email registrasi
+// selalu dikirim lewat email karena user belum sempat memilih.
+var Kinds = []string{BookingCreated, BookingConfirmed, BookingCancelled, BookingReminder, BookingMessage, WaitlistOffer, PaymentReceipt, GalleryDelivered}
+
+// Content adalah hasil render satu template dalam satu bahasa. Text dipakai untuk email
+// dan WhatsApp, Short (isi pesan tanpa sapaan dan penutup) untuk SMS.
+type Content struct {
+	Subject string
+	Text    string
+	HTML    string
+	Short   string
+}
+
+// layout adalah data yang diisi ke kerangka email; semua teks sudah diterjemahkan
+type layout struct {
+	Lang     string
+	Subject  string
+	Greeting string
+	Body     string
+	Footer   string
+}
+
+var textLayout = template.Must(template.New("text").Parse(`{{.Greeting}}
+
+{{.Body}}
+
+{{.Footer}}
+`))
+
+// htmlLayout meng-escape semua teks, termasuk nilai dari user seperti nama dan lokasi
+var htmlLayout = htmltemplate.Must(htmltemplate.New("html").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head><meta charset="utf-8"><title>{{.Subject}}</title></head>
+<body style="font-family: Arial, sans-serif; color: #222222; line-height: 1.5;">
+<p>{{.Greeting}}</p>
+<p>{{.Body}}</p>
+<p style="color: #666666;">{{.Footer}}</p>
+</body>
+</html>
+`))
+
+// Render menerjemahkan template ke bahasa lang. params berisi nilai placeholder pesan,
+// termasuk {name} untuk sapaan.
+func Render(lang, name string, params map[string]string) (Content, error) {
+	subjectKey, bodyKey := "email."+name+".subject", "email."+name+".body"
+	if !i18n.Has(subjectKey) || !i18n.Has(bodyKey) {
+		return Content{}, fmt.Errorf("template notifikasi %q tidak dikenal", name)
+	}
+	lang = i18n.Resolve(lang, "")
+
+	data := layout{
+		Lang:     lang,
+		Subject:  i18n.T(lang, subjectKey, params),
+		Greeting: i18n.T(lang, "email.greeting", params),
+		Body:     i18n.T(lang, bodyKey, params),
+		Footer:   i18n.T(lang, "email.footer", params),
+	}
+
+	var text, html bytes.Buffer
+	if err := textLayout.Execute(&text, data); err != nil {
+		return Content{}, err
+	}
+	if err := htmlLayout.Execute(&html, data); err != nil {
+		return Content{}, err
+	}
+	return Content{Subject: data.Subject, Text: text.String(), HTML: html.String(), Short: data.Body}, nil
+}
@@ -0,0 +1,169 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"manajemen-fotografi-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ContentType adalah media type JSON Merge Patch (RFC 7396)
+const ContentType = "application/merge-patch+json"
+
+// Apply menerapkan JSON Merge Patch ke target (pointer ke struct model):
+// field yang tidak dikirim tidak berubah, null menghapus field (menjadi zero value)
+// dan nilai lain mengganti field. Object bersarang di-merge secara rekursif.
+// Hanya field JSON top-level di allowed yang boleh dikirim.
+// Mengembalikan nama field JSON top-level yang diubah, sesuai urutan allowed.
+func Apply(target interface{}, body []byte, allowed ...string) ([]string, error) {
+	var doc map[string]interface{}
+	if err := decode(body, &doc); err != nil || doc == nil {
+		// Merge patch selain object akan mengganti seluruh dokumen, tidak didukung
+		return nil, utils.ErrInvalidBody.Wrap(err)
+	}
+
+	keys := make([]string, 0, len(doc))
+	for key := range doc {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var fields []string
+	var details []utils.FieldError
+	for _, key := range keys {
+		if !contains(allowed, key) {
+			details = append(details, utils.Invalid(key, "patch.field_not_allowed").Details...)
+		}
+	}
+	if len(details) > 0 {
+		return nil, utils.ErrValidation.WithDetails(details...)
+	}
+	for _, key := range allowed {
+		if _, ok := doc[key]; ok {
+			fields = append(fields, key)
+		}
+	}
+
+	current, err := json.Marshal(target)
+	if err != nil {
+		return nil, utils.ErrInternal.Wrap(err)
+	}
+	var original map[string]interface{}
+	if err := decode(current, &original); err != nil {
+		return nil, utils.ErrInternal.Wrap(err)
+	}
+
+	merged, err := json.Marshal(Merge(original, doc))
+	if err != nil {
+		return nil, utils.ErrInternal.Wrap(err)
+	}
+
+	// Target dikosongkan dulu supaya field yang di-null-kan benar-benar hilang
+	v := reflect.ValueOf(target).Elem()
+	v.Set(reflect.Zero(v.Type()))
+	if err := json.Unmarshal(merged, target); err != nil {
+		// Tipe nilai tidak cocok, misal teks untuk field tanggal
+		return nil, utils.ErrInvalidBody.Wrap(err)
+	}
+	return fields, nil
+}
+
+// Merge adalah algoritma MergePatch dari RFC 7396 untuk nilai hasil decode JSON
+func Merge(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = map[string]interface{}{}
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = Merge(targetObj[key], value)
+	}
+	return targetObj
+}
+
+// Update membuat update Mongo untuk field JSON tertentu dari dokumen hasil Apply.
+// Field yang kosong dan ditandai omitempty di bson di-$unset, sisanya di-$set.
+func Update(doc interface{}, fields ...string) (bson.M, error) {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var values bson.M
+	if err := bson.Unmarshal(raw, &values); err != nil {
+		return nil, err
+	}
+
+	names := bsonNames(reflect.TypeOf(doc))
+	set, unset := bson.M{}, bson.M{}
+	for _, field := range fields {
+		name, ok := names[field]
+		if !ok {
+			name = field
+		}
+		if v, ok := values[name]; ok {
+			set[name] = v
+		} else {
+			unset[name] = ""
+		}
+	}
+
+	update := bson.M{}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	return update, nil
+}
+
+// bsonNames memetakan nama field JSON ke nama field bson pada struct model
+func bsonNames(t reflect.Type) map[string]string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	names := map[string]string{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		jsonName := tagName(f.Tag.Get("json"))
+		bsonName := tagName(f.Tag.Get("bson"))
+		if jsonName == "" || jsonName == "-" || bsonName == "" || bsonName == "-" {
+			continue
+		}
+		names[jsonName] = bsonName
+	}
+	return names
+}
+
+func tagName(tag string) string {
+	return strings.SplitN(tag, ",", 2)[0]
+}
+
+// decode membaca JSON dengan angka tetap utuh (json.Number), supaya nilai besar tidak berubah
+// saat dokumen di-marshal ulang
+func decode(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
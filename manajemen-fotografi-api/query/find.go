@@ -0,0 +1,101 @@
+package query
+
+import (
+	"context"
+
+	"manajemen-fotografi-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Finder adalah bagian repositories.Collection yang dibutuhkan untuk menjalankan query list
+type Finder interface {
+	Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error)
+	CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error)
+}
+
+// Find menjalankan query ke collection dan mengembalikan satu halaman data beserta meta.
+// Total dihitung dari filter tanpa posisi cursor, sehingga sama untuk semua halaman.
+func Find[T any](ctx context.Context, coll Finder, q *Query) ([]T, *utils.Meta, error) {
+	total, err := coll.CountDocuments(ctx, q.Filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	direction := 1
+	if q.SortDesc {
+		direction = -1
+	}
+	sort := bson.D{{Key: q.SortField, Value: direction}}
+	if q.SortField != "_id" {
+		// _id sebagai pemecah seri agar urutan stabil antar halaman
+		sort = append(sort, bson.E{Key: "_id", Value: direction})
+	}
+
+	// Ambil satu dokumen lebih untuk mengetahui apakah masih ada halaman berikutnya
+	opts := options.Find().SetSort(sort).SetLimit(int64(q.Limit + 1))
+	filter := q.Filter
+	switch {
+	case q.Cursor == nil:
+		opts.SetSkip(int64((q.Page - 1) * q.Limit))
+	case !q.Cursor.ID.IsZero():
+		filter = bson.M{"$and": bson.A{q.Filter, q.after()}}
+	}
+
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var raws []bson.Raw
+	if err := cursor.All(ctx, &raws); err != nil {
+		return nil, nil, err
+	}
+
+	meta := &utils.Meta{Limit: q.Limit, Total: total}
+	if q.Cursor == nil {
+		meta.Page = q.Page
+	}
+
+	hasMore := len(raws) > q.Limit
+	if hasMore {
+		raws = raws[:q.Limit]
+	}
+
+	items := make([]T, 0, len(raws))
+	for _, raw := range raws {
+		var item T
+		if err := bson.Unmarshal(raw, &item); err != nil {
+			return nil, nil, err
+		}
+		items = append(items, item)
+	}
+
+	if hasMore && q.Cursor != nil {
+		last := raws[len(raws)-1]
+		next := Cursor{Value: last.Lookup(q.SortField), ID: last.Lookup("_id").ObjectID()}
+		if meta.NextCursor, err = EncodeCursor(next); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return items, meta, nil
+}
+
+// after membuat filter dokumen yang berada setelah cursor sesuai arah sort
+func (q *Query) after() bson.M {
+	op := "$gt"
+	if q.SortDesc {
+		op = "$lt"
+	}
+	if q.SortField == "_id" {
+		return bson.M{"_id": bson.M{op: q.Cursor.ID}}
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{q.SortField: bson.M{op: q.Cursor.Value}},
+		bson.M{q.SortField: q.Cursor.Value, "_id": bson.M{op: q.Cursor.ID}},
+	}}
+}
@@ -0,0 +1,65 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"manajemen-fotografi-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetLinks menulis header Link (RFC 8288) untuk navigasi halaman.
+// Pagination berbasis page mendapat first/prev/next/last, berbasis cursor hanya next,
+// sehingga setiap respons punya paling banyak satu rel="next".
+func SetLinks(c *fiber.Ctx, q *Query, meta *utils.Meta) {
+	var links []string
+	add := func(rel string, params map[string]string) {
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, pageURL(c, params), rel))
+	}
+
+	if q.Cursor != nil {
+		if meta.NextCursor != "" {
+			add("next", map[string]string{"cursor": meta.NextCursor, "page": ""})
+		}
+	} else {
+		last := int((meta.Total + int64(q.Limit) - 1) / int64(q.Limit))
+		last = max(last, 1)
+
+		add("first", map[string]string{"page": "1", "cursor": ""})
+		if q.Page > 1 {
+			add("prev", map[string]string{"page": strconv.Itoa(min(q.Page-1, last)), "cursor": ""})
+		}
+		if q.Page < last {
+			add("next", map[string]string{"page": strconv.Itoa(q.Page + 1), "cursor": ""})
+		}
+		add("last", map[string]string{"page": strconv.Itoa(last), "cursor": ""})
+	}
+
+	if len(links) > 0 {
+		c.Set(fiber.HeaderLink, strings.Join(links, ", "))
+	}
+}
+
+// pageURL menyalin URL request dengan parameter yang diganti; nilai kosong menghapus parameter
+func pageURL(c *fiber.Ctx, params map[string]string) string {
+	args := c.Request().URI().QueryArgs()
+	query := fiber.AcquireArgs()
+	defer fiber.ReleaseArgs(query)
+	args.CopyTo(query)
+
+	for k, v := range params {
+		if v == "" {
+			query.Del(k)
+			continue
+		}
+		query.Set(k, v)
+	}
+
+	url := c.BaseURL() + c.Path()
+	if encoded := query.String(); encoded != "" {
+		url += "?" + encoded
+	}
+	return url
+}
@@ -0,0 +1,263 @@
+package query
+
+import (
+	"encoding/base64"
+	"errors"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"manajemen-fotografi-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Batas default pagination
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Type menentukan cara nilai filter dari query string diubah ke tipe Mongo
+type Type int
+
+const (
+	String Type = iota
+	ObjectID
+	Time
+	Number
+)
+
+// Op adalah operator perbandingan filter
+type Op string
+
+const (
+	Eq     Op = "$eq"  // sama dengan, nilai dipisah koma menjadi $in
+	Gte    Op = "$gte" // lebih besar atau sama dengan
+	Lte    Op = "$lte" // lebih kecil atau sama dengan, tanggal tanpa jam dihitung sampai akhir hari
+	Prefix Op = "prefix"
+)
+
+// Filter memetakan satu parameter query string ke field dokumen
+type Filter struct {
+	Field string
+	Type  Type
+	Op    Op
+}
+
+// Spec adalah whitelist field yang boleh dipakai untuk filter dan sort pada satu collection.
+// Parameter yang tidak ada di whitelist diabaikan sehingga tidak bisa dipakai menyusun query bebas.
+type Spec struct {
+	Filters     map[string]Filter
+	Sorts       []string
+	DefaultSort string // misal "-created_at"
+}
+
+// Query adalah hasil parsing query string yang siap dijalankan ke Mongo
+type Query struct {
+	Filter    bson.M
+	SortField string
+	SortDesc  bool
+	Page      int
+	Limit     int
+	Cursor    *Cursor // nil jika memakai pagination berbasis page, ID kosong untuk halaman pertama
+}
+
+// Cursor menunjuk posisi dokumen terakhir halaman sebelumnya (nilai sort dan _id)
+type Cursor struct {
+	Value interface{}        `bson:"v"`
+	ID    primitive.ObjectID `bson:"id"`
+}
+
+// Parse membaca ?page, ?cursor, ?limit, ?sort dan filter yang ada di whitelist spec
+func Parse(c *fiber.Ctx, spec Spec) (*Query, error) {
+	q := &Query{Filter: bson.M{}, Page: 1, Limit: DefaultLimit}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return nil, utils.Invalid("limit", "query.positive_number")
+		}
+		q.Limit = min(limit, MaxLimit)
+	}
+
+	if v := c.Query("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 {
+			return nil, utils.Invalid("page", "query.positive_number")
+		}
+		q.Page = page
+	}
+
+	// ?cursor tanpa nilai memulai pagination berbasis cursor dari halaman pertama
+	if c.Request().URI().QueryArgs().Has("cursor") {
+		if c.Query("page") != "" {
+			return nil, utils.Invalid("cursor", "query.cursor_with_page")
+		}
+		q.Cursor = &Cursor{}
+		if v := c.Query("cursor"); v != "" {
+			cursor, err := DecodeCursor(v)
+			if err != nil {
+				return nil, utils.Invalid("cursor", "query.invalid_cursor")
+			}
+			q.Cursor = cursor
+		}
+	}
+
+	sort := c.Query("sort", spec.DefaultSort)
+	q.SortDesc = strings.HasPrefix(sort, "-")
+	q.SortField = strings.TrimPrefix(sort, "-")
+	if !allowed(spec.Sorts, q.SortField) {
+		return nil, utils.InvalidWith("sort", "validation.oneof", map[string]string{
+			"field": "sort",
+			"param": strings.Join(spec.Sorts, ", "),
+		})
+	}
+
+	for param, f := range spec.Filters {
+		raw := c.Query(param)
+		if raw == "" {
+			continue
+		}
+		cond, err := f.condition(param, raw)
+		if err != nil {
+			return nil, err
+		}
+		// Beberapa parameter boleh menunjuk field yang sama, misal date_from dan date_to
+		if existing, ok := q.Filter[f.Field].(bson.M); ok {
+			for op, v := range cond {
+				existing[op] = v
+			}
+			continue
+		}
+		q.Filter[f.Field] = cond
+	}
+
+	return q, nil
+}
+
+func allowed(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// condition mengubah nilai mentah menjadi kondisi Mongo, misal {"$in": [...]}
+func (f Filter) condition(param, raw string) (bson.M, error) {
+	if f.Op == Prefix {
+		// Nilai di-escape supaya karakter regex dari user tidak ikut dieksekusi
+		return bson.M{"$regex": "^" + regexp.QuoteMeta(raw), "$options": "i"}, nil
+	}
+
+	if f.Op == Eq {
+		parts := strings.Split(raw, ",")
+		values := make([]interface{}, 0, len(parts))
+		for _, part := range parts {
+			v, err := f.parse(param, strings.TrimSpace(part), false)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		if len(values) == 1 {
+			return bson.M{"$eq": values[0]}, nil
+		}
+		return bson.M{"$in": values}, nil
+	}
+
+	v, err := f.parse(param, raw, f.Op == Lte)
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := v.(time.Time); ok && f.Op == Lte && isDateOnly(raw) {
+		// date_to=2025-01-31 mencakup seluruh hari tanggal 31
+		return bson.M{"$lt": t}, nil
+	}
+	return bson.M{string(f.Op): v}, nil
+}
+
+// parse mengubah satu nilai sesuai tipe filter. endOfDay menggeser tanggal tanpa jam ke hari berikutnya.
+func (f Filter) parse(param, raw string, endOfDay bool) (interface{}, error) {
+	switch f.Type {
+	case ObjectID:
+		id, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			return nil, utils.Invalid(param, "validation.objectid")
+		}
+		return id, nil
+	case Time:
+		if isDateOnly(raw) {
+			t, err := time.Parse(time.DateOnly, raw)
+			if err != nil {
+				return nil, utils.Invalid(param, "query.invalid_date")
+			}
+			if endOfDay {
+				t = t.AddDate(0, 0, 1)
+			}
+			return t, nil
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, utils.Invalid(param, "query.invalid_date")
+		}
+		return t, nil
+	case Number:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, utils.Invalid(param, "query.invalid_number")
+		}
+		return n, nil
+	}
+	return raw, nil
+}
+
+func isDateOnly(raw string) bool {
+	return len(raw) == len(time.DateOnly)
+}
+
+// EncodeCursor membuat token cursor opaque dari nilai sort dan _id dokumen terakhir
+func EncodeCursor(cursor Cursor) (string, error) {
+	raw, err := bson.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// ErrCursorValue dikembalikan jika nilai sort di cursor bukan nilai tunggal. Token berasal
+// dari client, jadi dokumen seperti {"$ne": ...} tidak boleh sampai ke filter sebagai operator.
+var ErrCursorValue = errors.New("nilai cursor tidak valid")
+
+// cursorTypes adalah tipe BSON yang boleh menjadi nilai sort di cursor
+var cursorTypes = []bsontype.Type{
+	bson.TypeString, bson.TypeInt32, bson.TypeInt64, bson.TypeDouble, bson.TypeDecimal128,
+	bson.TypeBoolean, bson.TypeDateTime, bson.TypeTimestamp, bson.TypeObjectID, bson.TypeNull,
+}
+
+// DecodeCursor membaca token dari EncodeCursor
+func DecodeCursor(token string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	value, err := bson.Raw(raw).LookupErr("v")
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(cursorTypes, value.Type) {
+		return nil, ErrCursorValue
+	}
+	var cursor Cursor
+	if err := bson.Unmarshal(raw, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"manajemen-fotografi-api/audit"
+	"manajemen-fotografi-api/auth"
+	"manajemen-fotografi-api/logging"
+	"manajemen-fotografi-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AuditCollection adalah collection tempat log audit disimpan
+const AuditCollection = "audit_logs"
+
+// AuditedCollections adalah collection yang setiap create, update dan delete-nya dicatat
+var AuditedCollections = []string{"bookings", "transactions", "galleries", "clients", "photographers"}
+
+// auditCollection mencatat setiap perubahan dokumen ke log audit beserta user, role, IP
+// dan diff field sebelum/sesudah. Dokumen dibaca sebelum dan sesudah operasi, jadi semua
+// jalur perubahan (handler, cascade, restore, purger) tercatat tanpa kode per handler.
+// Kegagalan menulis log hanya dicatat ke log aplikasi dan tidak menggagalkan operasi.
+type auditCollection struct {
+	Collection
+	log Collection
+}
+
+// WithAudit membungkus collection dengan pencatatan audit ke collection log
+func WithAudit(coll, log Collection) Collection {
+	return &auditCollection{Collection: coll, log: log}
+}
+
+func (a *auditCollection) InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	result, err := a.Collection.InsertOne(ctx, document, opts...)
+	if err != nil {
+		return result, err
+	}
+	after, convErr := toM(document)
+	if convErr != nil {
+		a.fail(ctx, convErr)
+		return result, nil
+	}
+	a.record(ctx, models.AuditActionCreate, result.InsertedID, nil, after)
+	return result, nil
+}
+
+func (a *auditCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	before, found := a.findOne(ctx, filter)
+	result, err := a.Collection.UpdateOne(ctx, filter, update, opts...)
+	if err != nil {
+		return result, err
+	}
+	switch {
+	case found && result.ModifiedCount > 0:
+		after, _ := a.findOne(ctx, bson.M{"_id": before["_id"]})
+		a.record(ctx, models.AuditActionUpdate, before["_id"], before, after)
+	case result.UpsertedID != nil:
+		after, _ := a.findOne(ctx, bson.M{"_id": result.UpsertedID})
+		a.record(ctx, models.AuditActionCreate, result.UpsertedID, nil, after)
+	}
+	return result, nil
+}
+
+func (a *auditCollection) UpdateMany(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	before := a.findMany(ctx, filter)
+	result, err := a.Collection.UpdateMany(ctx, filter, update, opts...)
+	if err != nil || result.ModifiedCount == 0 || len(before) == 0 {
+		return result, err
+	}
+
+	after := map[interface{}]bson.M{}
+	for _, doc := range a.findMany(ctx, bson.M{"_id": bson.M{"$in": ids(before)}}) {
+		after[doc["_id"]] = doc
+	}
+	for _, doc := range before {
+		a.record(ctx, models.AuditActionUpdate, doc["_id"], doc, after[doc["_id"]])
+	}
+	return result, nil
+}
+
+func (a *auditCollection) FindOneAndUpdate(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) *mongo.SingleResult {
+	before, found := a.findOne(ctx, filter)
+	result := a.Collection.FindOneAndUpdate(ctx, filter, update, opts...)
+	if found && result.Err() == nil {
+		after, _ := a.findOne(ctx, bson.M{"_id": before["_id"]})
+		a.record(ctx, models.AuditActionUpdate, before["_id"], before, after)
+	}
+	return result
+}
+
+func (a *auditCollection) DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	before, found := a.findOne(ctx, filter)
+	result, err := a.Collection.DeleteOne(ctx, filter, opts...)
+	if err == nil && found && result.DeletedCount > 0 {
+		a.record(ctx, models.AuditActionDelete, before["_id"], before, nil)
+	}
+	return result, err
+}
+
+func (a *auditCollection) DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	before := a.findMany(ctx, filter)
+	result, err := a.Collection.DeleteMany(ctx, filter, opts...)
+	if err == nil && result.DeletedCount > 0 {
+		for _, doc := range before {
+			a.record(ctx, models.AuditActionDelete, doc["_id"], doc, nil)
+		}
+	}
+	return result, err
+}
+
+// record menulis satu catatan audit. Perubahan tanpa field yang berbeda tidak dicatat.
+func (a *auditCollection) record(ctx context.Context, action string, id interface{}, before, after bson.M) {
+	changes := audit.Diff(before, after)
+	if len(changes) == 0 {
+		return
+	}
+	entityID, _ := id.(primitive.ObjectID)
+
+	source := audit.SourceFrom(ctx)
+	entry := models.AuditLog{
+		ID:        primitive.NewObjectID(),
+		Entity:    a.Name(),
+		EntityID:  entityID,
+		Action:    action,
+		IP:        source.IP,
+		RequestID: source.RequestID,
+		At:        time.Now(),
+		Changes:   changes,
+	}
+	if actor, ok := auth.FromContext(ctx); ok {
+		entry.ActorID = &actor.UserID
+		entry.Role = actor.Role
+	}
+
+	// Log audit tetap ditulis walau context request dibatalkan tepat setelah operasi
+	if _, err := a.log.InsertOne(context.WithoutCancel(ctx), entry); err != nil {
+		a.fail(ctx, err)
+	}
+}
+
+func (a *auditCollection) fail(ctx context.Context, err error) {
+	logging.FromContext(ctx).ErrorContext(ctx, "gagal menulis log audit",
+		"collection", a.Name(),
+		"error", err,
+	)
+}
+
+// findOne membaca dokumen sebagai bson.M untuk dibandingkan
+func (a *auditCollection) findOne(ctx context.Context, filter interface{}) (bson.M, bool) {
+	var doc bson.M
+	err := a.Collection.FindOne(ctx, filter).Decode(&doc)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		a.fail(ctx, err)
+	}
+	return doc, err == nil
+}
+
+func (a *auditCollection) findMany(ctx context.Context, filter interface{}) []bson.M {
+	cursor, err := a.Collection.Find(ctx, filter)
+	if err != nil {
+		a.fail(ctx, err)
+		return nil
+	}
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		a.fail(ctx, err)
+		return nil
+	}
+	return docs
+}
+
+func ids(docs []bson.M) bson.A {
+	result := make(bson.A, 0, len(docs))
+	for _, doc := range docs {
+		result = append(result, doc["_id"])
+	}
+	return result
+}
+
+// toM mengubah dokumen (struct atau map) menjadi bson.M dengan nilai yang sama seperti
+// hasil baca dari database, supaya diff create sebanding dengan diff update
+func toM(document interface{}) (bson.M, error) {
+	raw, err := bson.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.M
+	return doc, bson.Unmarshal(raw, &doc)
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+
+	"manajemen-fotografi-api/config"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection adalah subset method *mongo.Collection yang dipakai handler.
+// *mongo.Collection sudah memenuhi interface ini, sehingga decorator
+// (metrics, dsb) bisa dipasang tanpa mengubah kode handler.
+type Collection interface {
+	Name() string
+	Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error)
+	FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult
+	InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error)
+	UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error)
+	UpdateMany(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error)
+	FindOneAndUpdate(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) *mongo.SingleResult
+	DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error)
+	DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error)
+	CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error)
+	Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error)
+}
+
+// New mengambil collection dari database dan membungkusnya dengan decorator standar.
+// Collection yang mendukung trash otomatis menyembunyikan dokumen yang sudah dihapus
+// dan menaikkan version setiap kali diubah. Collection yang diaudit mencatat setiap perubahan.
+func New(name string) Collection {
+	coll := base(name)
+	for _, n := range SoftDeleteCollections {
+		if n == name {
+			coll = WithSoftDelete(WithVersioning(coll))
+			break
+		}
+	}
+	return audited(name, coll)
+}
+
+// Unscoped mengambil collection tanpa soft delete: dokumen di trash ikut terbaca
+// dan DeleteOne/DeleteMany menghapus permanen. Perubahan tetap dicatat ke log audit.
+func Unscoped(name string) Collection {
+	return audited(name, base(name))
+}
+
+func base(name string) Collection {
+	return WithLogging(WithMetrics(config.GetCollection(name)))
+}
+
+// audited memasang pencatatan audit jika name termasuk AuditedCollections
+func audited(name string, coll Collection) Collection {
+	for _, n := range AuditedCollections {
+		if n == name {
+			return WithAudit(coll, base(AuditCollection))
+		}
+	}
+	return coll
+}
@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+
+	"manajemen-fotografi-api/logging"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// loggingCollection mencatat error MongoDB lengkap dengan request ID dari context.
+// Filter dan dokumen tidak ikut dicatat agar data pribadi tidak bocor ke log.
+type loggingCollection struct {
+	Collection
+}
+
+// WithLogging membungkus collection dengan pencatatan error ke log
+func WithLogging(coll Collection) Collection {
+	return &loggingCollection{Collection: coll}
+}
+
+func (l *loggingCollection) log(ctx context.Context, operation string, err error) {
+	if err == nil || err == mongo.ErrNoDocuments {
+		return
+	}
+	logging.FromContext(ctx).ErrorContext(ctx, "operasi mongo gagal",
+		"collection", l.Name(),
+		"operation", operation,
+		"error", err,
+	)
+}
+
+func (l *loggingCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	cursor, err := l.Collection.Find(ctx, filter, opts...)
+	l.log(ctx, "find", err)
+	return cursor, err
+}
+
+func (l *loggingCollection) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult {
+	result := l.Collection.FindOne(ctx, filter, opts...)
+	l.log(ctx, "find_one", result.Err())
+	return result
+}
+
+func (l *loggingCollection) InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	result, err := l.Collection.InsertOne(ctx, document, opts...)
+	l.log(ctx, "insert_one", err)
+	return result, err
+}
+
+func (l *loggingCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	result, err := l.Collection.UpdateOne(ctx, filter, update, opts...)
+	l.log(ctx, "update_one", err)
+	return result, err
+}
+
+func (l *loggingCollection) UpdateMany(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	result, err := l.Collection.UpdateMany(ctx, filter, update, opts...)
+	l.log(ctx, "update_many", err)
+	return result, err
+}
+
+func (l *loggingCollection) FindOneAndUpdate(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) *mongo.SingleResult {
+	result := l.Collection.FindOneAndUpdate(ctx, filter, update, opts...)
+	l.log(ctx, "find_one_and_update", result.Err())
+	return result
+}
+
+func (l *loggingCollection) DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	result, err := l.Collection.DeleteOne(ctx, filter, opts...)
+	l.log(ctx, "delete_one", err)
+	return result, err
+}
+
+func (l *loggingCollection) DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	result, err := l.Collection.DeleteMany(ctx, filter, opts...)
+	l.log(ctx, "delete_many", err)
+	return result, err
+}
+
+func (l *loggingCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	count, err := l.Collection.CountDocuments(ctx, filter, opts...)
+	l.log(ctx, "count_documents", err)
+	return count, err
+}
+
+func (l *loggingCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	cursor, err := l.Collection.Aggregate(ctx, pipeline, opts...)
+	l.log(ctx, "aggregate", err)
+	return cursor, err
+}
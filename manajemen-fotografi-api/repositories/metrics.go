@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"manajemen-fotografi-api/metrics"
+	"manajemen-fotografi-api/models"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// metricsCollection mencatat durasi setiap operasi ke Prometheus
+// dan menghitung event bisnis dari dokumen yang berhasil di-insert.
+type metricsCollection struct {
+	Collection
+}
+
+// WithMetrics membungkus collection dengan pencatatan metrics
+func WithMetrics(coll Collection) Collection {
+	return &metricsCollection{Collection: coll}
+}
+
+func (m *metricsCollection) observe(operation string, start time.Time, err error) {
+	metrics.ObserveMongo(m.Name(), operation, time.Since(start), err)
+}
+
+func (m *metricsCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	start := time.Now()
+	cursor, err := m.Collection.Find(ctx, filter, opts...)
+	m.observe("find", start, err)
+	return cursor, err
+}
+
+func (m *metricsCollection) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult {
+	start := time.Now()
+	result := m.Collection.FindOne(ctx, filter, opts...)
+	err := result.Err()
+	if err == mongo.ErrNoDocuments {
+		err = nil // data tidak ditemukan bukan kegagalan operasi
+	}
+	m.observe("find_one", start, err)
+	return result
+}
+
+func (m *metricsCollection) InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	start := time.Now()
+	result, err := m.Collection.InsertOne(ctx, document, opts...)
+	m.observe("insert_one", start, err)
+	if err == nil {
+		recordInsert(document)
+	}
+	return result, err
+}
+
+func (m *metricsCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	start := time.Now()
+	result, err := m.Collection.UpdateOne(ctx, filter, update, opts...)
+	m.observe("update_one", start, err)
+	return result, err
+}
+
+func (m *metricsCollection) UpdateMany(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	start := time.Now()
+	result, err := m.Collection.UpdateMany(ctx, filter, update, opts...)
+	m.observe("update_many", start, err)
+	return result, err
+}
+
+func (m *metricsCollection) FindOneAndUpdate(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) *mongo.SingleResult {
+	start := time.Now()
+	result := m.Collection.FindOneAndUpdate(ctx, filter, update, opts...)
+	err := result.Err()
+	if err == mongo.ErrNoDocuments {
+		err = nil
+	}
+	m.observe("find_one_and_update", start, err)
+	return result
+}
+
+func (m *metricsCollection) DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	start := time.Now()
+	result, err := m.Collection.DeleteOne(ctx, filter, opts...)
+	m.observe("delete_one", start, err)
+	return result, err
+}
+
+func (m *metricsCollection) DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	start := time.Now()
+	result, err := m.Collection.DeleteMany(ctx, filter, opts...)
+	m.observe("delete_many", start, err)
+	return result, err
+}
+
+func (m *metricsCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	start := time.Now()
+	count, err := m.Collection.CountDocuments(ctx, filter, opts...)
+	m.observe("count_documents", start, err)
+	return count, err
+}
+
+func (m *metricsCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	start := time.Now()
+	cursor, err := m.Collection.Aggregate(ctx, pipeline, opts...)
+	m.observe("aggregate", start, err)
+	return cursor, err
+}
+
+// recordInsert menghitung event bisnis berdasarkan tipe dokumen yang di-insert
+func recordInsert(document interface{}) {
+	switch doc := document.(type) {
+	case models.Booking:
+		metrics.BookingCreated(doc.Status)
+	case *models.Booking:
+		metrics.BookingCreated(doc.Status)
+	case models.Transaction:
+		metrics.PaymentRecorded(doc.Method, doc.Status, doc.Total)
+	case *models.Transaction:
+		metrics.PaymentRecorded(doc.Method, doc.Status, doc.Total)
+	case models.User:
+		metrics.UserRegistered(doc.Role)
+	case *models.User:
+		metrics.UserRegistered(doc.Role)
+	}
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SessionStorage menyimpan session Fiber di MongoDB (memenuhi fiber.Storage),
+// sehingga login tetap berlaku di semua instance dan setelah restart.
+// Dokumen kedaluwarsa dibersihkan oleh TTL index pada expires_at.
+type SessionStorage struct {
+	coll    Collection
+	timeout time.Duration
+}
+
+type sessionDocument struct {
+	Key       string    `bson:"_id"`
+	Data      []byte    `bson:"data"`
+	ExpiresAt time.Time `bson:"expires_at,omitempty"`
+}
+
+// NewSessionStorage membuat storage session di collection "sessions"
+func NewSessionStorage() *SessionStorage {
+	return &SessionStorage{coll: New("sessions"), timeout: 5 * time.Second}
+}
+
+func (s *SessionStorage) Get(key string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	var doc sessionDocument
+	err := s.coll.FindOne(ctx, bson.M{"_id": key}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// TTL index Mongo tidak langsung menghapus, jadi kedaluwarsa dicek di sini juga
+	if !doc.ExpiresAt.IsZero() && doc.ExpiresAt.Before(time.Now()) {
+		return nil, nil
+	}
+	return doc.Data, nil
+}
+
+func (s *SessionStorage) Set(key string, val []byte, exp time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	doc := bson.M{"data": val}
+	if exp > 0 {
+		doc["expires_at"] = time.Now().Add(exp)
+	}
+	_, err := s.coll.UpdateOne(ctx, bson.M{"_id": key}, bson.M{"$set": doc}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (s *SessionStorage) Delete(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	_, err := s.coll.DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
+
+func (s *SessionStorage) Reset() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	_, err := s.coll.DeleteMany(ctx, bson.M{})
+	return err
+}
+
+func (s *SessionStorage) Close() error {
+	return nil
+}
@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"manajemen-fotografi-api/auth"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SoftDeleteCollections adalah collection yang datanya masuk trash saat dihapus
+var SoftDeleteCollections = []string{"bookings", "galleries", "clients", "photographers", "transactions", "messages"}
+
+// NotDeleted adalah filter dokumen yang belum dihapus. deleted_at disimpan null
+// (bukan dihilangkan) supaya bisa dipakai partial index unik.
+var NotDeleted = bson.M{"deleted_at": nil}
+
+// Deleted adalah filter dokumen yang sedang berada di trash
+var Deleted = bson.M{"deleted_at": bson.M{"$ne": nil}}
+
+// softDeleteCollection menyembunyikan dokumen yang sudah dihapus dari semua query,
+// dan mengubah DeleteOne/DeleteMany menjadi pengisian deleted_at dan deleted_by.
+// Penghapusan permanen hanya lewat Unscoped, dipakai restore dan purger trash.
+type softDeleteCollection struct {
+	Collection
+}
+
+type deletedAtKey struct{}
+
+// WithSoftDelete membungkus collection dengan perilaku soft delete
+func WithSoftDelete(coll Collection) Collection {
+	return &softDeleteCollection{Collection: coll}
+}
+
+// WithDeletedAt memakai waktu hapus yang sama untuk beberapa operasi, misal induk dan
+// anak yang ikut terhapus, supaya bisa dipulihkan bersama
+func WithDeletedAt(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, deletedAtKey{}, t)
+}
+
+// scope menambahkan syarat belum dihapus ke filter apapun
+func scope(filter interface{}) interface{} {
+	if filter == nil {
+		return NotDeleted
+	}
+	if m, ok := filter.(bson.M); ok {
+		if _, has := m["deleted_at"]; !has {
+			scoped := bson.M{"deleted_at": nil}
+			for k, v := range m {
+				scoped[k] = v
+			}
+			return scoped
+		}
+	}
+	return bson.M{"$and": bson.A{filter, NotDeleted}}
+}
+
+// markDeleted adalah update yang memindahkan dokumen ke trash
+func markDeleted(ctx context.Context) bson.M {
+	now, ok := ctx.Value(deletedAtKey{}).(time.Time)
+	if !ok {
+		now = time.Now()
+	}
+	set := bson.M{"deleted_at": now, "deleted_by": nil}
+	if actor, ok := auth.FromContext(ctx); ok {
+		set["deleted_by"] = actor.UserID
+	}
+	return bson.M{"$set": set}
+}
+
+func (s *softDeleteCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	return s.Collection.Find(ctx, scope(filter), opts...)
+}
+
+func (s *softDeleteCollection) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult {
+	return s.Collection.FindOne(ctx, scope(filter), opts...)
+}
+
+func (s *softDeleteCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	return s.Collection.UpdateOne(ctx, scope(filter), update, opts...)
+}
+
+func (s *softDeleteCollection) UpdateMany(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	return s.Collection.UpdateMany(ctx, scope(filter), update, opts...)
+}
+
+func (s *softDeleteCollection) FindOneAndUpdate(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) *mongo.SingleResult {
+	return s.Collection.FindOneAndUpdate(ctx, scope(filter), update, opts...)
+}
+
+func (s *softDeleteCollection) DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	result, err := s.Collection.UpdateOne(ctx, scope(filter), markDeleted(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &mongo.DeleteResult{DeletedCount: result.ModifiedCount}, nil
+}
+
+func (s *softDeleteCollection) DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	result, err := s.Collection.UpdateMany(ctx, scope(filter), markDeleted(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &mongo.DeleteResult{DeletedCount: result.ModifiedCount}, nil
+}
+
+func (s *softDeleteCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	return s.Collection.CountDocuments(ctx, scope(filter), opts...)
+}
+
+// Aggregate menambahkan $match di awal pipeline. Pipeline harus berupa mongo.Pipeline atau bson.A.
+func (s *softDeleteCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	match := bson.D{{Key: "$match", Value: NotDeleted}}
+	switch p := pipeline.(type) {
+	case mongo.Pipeline:
+		pipeline = append(mongo.Pipeline{match}, p...)
+	case bson.A:
+		pipeline = append(bson.A{match}, p...)
+	}
+	return s.Collection.Aggregate(ctx, pipeline, opts...)
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrVersionConflict dikembalikan UpdateVersioned jika dokumen sudah diubah orang lain
+var ErrVersionConflict = errors.New("versi dokumen tidak cocok")
+
+// versioningCollection menaikkan field version pada setiap update, sehingga perubahan
+// dari jalur manapun (handler, cascade, soft delete) membuat ETag lama tidak berlaku
+type versioningCollection struct {
+	Collection
+}
+
+// WithVersioning membungkus collection dengan penambahan version otomatis
+func WithVersioning(coll Collection) Collection {
+	return &versioningCollection{Collection: coll}
+}
+
+// bump menambahkan $inc version ke update berbentuk bson.M. Update pipeline dibiarkan.
+func bump(update interface{}) interface{} {
+	m, ok := update.(bson.M)
+	if !ok {
+		return update
+	}
+	bumped := bson.M{}
+	for k, v := range m {
+		bumped[k] = v
+	}
+	inc := bson.M{"version": 1}
+	if existing, ok := m["$inc"].(bson.M); ok {
+		for k, v := range existing {
+			inc[k] = v
+		}
+	}
+	bumped["$inc"] = inc
+	return bumped
+}
+
+func (v *versioningCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	return v.Collection.UpdateOne(ctx, filter, bump(update), opts...)
+}
+
+func (v *versioningCollection) UpdateMany(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	return v.Collection.UpdateMany(ctx, filter, bump(update), opts...)
+}
+
+func (v *versioningCollection) FindOneAndUpdate(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) *mongo.SingleResult {
+	return v.Collection.FindOneAndUpdate(ctx, filter, bump(update), opts...)
+}
+
+// VersionFilter mencocokkan versi dokumen. Dokumen lama tanpa field version dianggap versi 0.
+func VersionFilter(version int64) interface{} {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return version
+}
+
+// UpdateVersioned mengubah dokumen hanya jika versinya masih sama dengan version,
+// lalu men-decode dokumen hasil update ke out. Version negatif (If-Match: *) berarti
+// update tanpa pengecekan versi.
+// Mengembalikan mongo.ErrNoDocuments jika dokumen tidak ada dan ErrVersionConflict
+// jika dokumen ada tetapi sudah berubah.
+func UpdateVersioned(ctx context.Context, coll Collection, id interface{}, version int64, update bson.M, out interface{}) error {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	filter := bson.M{"_id": id}
+	if version >= 0 {
+		filter["version"] = VersionFilter(version)
+	}
+	err := coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(out)
+	if !errors.Is(err, mongo.ErrNoDocuments) || version < 0 {
+		return err
+	}
+
+	count, countErr := coll.CountDocuments(ctx, bson.M{"_id": id})
+	if countErr != nil {
+		return countErr
+	}
+	if count > 0 {
+		return ErrVersionConflict
+	}
+	return mongo.ErrNoDocuments
+}
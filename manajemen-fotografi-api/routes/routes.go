@@ -2,30 +2,57 @@ package routes
 
 import (
 	"manajemen-fotografi-api/handlers"
+	"manajemen-fotografi-api/middlewares"
+	"manajemen-fotografi-api/models"
 
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 func SetupRoutes(app *fiber.App) {
+	// Endpoint untuk di-scrape Prometheus
+	app.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
 	
 	user := app.Group("/api/users")
 	user.Post("/register", handlers.RegisterUser)
 	user.Post("/login", handlers.LoginUser)
 	user.Post("/logout", handlers.LogoutUser)
+	user.Get("/me/notification-preferences", middlewares.RequireAuth(), handlers.GetNotificationPreferences)
+	user.Put("/me/notification-preferences", middlewares.RequireAuth(), handlers.UpdateNotificationPreferences)
 
 	transaction := app.Group("/api/transaction")
 	transaction.Post("/transactions", handlers.CreateDummyTransaction)
 	transaction.Get("/transactions", handlers.GetAllTransactions)
+	transaction.Delete("/transactions/:id", handlers.DeleteTransaction)
+	transaction.Post("/transactions/:id/restore", middlewares.RequireRole(models.RoleAdmin), handlers.RestoreTransaction)
+
+	// Cek potongan kode promo sebelum membayar, kupon dipakai saat transaksi dibuat
+	app.Post("/api/coupons/preview", middlewares.RequireAuth(), handlers.PreviewCoupon)
 
 	
 
 	photographer := app.Group("/photographers")
 	photographer.Post("/", handlers.CreatePhotographer)                 // Create new photographer
+	photographer.Get("/", handlers.GetAllPhotographers)                 // List photographer (filter, sort, pagination)
 	photographer.Get("/:id", handlers.GetPhotographerByID)              // Get photographer by ID
 	photographer.Get("/user/:user_id", handlers.GetPhotographerByUserID) // Get photographer by user ID
-	photographer.Put("/:id", handlers.UpdatePhotographer)               // Update photographer (dengan upload file)
-	photographer.Delete("/:id", handlers.DeletePhotographer)            // Delete photographer
+	photographer.Put("/:id", handlers.UpdatePhotographer)               // Ganti data photographer (dengan upload file)
+	photographer.Patch("/:id", handlers.PatchPhotographer)              // Ubah sebagian field (JSON Merge Patch)
+	photographer.Delete("/:id", handlers.DeletePhotographer)            // Delete photographer (masuk trash)
+	photographer.Post("/:id/restore", middlewares.RequireRole(models.RoleAdmin), handlers.RestorePhotographer) // Pulihkan photographer dari trash (admin)
+
+	// Kalender: feed ICS bertoken untuk aplikasi kalender dan impor jadwal sibuk dari .ics
+	photographer.Post("/:id/calendar-token", middlewares.RequireAuth(), handlers.CreateCalendarToken)
+	photographer.Get("/:id/calendar.ics", handlers.GetPhotographerCalendar)
+	photographer.Get("/:id/busy-times", middlewares.RequireAuth(), handlers.GetBusyTimes)
+	photographer.Post("/:id/busy-times/import", middlewares.RequireAuth(), handlers.ImportBusyTimes)
+	photographer.Get("/:id/analytics/:metric", middlewares.RequireAuth(), handlers.GetPhotographerAnalytics)
+	photographer.Get("/:id/ledger", middlewares.RequireAuth(), handlers.GetPhotographerLedger)
+	photographer.Post("/:id/waitlist", middlewares.RequireAuth(), handlers.JoinWaitlist)
+	photographer.Get("/:id/waitlist", middlewares.RequireAuth(), handlers.GetPhotographerWaitlist)
+	photographer.Get("/:id/ledger/journals", middlewares.RequireAuth(), handlers.GetPhotographerJournals)
         
 
 	// Client routes
@@ -35,7 +62,9 @@ func SetupRoutes(app *fiber.App) {
     client.Get("/:id", handlers.GetClientByID)
     client.Get("/user/:user_id", handlers.GetClientByUserID)
     client.Put("/:id", handlers.UpdateClient)
+    client.Patch("/:id", handlers.PatchClient)
     client.Delete("/:id", handlers.DeleteClient)
+    client.Post("/:id/restore", middlewares.RequireRole(models.RoleAdmin), handlers.RestoreClient)
 
 
 	// Booking Routes
@@ -44,7 +73,33 @@ func SetupRoutes(app *fiber.App) {
 	booking.Get("/:id", handlers.GetBookingByID)
 	booking.Post("/", handlers.CreateBooking)
 	booking.Put("/:id", handlers.UpdateBooking)
+	booking.Patch("/:id", handlers.PatchBooking)
 	booking.Delete("/:id", handlers.DeleteBooking)
+	booking.Post("/:id/restore", middlewares.RequireRole(models.RoleAdmin), handlers.RestoreBooking)
+
+	// Thread pesan booking, hanya untuk client dan fotografer booking tersebut
+	booking.Get("/:id/messages", middlewares.RequireAuth(), handlers.GetBookingMessages)
+	booking.Post("/:id/messages", middlewares.RequireAuth(), handlers.CreateBookingMessage)
+	booking.Post("/:id/messages/read", middlewares.RequireAuth(), handlers.MarkBookingMessagesRead)
+	app.Get("/api/messages/unread", middlewares.RequireAuth(), handlers.GetUnreadMessages)
+
+	// Waitlist milik client yang login; DELETE juga dipakai untuk menolak tawaran
+	app.Get("/api/waitlist", middlewares.RequireAuth(), handlers.GetMyWaitlist)
+	app.Delete("/api/waitlist/:id", middlewares.RequireAuth(), handlers.LeaveWaitlist)
+
+	// Shortlist favorit client; link berbagi dibuka tanpa login dan hanya bisa dibaca.
+	// ?date=YYYY-MM-DD menampilkan ketersediaan fotografer pada tanggal tersebut
+	shortlists := app.Group("/api/shortlists")
+	shortlists.Get("/shared/:token", handlers.GetSharedShortlist)
+	shortlists.Post("/", middlewares.RequireAuth(), handlers.CreateShortlist)
+	shortlists.Get("/", middlewares.RequireAuth(), handlers.GetMyShortlists)
+	shortlists.Get("/:id", middlewares.RequireAuth(), handlers.GetShortlist)
+	shortlists.Put("/:id", middlewares.RequireAuth(), handlers.UpdateShortlist)
+	shortlists.Delete("/:id", middlewares.RequireAuth(), handlers.DeleteShortlist)
+	shortlists.Post("/:id/items", middlewares.RequireAuth(), handlers.AddShortlistItem)
+	shortlists.Delete("/:id/items/:itemId", middlewares.RequireAuth(), handlers.RemoveShortlistItem)
+	shortlists.Post("/:id/share", middlewares.RequireAuth(), handlers.ShareShortlist)
+	shortlists.Delete("/:id/share", middlewares.RequireAuth(), handlers.RevokeShortlistShare)
 
 	// Gallery Routes
 	gallery := app.Group("/api/galleries")
@@ -52,5 +107,52 @@ func SetupRoutes(app *fiber.App) {
 	gallery.Get("/:id", handlers.GetGalleryByID)
 	gallery.Post("/", handlers.CreateGallery)
 	gallery.Put("/:id", handlers.UpdateGallery)
+	gallery.Patch("/:id", handlers.PatchGallery)
 	gallery.Delete("/:id", handlers.DeleteGallery)
+	gallery.Post("/:id/restore", middlewares.RequireRole(models.RoleAdmin), handlers.RestoreGallery)
+
+	// Trash: data yang sudah dihapus dan masih bisa dipulihkan, hanya untuk admin
+	// seperti endpoint restore di setiap entitas
+	app.Get("/api/trash/:collection", middlewares.RequireRole(models.RoleAdmin), handlers.GetTrash)
+
+	// Notifikasi realtime (Server-Sent Events) untuk user yang login
+	app.Get("/api/events/stream", middlewares.RequireAuth(), handlers.StreamEvents)
+
+	// Admin Routes
+	admin := app.Group("/api/admin", middlewares.RequireRole(models.RoleAdmin))
+	admin.Get("/audit-logs", handlers.GetAuditLogs) // Riwayat perubahan (filter entity, actor, rentang waktu)
+
+	// Webhook partner: daftar endpoint, log pengiriman dan dead-letter (?status=dead)
+	admin.Post("/webhooks", handlers.CreateWebhook)
+	admin.Get("/webhooks", handlers.GetAllWebhooks)
+	admin.Delete("/webhooks/:id", handlers.DeleteWebhook)
+	admin.Get("/webhook-deliveries", handlers.GetWebhookDeliveries)
+	admin.Post("/webhook-deliveries/:id/retry", handlers.RetryWebhookDelivery)
+
+	// Job background: pengingat booking, penutupan status dan kedaluwarsa pembayaran
+	admin.Get("/jobs", handlers.GetJobs)
+	admin.Post("/jobs/:id/retry", handlers.RetryJob)
+
+	// Analitik dashboard: ?from, ?to, ?tz dan ?limit, hasil di-cache sebentar
+	admin.Get("/analytics", handlers.GetAnalyticsMetrics)
+	admin.Get("/analytics/:metric", handlers.GetAnalytics)
+
+	// Buku besar pendapatan fotografer: tarif komisi, refund, koreksi dan batch payout
+	admin.Get("/commission-rates", handlers.GetCommissionRates)
+	admin.Put("/commission-rates/default", handlers.SetDefaultCommissionRate)
+	admin.Put("/commission-rates/photographers/:id", handlers.SetPhotographerCommissionRate)
+	admin.Delete("/commission-rates/photographers/:id", handlers.DeletePhotographerCommissionRate)
+	admin.Post("/ledger/refunds", handlers.CreateRefund)
+	admin.Post("/ledger/adjustments", handlers.CreateAdjustment)
+	admin.Post("/payouts", handlers.CreatePayoutBatch)
+	admin.Get("/payouts", handlers.GetPayoutBatches)
+	admin.Get("/payouts/:id", handlers.GetPayoutBatch)
+	admin.Get("/payouts/:id/export.csv", handlers.ExportPayoutBatch)
+
+	// Kode promo dan riwayat pemakaiannya
+	admin.Post("/coupons", handlers.CreateCoupon)
+	admin.Get("/coupons", handlers.GetAllCoupons)
+	admin.Get("/coupons/:id", handlers.GetCouponByID)
+	admin.Put("/coupons/:id", handlers.UpdateCoupon)
+	admin.Get("/coupons/:id/redemptions", handlers.GetCouponRedemptions)
 }
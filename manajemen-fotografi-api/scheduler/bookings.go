@@ -0,0 +1,223 @@
+package scheduler
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"manajemen-fotografi-api/events"
+	"manajemen-fotografi-api/logging"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/notifications"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Nama job booking
+const (
+	JobPlanReminders = "bookings.plan_reminders"
+	JobSendReminder  = "bookings.send_reminder"
+	JobClosePast     = "bookings.close_past"
+	JobExpireUnpaid  = "bookings.expire_unpaid"
+)
+
+// Default aturan booking, bisa diganti lewat SCHEDULER_SWEEP_INTERVAL, BOOKING_PAYMENT_TIMEOUT
+// dan BOOKING_CLOSE_AFTER
+const (
+	DefaultSweepInterval  = 10 * time.Minute
+	DefaultPaymentTimeout = 24 * time.Hour
+	DefaultCloseAfter     = 12 * time.Hour
+)
+
+// reminderOffsets adalah jarak pengingat sebelum tanggal booking (H-7 dan H-1)
+var reminderOffsets = []int{7, 1}
+
+// reminderGrace adalah batas keterlambatan pengingat; pengingat yang terlewat lebih lama
+// (misal booking dibuat 3 hari sebelum sesi foto untuk H-7) tidak dikirim
+const reminderGrace = 6 * time.Hour
+
+// sweepBatch membatasi jumlah booking yang diproses satu putaran job berulang
+const sweepBatch = 200
+
+// reminderPayload adalah payload job bookings.send_reminder
+type reminderPayload struct {
+	BookingID primitive.ObjectID `bson:"booking_id"`
+	Date      time.Time          `bson:"date"`
+	Days      int                `bson:"days"`
+}
+
+// PaymentTimeout membaca BOOKING_PAYMENT_TIMEOUT, lama booking pending boleh belum dibayar
+func PaymentTimeout() time.Duration {
+	return durationFromEnv("BOOKING_PAYMENT_TIMEOUT", DefaultPaymentTimeout)
+}
+
+// CloseAfter membaca BOOKING_CLOSE_AFTER, jeda setelah tanggal booking sebelum status ditutup
+func CloseAfter() time.Duration {
+	return durationFromEnv("BOOKING_CLOSE_AFTER", DefaultCloseAfter)
+}
+
+func registerBookingJobs(ctx context.Context) {
+	Register(JobPlanReminders, func(ctx context.Context, job models.Job) error {
+		return PlanReminders(ctx, time.Now())
+	})
+	Register(JobSendReminder, sendReminder)
+	Register(JobClosePast, func(ctx context.Context, job models.Job) error {
+		return ClosePast(ctx, time.Now())
+	})
+	Register(JobExpireUnpaid, func(ctx context.Context, job models.Job) error {
+		return ExpireUnpaid(ctx, time.Now())
+	})
+
+	every := durationFromEnv("SCHEDULER_SWEEP_INTERVAL", DefaultSweepInterval)
+	for _, name := range []string{JobPlanReminders, JobClosePast, JobExpireUnpaid} {
+		if err := Every(ctx, name, every); err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "gagal mendaftarkan job berulang", "job", name, "error", err)
+		}
+	}
+}
+
+// ReminderRunAt mengembalikan waktu kirim pengingat H-days untuk booking pada date, dan
+// false jika pengingat itu sudah terlewat terlalu lama pada now
+func ReminderRunAt(date time.Time, days int, now time.Time) (time.Time, bool) {
+	runAt := date.AddDate(0, 0, -days)
+	if now.After(runAt.Add(reminderGrace)) || !now.Before(date) {
+		return time.Time{}, false
+	}
+	return runAt, true
+}
+
+// PlanReminders menjadwalkan job pengingat H-7 dan H-1 untuk booking terkonfirmasi yang
+// sesi fotonya dalam 7 hari ke depan (ditambah satu putaran supaya tidak ada yang terlewat).
+// Key job memuat tanggal booking, sehingga booking yang dijadwal ulang mendapat pengingat baru.
+func PlanReminders(ctx context.Context, now time.Time) error {
+	horizon := now.AddDate(0, 0, reminderOffsets[0]).Add(durationFromEnv("SCHEDULER_SWEEP_INTERVAL", DefaultSweepInterval))
+	cursor, err := collection("bookings").Find(ctx,
+		bson.M{
+			"status": models.BookingStatusConfirmed,
+			"date":   bson.M{"$gt": now, "$lte": horizon},
+		},
+		options.Find().SetProjection(bson.M{"date": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	var bookings []models.Booking
+	if err := cursor.All(ctx, &bookings); err != nil {
+		return err
+	}
+
+	for _, booking := range bookings {
+		for _, days := range reminderOffsets {
+			runAt, ok := ReminderRunAt(booking.Date, days, now)
+			if !ok {
+				continue
+			}
+			key := "reminder:" + booking.ID.Hex() + ":" + strconv.Itoa(days) + ":" + strconv.FormatInt(booking.Date.Unix(), 10)
+			payload := reminderPayload{BookingID: booking.ID, Date: booking.Date, Days: days}
+			if err := Schedule(ctx, JobSendReminder, key, runAt, payload); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sendReminder mengirim pengingat jika booking masih terkonfirmasi dan tanggalnya belum berubah
+func sendReminder(ctx context.Context, job models.Job) error {
+	var payload reminderPayload
+	if err := DecodePayload(job, &payload); err != nil {
+		return err
+	}
+
+	var booking models.Booking
+	err := collection("bookings").FindOne(ctx, bson.M{"_id": payload.BookingID}).Decode(&booking)
+	if err != nil {
+		return ignoreNotFound(err) // booking sudah dihapus
+	}
+	if booking.Status != models.BookingStatusConfirmed || !booking.Date.Equal(payload.Date) {
+		return nil
+	}
+
+	params := notifications.BookingParams(booking)
+	params["days"] = strconv.Itoa(payload.Days)
+	return notifications.NotifyUsers(ctx, bookingRecipients(ctx, booking), notifications.BookingReminder, params, job.Key)
+}
+
+// ClosePast menutup booking yang tanggalnya sudah lewat CloseAfter: booking terkonfirmasi
+// (sudah dibayar) menjadi done, booking yang masih pending menjadi no_show
+func ClosePast(ctx context.Context, now time.Time) error {
+	cutoff := now.Add(-CloseAfter())
+	if err := transition(ctx, bson.M{"date": bson.M{"$lt": cutoff}},
+		models.BookingStatusConfirmed, models.BookingStatusDone); err != nil {
+		return err
+	}
+	return transition(ctx, bson.M{"date": bson.M{"$lt": cutoff}},
+		models.BookingStatusPending, models.BookingStatusNoShow)
+}
+
+// ExpireUnpaid membatalkan booking pending yang belum dibayar lebih dari PaymentTimeout
+// sejak dibuat, supaya jadwal fotografer kembali kosong
+func ExpireUnpaid(ctx context.Context, now time.Time) error {
+	return transition(ctx, bson.M{"created_at": bson.M{"$lt": now.Add(-PaymentTimeout())}},
+		models.BookingStatusPending, models.BookingStatusCancelled)
+}
+
+// transition mengubah status booking yang cocok filter dari status from ke to satu per satu.
+// Status lama ikut di filter update, jadi booking yang baru saja diubah user (misal
+// dibayar) tidak ikut tertimpa. Setiap perubahan dikirim sebagai booking.status_changed.
+func transition(ctx context.Context, filter bson.M, from, to string) error {
+	filter["status"] = from
+	cursor, err := collection("bookings").Find(ctx, filter,
+		options.Find().SetProjection(bson.M{"_id": 1}).SetLimit(sweepBatch),
+	)
+	if err != nil {
+		return err
+	}
+	var ids []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &ids); err != nil {
+		return err
+	}
+
+	for _, doc := range ids {
+		var booking models.Booking
+		err := collection("bookings").FindOneAndUpdate(ctx,
+			bson.M{"_id": doc.ID, "status": from},
+			bson.M{"$set": bson.M{"status": to, "updated_at": time.Now()}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&booking)
+		if err != nil {
+			if ignoreNotFound(err) == nil {
+				continue
+			}
+			return err
+		}
+		events.Publish(ctx, events.BookingStatusChanged, bookingRecipients(ctx, booking), map[string]interface{}{
+			"booking": booking,
+			"from":    from,
+			"to":      to,
+		})
+	}
+	return nil
+}
+
+// bookingRecipients adalah user client dan fotografer pada booking, sama seperti
+// penerima event booking dari handler
+func bookingRecipients(ctx context.Context, booking models.Booking) []primitive.ObjectID {
+	var recipients []primitive.ObjectID
+	for name, id := range map[string]primitive.ObjectID{"clients": booking.ClientID, "photographers": booking.PhotographerID} {
+		var profile struct {
+			UserID primitive.ObjectID `bson:"user_id"`
+		}
+		err := collection(name).FindOne(ctx, bson.M{"_id": id},
+			options.FindOne().SetProjection(bson.M{"user_id": 1}),
+		).Decode(&profile)
+		if err == nil {
+			recipients = append(recipients, profile.UserID)
+		}
+	}
+	return recipients
+}
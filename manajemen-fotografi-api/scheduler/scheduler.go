@@ -0,0 +1,308 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"manajemen-fotografi-api/logging"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection menyimpan semua job
+const Collection = "jobs"
+
+// Default scheduler, bisa diganti lewat SCHEDULER_POLL_INTERVAL
+const (
+	DefaultPollInterval = 10 * time.Second
+	DefaultMaxAttempts  = 5
+)
+
+// lease adalah lama job dikunci instance yang menjalankannya. Job yang kuncinya kedaluwarsa
+// (instance mati di tengah jalan) diambil lagi oleh instance lain.
+const lease = 5 * time.Minute
+
+// Batas backoff antar percobaan job yang gagal
+const (
+	baseBackoff = 30 * time.Second
+	maxBackoff  = time.Hour
+)
+
+// Handler menjalankan satu job. Error membuat job dicoba lagi dengan backoff.
+type Handler func(ctx context.Context, job models.Job) error
+
+var (
+	mu       sync.RWMutex
+	handlers = map[string]Handler{}
+)
+
+// collection diambil saat dipakai, bukan saat package di-load,
+// supaya aturan job bisa diuji tanpa koneksi database
+var collection = repositories.New
+
+// instanceID menandai replica yang mengunci job, untuk ditampilkan di admin
+var instanceID = newInstanceID()
+
+func newInstanceID() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%s", host, primitive.NewObjectID().Hex()[18:])
+}
+
+// Register mendaftarkan handler untuk job bernama name
+func Register(name string, handler Handler) {
+	mu.Lock()
+	defer mu.Unlock()
+	handlers[name] = handler
+}
+
+func handlerFor(name string) (Handler, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	h, ok := handlers[name]
+	return h, ok
+}
+
+// Backoff adalah jeda sebelum percobaan berikutnya: 30 detik, 1 menit, 2 menit, maksimal 1 jam
+func Backoff(attempt int) time.Duration {
+	d := baseBackoff
+	for i := 1; i < attempt && d < maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxBackoff)
+}
+
+// Schedule menjadwalkan job sekali jalan pada runAt. Job dengan key yang sudah ada diabaikan,
+// sehingga aman dipanggil berulang kali oleh beberapa replica.
+func Schedule(ctx context.Context, name, key string, runAt time.Time, payload interface{}) error {
+	doc, err := toM(payload)
+	if err != nil {
+		return err
+	}
+	_, err = collection(Collection).InsertOne(ctx, models.Job{
+		ID:          primitive.NewObjectID(),
+		Name:        name,
+		Key:         key,
+		Payload:     doc,
+		Status:      models.JobScheduled,
+		RunAt:       runAt,
+		MaxAttempts: DefaultMaxAttempts,
+		CreatedAt:   time.Now(),
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+// Every memastikan job berulang bernama name terdaftar dengan interval every.
+// Hanya satu dokumen per nama, jadi setiap putaran dijalankan satu replica saja.
+func Every(ctx context.Context, name string, every time.Duration) error {
+	now := time.Now()
+	_, err := collection(Collection).UpdateOne(ctx,
+		bson.M{"key": "every:" + name},
+		bson.M{
+			"$set": bson.M{"every": every},
+			"$setOnInsert": bson.M{
+				"name":         name,
+				"status":       models.JobScheduled,
+				"run_at":       now,
+				"attempts":     0,
+				"max_attempts": DefaultMaxAttempts,
+				"locked_until": nil,
+				"created_at":   now,
+			},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil // replica lain mendaftarkannya bersamaan
+	}
+	return err
+}
+
+func pollInterval() time.Duration {
+	return durationFromEnv("SCHEDULER_POLL_INTERVAL", DefaultPollInterval)
+}
+
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+func intFromEnv(key string, fallback int) int {
+	n, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || n < 1 {
+		return fallback
+	}
+	return n
+}
+
+// Start mendaftarkan job bawaan lalu menjalankan job yang jatuh tempo di background
+// sampai ctx dibatalkan
+func Start(ctx context.Context) {
+	registerBookingJobs(ctx)
+
+	go func() {
+		ticker := time.NewTicker(pollInterval())
+		defer ticker.Stop()
+		for {
+			RunDue(ctx, time.Now())
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RunDue menjalankan job yang jatuh tempo pada now satu per satu sampai tidak ada lagi.
+// Mengembalikan jumlah job yang dijalankan.
+func RunDue(ctx context.Context, now time.Time) int {
+	logger := logging.FromContext(ctx)
+	jobs := collection(Collection)
+
+	ran := 0
+	for ctx.Err() == nil {
+		// Job running yang kuncinya kedaluwarsa ikut diambil, instance sebelumnya dianggap mati
+		var job models.Job
+		err := jobs.FindOneAndUpdate(ctx,
+			bson.M{
+				"run_at": bson.M{"$lte": now},
+				"$or": bson.A{
+					bson.M{"status": models.JobScheduled},
+					bson.M{"status": models.JobRunning, "locked_until": bson.M{"$lt": now}},
+				},
+			},
+			bson.M{"$set": bson.M{
+				"status":       models.JobRunning,
+				"locked_by":    instanceID,
+				"locked_until": now.Add(lease),
+			}},
+			options.FindOneAndUpdate().SetSort(bson.D{{Key: "run_at", Value: 1}}).SetReturnDocument(options.After),
+		).Decode(&job)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			logger.ErrorContext(ctx, "gagal mengambil job", "error", err)
+			break
+		}
+
+		if err := run(ctx, job); err != nil {
+			logger.ErrorContext(ctx, "gagal menyimpan hasil job", "job_id", job.ID.Hex(), "name", job.Name, "error", err)
+		}
+		ran++
+	}
+	return ran
+}
+
+// run menjalankan satu job lalu mencatat hasilnya; job berulang dijadwalkan ulang
+func run(ctx context.Context, job models.Job) error {
+	logger := logging.FromContext(ctx).With("job_id", job.ID.Hex(), "job", job.Name)
+
+	handler, ok := handlerFor(job.Name)
+	var err error
+	if !ok {
+		err = fmt.Errorf("handler job %q tidak terdaftar", job.Name)
+	} else {
+		jobCtx, cancel := context.WithTimeout(ctx, lease)
+		err = safeRun(jobCtx, handler, job)
+		cancel()
+	}
+
+	now := time.Now()
+	set := bson.M{"locked_by": "", "locked_until": nil}
+	attempts := job.Attempts + 1
+	switch {
+	case err == nil && job.Every > 0:
+		set["status"] = models.JobScheduled
+		set["run_at"] = now.Add(job.Every)
+		set["attempts"] = 0
+		set["last_error"] = ""
+		set["finished_at"] = now
+	case err == nil:
+		set["status"] = models.JobDone
+		set["attempts"] = attempts
+		set["last_error"] = ""
+		set["finished_at"] = now
+	case job.Every > 0:
+		// Job berulang tidak pernah berhenti, putaran berikutnya dicoba lebih cepat
+		set["status"] = models.JobScheduled
+		set["run_at"] = now.Add(min(Backoff(attempts), job.Every))
+		set["attempts"] = attempts
+		set["last_error"] = err.Error()
+	case attempts >= max(job.MaxAttempts, 1):
+		set["status"] = models.JobFailed
+		set["attempts"] = attempts
+		set["last_error"] = err.Error()
+		set["finished_at"] = now
+	default:
+		set["status"] = models.JobScheduled
+		set["run_at"] = now.Add(Backoff(attempts))
+		set["attempts"] = attempts
+		set["last_error"] = err.Error()
+	}
+	if err != nil {
+		logger.WarnContext(ctx, "job gagal", "attempt", attempts, "error", err)
+	}
+
+	// Hanya instance pemegang kunci yang boleh mencatat hasil
+	_, updateErr := collection(Collection).UpdateOne(ctx,
+		bson.M{"_id": job.ID, "locked_by": instanceID},
+		bson.M{"$set": set},
+	)
+	return updateErr
+}
+
+// safeRun mengubah panic di handler menjadi error supaya scheduler tetap berjalan
+func safeRun(ctx context.Context, handler Handler, job models.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+// DecodePayload membaca payload job ke struct
+func DecodePayload(job models.Job, out interface{}) error {
+	raw, err := bson.Marshal(job.Payload)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(raw, out)
+}
+
+func toM(payload interface{}) (bson.M, error) {
+	if payload == nil {
+		return nil, nil
+	}
+	raw, err := bson.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.M
+	return doc, bson.Unmarshal(raw, &doc)
+}
+
+// ignoreNotFound menganggap dokumen yang sudah tidak ada sebagai bukan kegagalan
+func ignoreNotFound(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil
+	}
+	return err
+}
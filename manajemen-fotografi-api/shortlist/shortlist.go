@@ -0,0 +1,233 @@
+// Package shortlist mengelola daftar favorit client: fotografer dan gambar galeri yang
+// disimpan ke shortlist bernama, link berbagi read-only untuk pasangan atau keluarga,
+// dan jumlah client yang memfavoritkan fotografer sebagai bahan peringkat pencarian.
+package shortlist
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"manajemen-fotografi-api/logging"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
+	"manajemen-fotografi-api/waitlist"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection menyimpan shortlist client
+const Collection = "shortlists"
+
+// MaxItems membatasi jumlah item dalam satu shortlist
+const MaxItems = 100
+
+// collection diambil saat dipakai, bukan saat package di-load,
+// supaya aturan shortlist bisa diuji tanpa koneksi database
+var collection = repositories.New
+
+// NewToken membuat token link berbagi beserta hash yang disimpan di database
+func NewToken() (token, hash string, err error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(raw)
+	return token, HashToken(token), nil
+}
+
+// HashToken menghitung hash SHA-256 token link berbagi
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// PhotographerIDs mengembalikan fotografer unik dari items sesuai urutan kemunculan.
+// Jika kind diisi, hanya item dengan jenis tersebut yang dihitung.
+func PhotographerIDs(items []models.ShortlistItem, kind string) []primitive.ObjectID {
+	seen := map[primitive.ObjectID]bool{}
+	var ids []primitive.ObjectID
+	for _, item := range items {
+		if (kind != "" && item.Kind != kind) || seen[item.PhotographerID] {
+			continue
+		}
+		seen[item.PhotographerID] = true
+		ids = append(ids, item.PhotographerID)
+	}
+	return ids
+}
+
+// sameItem adalah filter $elemMatch untuk item yang menunjuk objek yang sama
+func sameItem(item models.ShortlistItem) bson.M {
+	if item.Kind == models.ShortlistGalleryImage {
+		return bson.M{"kind": item.Kind, "gallery_id": item.GalleryID}
+	}
+	return bson.M{"kind": item.Kind, "photographer_id": item.PhotographerID}
+}
+
+// AddItem menambahkan item ke shortlist milik client. Item yang sudah ada ditolak dan
+// shortlist yang penuh ditolak dengan error validasi.
+func AddItem(ctx context.Context, id, clientID primitive.ObjectID, item models.ShortlistItem, now time.Time) (models.Shortlist, error) {
+	item.ID = primitive.NewObjectID()
+	item.AddedAt = now
+
+	var list models.Shortlist
+	err := collection(Collection).FindOneAndUpdate(ctx, bson.M{
+		"_id":                               id,
+		"client_id":                         clientID,
+		"items." + strconv.Itoa(MaxItems-1): bson.M{"$exists": false},
+		"items":                             bson.M{"$not": bson.M{"$elemMatch": sameItem(item)}},
+	}, bson.M{
+		"$push": bson.M{"items": item},
+		"$set":  bson.M{"updated_at": now},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&list)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return list, addItemConflict(ctx, id, clientID)
+	}
+	if err != nil {
+		return list, err
+	}
+
+	if item.Kind == models.ShortlistPhotographer {
+		RefreshCounts(ctx, item.PhotographerID)
+	}
+	return list, nil
+}
+
+// addItemConflict mencari tahu kenapa AddItem tidak mengubah dokumen apa pun
+func addItemConflict(ctx context.Context, id, clientID primitive.ObjectID) error {
+	var list models.Shortlist
+	err := collection(Collection).FindOne(ctx, bson.M{"_id": id, "client_id": clientID}).Decode(&list)
+	if err != nil {
+		return utils.NotFoundOr(err, utils.ErrShortlistNotFound)
+	}
+	if len(list.Items) >= MaxItems {
+		return utils.InvalidWith("items", "shortlist.full", map[string]string{"field": "items", "param": strconv.Itoa(MaxItems)})
+	}
+	return utils.ErrShortlistItemExists
+}
+
+// RemoveItem menghapus satu item dari shortlist milik client
+func RemoveItem(ctx context.Context, id, clientID, itemID primitive.ObjectID, now time.Time) (models.Shortlist, error) {
+	var before models.Shortlist
+	err := collection(Collection).FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "client_id": clientID, "items._id": itemID},
+		bson.M{
+			"$pull": bson.M{"items": bson.M{"_id": itemID}},
+			"$set":  bson.M{"updated_at": now},
+		},
+	).Decode(&before)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if n, err := collection(Collection).CountDocuments(ctx, bson.M{"_id": id, "client_id": clientID}); err != nil || n == 0 {
+			return before, utils.NotFoundOr(err, utils.ErrShortlistNotFound)
+		}
+		return before, utils.ErrShortlistItemNotFound
+	}
+	if err != nil {
+		return before, err
+	}
+
+	list := before
+	list.Items = nil
+	var removed models.ShortlistItem
+	for _, item := range before.Items {
+		if item.ID == itemID {
+			removed = item
+			continue
+		}
+		list.Items = append(list.Items, item)
+	}
+	list.UpdatedAt = now
+
+	if removed.Kind == models.ShortlistPhotographer {
+		RefreshCounts(ctx, removed.PhotographerID)
+	}
+	return list, nil
+}
+
+// Delete menghapus shortlist milik client lalu menghitung ulang favorit fotografernya
+func Delete(ctx context.Context, id, clientID primitive.ObjectID) error {
+	var list models.Shortlist
+	filter := bson.M{"_id": id, "client_id": clientID}
+	if err := collection(Collection).FindOne(ctx, filter).Decode(&list); err != nil {
+		return utils.NotFoundOr(err, utils.ErrShortlistNotFound)
+	}
+	res, err := collection(Collection).DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return utils.ErrShortlistNotFound
+	}
+
+	RefreshCounts(ctx, PhotographerIDs(list.Items, models.ShortlistPhotographer)...)
+	return nil
+}
+
+// FavoriteCount menghitung client berbeda yang menyimpan fotografer di salah satu shortlist-nya
+func FavoriteCount(ctx context.Context, photographerID primitive.ObjectID) (int, error) {
+	cursor, err := collection(Collection).Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"items": bson.M{"$elemMatch": bson.M{
+			"kind":            models.ShortlistPhotographer,
+			"photographer_id": photographerID,
+		}}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$client_id"}}},
+		{{Key: "$count", Value: "clients"}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var out []struct {
+		Clients int `bson:"clients"`
+	}
+	if err := cursor.All(ctx, &out); err != nil || len(out) == 0 {
+		return 0, err
+	}
+	return out[0].Clients, nil
+}
+
+// RefreshCounts menulis ulang favorite_count fotografer dari isi shortlist. Dihitung ulang,
+// bukan $inc, supaya item ganda di beberapa shortlist satu client tetap dihitung satu.
+// Kegagalan hanya dicatat karena angka ini sekadar bahan peringkat.
+func RefreshCounts(ctx context.Context, photographerIDs ...primitive.ObjectID) {
+	// Unscoped supaya version fotografer tidak naik: favorite_count bukan perubahan profil
+	photographers := repositories.Unscoped("photographers")
+	for _, id := range photographerIDs {
+		count, err := FavoriteCount(ctx, id)
+		if err == nil {
+			_, err = photographers.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"favorite_count": count}})
+		}
+		if err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "gagal memperbarui favorite_count", "photographer_id", id.Hex(), "error", err)
+		}
+	}
+}
+
+// Availability mengisi Available pada setiap item untuk tanggal day. Fotografer dianggap
+// tersedia jika belum punya booking aktif atau jadwal sibuk pada hari tersebut.
+func Availability(ctx context.Context, items []models.ShortlistItem, day time.Time) error {
+	free := map[primitive.ObjectID]bool{}
+	for _, id := range PhotographerIDs(items, "") {
+		full, err := waitlist.Full(ctx, id, day)
+		if err != nil {
+			return err
+		}
+		free[id] = !full
+	}
+	for i := range items {
+		available := free[items[i].PhotographerID]
+		items[i].Available = &available
+	}
+	return nil
+}
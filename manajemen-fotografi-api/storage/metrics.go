@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"context"
+	"mime/multipart"
+	"time"
+
+	"manajemen-fotografi-api/metrics"
+)
+
+// metricsStorage mencatat ukuran dan durasi setiap upload
+type metricsStorage struct {
+	Storage
+}
+
+// WithMetrics membungkus storage dengan pencatatan metrics upload
+func WithMetrics(s Storage) Storage {
+	return &metricsStorage{Storage: s}
+}
+
+func (m *metricsStorage) Save(ctx context.Context, kind string, file *multipart.FileHeader) (string, error) {
+	start := time.Now()
+	url, err := m.Storage.Save(ctx, kind, file)
+	metrics.ObserveUpload(kind, file.Size, time.Since(start), err)
+	return url, err
+}
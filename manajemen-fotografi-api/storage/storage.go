@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Storage menyimpan file upload dan mengembalikan URL path untuk diakses client
+type Storage interface {
+	Save(ctx context.Context, kind string, file *multipart.FileHeader) (string, error)
+	// Delete menghapus file berdasarkan URL dari Save. URL di luar storage ini
+	// dan file yang sudah tidak ada diabaikan.
+	Delete(ctx context.Context, url string) error
+}
+
+// LocalStorage menyimpan file ke folder lokal (default ./uploads)
+type LocalStorage struct {
+	Dir     string // folder tujuan di disk
+	URLPath string // prefix URL, misal "/uploads"
+}
+
+// NewLocalStorage membuat storage lokal, folder dibuat saat file pertama disimpan
+func NewLocalStorage(dir, urlPath string) *LocalStorage {
+	return &LocalStorage{Dir: dir, URLPath: urlPath}
+}
+
+// Save menyalin file upload ke folder lokal dengan nama berprefix timestamp
+func (s *LocalStorage) Save(ctx context.Context, kind string, file *multipart.FileHeader) (string, error) {
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return "", err
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	filename := fmt.Sprintf("%d_%s", time.Now().Unix(), filepath.Base(file.Filename))
+	dst, err := os.Create(filepath.Join(s.Dir, filename))
+	if err != nil {
+		return "", err
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s/%s", s.URLPath, filename), nil
+}
+
+// Delete menghapus file lokal yang URL-nya berada di bawah URLPath
+func (s *LocalStorage) Delete(ctx context.Context, url string) error {
+	name, ok := strings.CutPrefix(url, s.URLPath+"/")
+	if !ok || name == "" {
+		return nil
+	}
+	// Base mencegah URL seperti /uploads/../main.go keluar dari folder upload
+	err := os.Remove(filepath.Join(s.Dir, filepath.Base(name)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Default adalah storage yang dipakai handler
+var Default Storage = WithTracing(WithMetrics(NewLocalStorage("./uploads", "/uploads")))
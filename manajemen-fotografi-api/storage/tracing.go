@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"mime/multipart"
+
+	"manajemen-fotografi-api/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// tracingStorage membuat span untuk setiap operasi penyimpanan file
+type tracingStorage struct {
+	Storage
+}
+
+// WithTracing membungkus storage dengan span OpenTelemetry
+func WithTracing(s Storage) Storage {
+	return &tracingStorage{Storage: s}
+}
+
+func (t *tracingStorage) Save(ctx context.Context, kind string, file *multipart.FileHeader) (string, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "storage.save")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("storage.kind", kind),
+		attribute.Int64("storage.size", file.Size),
+	)
+
+	url, err := t.Storage.Save(ctx, kind, file)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return url, err
+	}
+
+	span.SetAttributes(attribute.String("storage.url", url))
+	return url, nil
+}
+
+func (t *tracingStorage) Delete(ctx context.Context, url string) error {
+	ctx, span := tracing.Tracer().Start(ctx, "storage.delete")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("storage.url", url))
+
+	if err := t.Storage.Delete(ctx, url); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"manajemen-fotografi-api/analytics"
+	"manajemen-fotografi-api/handlers"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func analyticsParams(t *testing.T, zone string) analytics.Params {
+	loc, err := time.LoadLocation(zone)
+	require.NoError(t, err)
+	return analytics.Params{
+		From:     time.Date(2025, 1, 1, 0, 0, 0, 0, loc),
+		To:       time.Date(2025, 4, 1, 0, 0, 0, 0, loc),
+		Location: loc,
+	}
+}
+
+func TestAnalyticsMetricsRegistered(t *testing.T) {
+	assert.Equal(t, []string{
+		"booking_status", "cancellation_rate", "conversion", "lead_time",
+		"new_clients_weekly", "revenue_by_photographer", "revenue_monthly", "top_locations",
+	}, analytics.Names())
+
+	_, err := analytics.Pipeline("tidak_ada", analyticsParams(t, "UTC"))
+	assert.ErrorIs(t, err, analytics.ErrUnknownMetric)
+}
+
+func TestAnalyticsRevenueGroupsByMonthInTimezone(t *testing.T) {
+	p := analyticsParams(t, "Asia/Jakarta")
+	pipeline, err := analytics.Pipeline("revenue_monthly", p)
+	require.NoError(t, err)
+
+	match := pipeline[0].Map()["$match"].(bson.M)
+	assert.Equal(t, "paid", match["status"])
+	assert.Equal(t, bson.M{"$gte": p.From, "$lt": p.To}, match["created_at"])
+
+	group := pipeline[3].Map()["$group"].(bson.M)
+	month := group["_id"].(bson.M)["$dateToString"].(bson.M)
+	assert.Equal(t, "%Y-%m", month["format"])
+	assert.Equal(t, "Asia/Jakarta", month["timezone"])
+}
+
+func TestAnalyticsPhotographerScope(t *testing.T) {
+	id := primitive.NewObjectID()
+	p := analyticsParams(t, "UTC")
+
+	// Tanpa fotografer, laporan booking tidak disaring per fotografer
+	pipeline, err := analytics.Pipeline("booking_status", p)
+	require.NoError(t, err)
+	assert.NotContains(t, pipeline[0].Map()["$match"].(bson.M), "photographer_id")
+
+	p.PhotographerID = &id
+	pipeline, err = analytics.Pipeline("booking_status", p)
+	require.NoError(t, err)
+	assert.Equal(t, id, pipeline[0].Map()["$match"].(bson.M)["photographer_id"])
+
+	// Pendapatan disaring lewat booking hasil $lookup
+	pipeline, err = analytics.Pipeline("revenue_by_photographer", p)
+	require.NoError(t, err)
+	assert.Equal(t, bson.M{"booking.photographer_id": id}, pipeline[3].Map()["$match"])
+}
+
+func TestAnalyticsFixedZoneUsesOffset(t *testing.T) {
+	loc := time.FixedZone("WIB", 7*60*60)
+	p := analytics.Params{
+		From:     time.Date(2025, 1, 1, 0, 0, 0, 0, loc),
+		To:       time.Date(2025, 2, 1, 0, 0, 0, 0, loc),
+		Location: loc,
+	}
+	pipeline, err := analytics.Pipeline("new_clients_weekly", p)
+	require.NoError(t, err)
+
+	var week bson.M
+	for _, s := range pipeline {
+		if g, ok := s.Map()["$group"].(bson.M); ok {
+			if id, ok := g["_id"].(bson.M); ok {
+				week = id["$dateToString"].(bson.M)
+			}
+		}
+	}
+	require.NotNil(t, week)
+	assert.Equal(t, "+07:00", week["timezone"])
+	assert.Equal(t, "%G-W%V", week["format"])
+}
+
+func TestAnalyticsDefaultRangeHitsCache(t *testing.T) {
+	app := fiber.New()
+	app.Get("/analytics/:metric", handlers.GetAnalytics)
+
+	get := func() analytics.Report {
+		resp, err := app.Test(httptest.NewRequest("GET", "/analytics/booking_status", nil), -1)
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+		var body struct {
+			Data analytics.Report `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return body.Data
+	}
+
+	first, second := get(), get()
+	assert.Zero(t, first.To.Second(), "batas akhir default dibulatkan ke menit")
+	assert.Zero(t, first.To.Nanosecond())
+	if first.To.Equal(second.To) {
+		// Permintaan kedua dalam menit yang sama dilayani dari cache
+		assert.True(t, first.GeneratedAt.Equal(second.GeneratedAt))
+	}
+}
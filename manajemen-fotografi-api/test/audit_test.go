@@ -0,0 +1,131 @@
+package test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"manajemen-fotografi-api/audit"
+	"manajemen-fotografi-api/auth"
+	"manajemen-fotografi-api/middlewares"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/repositories"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// snapshotCollection mengembalikan dokumen FindOne secara berurutan, tanpa database
+type snapshotCollection struct {
+	repositories.Collection
+	name      string
+	snapshots []bson.M
+	inserted  []interface{}
+}
+
+func (s *snapshotCollection) Name() string { return s.name }
+
+func (s *snapshotCollection) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult {
+	if len(s.snapshots) == 0 {
+		return mongo.NewSingleResultFromDocument(bson.M{}, mongo.ErrNoDocuments, nil)
+	}
+	doc := s.snapshots[0]
+	s.snapshots = s.snapshots[1:]
+	return mongo.NewSingleResultFromDocument(doc, nil, nil)
+}
+
+func (s *snapshotCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	return &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil
+}
+
+func (s *snapshotCollection) InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	s.inserted = append(s.inserted, document)
+	return &mongo.InsertOneResult{InsertedID: primitive.NewObjectID()}, nil
+}
+
+func TestAuditDiff(t *testing.T) {
+	changes := audit.Diff(
+		bson.M{"_id": 1, "status": "pending", "note": "a", "location": "Bandung"},
+		bson.M{"_id": 1, "status": "done", "location": "Bandung", "version": int64(2)},
+	)
+	assert.Equal(t, []models.AuditChange{
+		{Field: "note", Before: "a", After: nil},
+		{Field: "status", Before: "pending", After: "done"},
+		{Field: "version", Before: nil, After: int64(2)},
+	}, changes)
+}
+
+func TestAuditRecordsUpdateWithActorAndSource(t *testing.T) {
+	id := primitive.NewObjectID()
+	bookings := &snapshotCollection{name: "bookings", snapshots: []bson.M{
+		{"_id": id, "status": "pending"},
+		{"_id": id, "status": "confirmed"},
+	}}
+	logs := &snapshotCollection{name: repositories.AuditCollection}
+	coll := repositories.WithAudit(bookings, logs)
+
+	actor := auth.Actor{UserID: primitive.NewObjectID(), Role: models.RoleClient}
+	ctx := auth.WithActor(context.Background(), actor)
+	ctx = audit.WithSource(ctx, audit.Source{IP: "10.0.0.1", RequestID: "req-1"})
+
+	_, err := coll.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"status": "confirmed"}})
+	assert.NoError(t, err)
+
+	if assert.Len(t, logs.inserted, 1) {
+		entry := logs.inserted[0].(models.AuditLog)
+		assert.Equal(t, "bookings", entry.Entity)
+		assert.Equal(t, id, entry.EntityID)
+		assert.Equal(t, models.AuditActionUpdate, entry.Action)
+		assert.Equal(t, actor.UserID, *entry.ActorID)
+		assert.Equal(t, models.RoleClient, entry.Role)
+		assert.Equal(t, "10.0.0.1", entry.IP)
+		assert.Equal(t, "req-1", entry.RequestID)
+		assert.Equal(t, []models.AuditChange{{Field: "status", Before: "pending", After: "confirmed"}}, entry.Changes)
+	}
+}
+
+func TestAuditRecordsCreate(t *testing.T) {
+	clients := &snapshotCollection{name: "clients"}
+	logs := &snapshotCollection{name: repositories.AuditCollection}
+	coll := repositories.WithAudit(clients, logs)
+
+	_, err := coll.InsertOne(context.Background(), models.Client{ID: primitive.NewObjectID(), Name: "Budi"})
+	assert.NoError(t, err)
+
+	if assert.Len(t, logs.inserted, 1) {
+		entry := logs.inserted[0].(models.AuditLog)
+		assert.Equal(t, models.AuditActionCreate, entry.Action)
+		assert.Nil(t, entry.ActorID)
+		assert.Contains(t, entry.Changes, models.AuditChange{Field: "name", Before: nil, After: "Budi"})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		if role := c.Get("X-Test-Role"); role != "" {
+			c.SetUserContext(auth.WithActor(c.UserContext(), auth.Actor{UserID: primitive.NewObjectID(), Role: role}))
+		}
+		return c.Next()
+	})
+	app.Get("/admin", middlewares.RequireRole(models.RoleAdmin), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	for role, status := range map[string]int{
+		"":                      fiber.StatusUnauthorized,
+		models.RoleClient:       fiber.StatusForbidden,
+		models.RolePhotographer: fiber.StatusForbidden,
+		models.RoleAdmin:        fiber.StatusOK,
+	} {
+		req := httptest.NewRequest("GET", "/admin", nil)
+		req.Header.Set("X-Test-Role", role)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, status, resp.StatusCode, role)
+	}
+}
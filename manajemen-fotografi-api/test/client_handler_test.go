@@ -7,12 +7,14 @@ import (
 	"manajemen-fotografi-api/config"
 	"manajemen-fotografi-api/handlers"
 	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/utils"
 	"net/http"
 	"testing"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/session"
+	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -32,6 +34,7 @@ func setupClientApp() *fiber.App {
 	app.Get("/clients/:id", handlers.GetClientByID)
 	app.Get("/clients/user/:user_id", handlers.GetClientByUserID)
 	app.Put("/clients/:id", handlers.UpdateClient)
+	app.Patch("/clients/:id", handlers.PatchClient)
 
 	return app
 }
@@ -39,12 +42,14 @@ func setupClientApp() *fiber.App {
 func TestCreateClient(t *testing.T) {
 	app := setupClientApp()
 
-	// Data client dummy dengan UserID valid dummy
-	userID := primitive.NewObjectID()
+	// Profil client harus menunjuk user yang ada dengan role client
+	userID := createTestUser(t, models.RoleClient)
 
 	client := models.Client{
-		UserID: userID,
-		Phone:  "081234567890",
+		UserID:  userID,
+		Name:    "Client Test",
+		Phone:   "081234567890",
+		Address: "Jl. Contoh No. 1",
 	}
 	data, _ := json.Marshal(client)
 
@@ -63,6 +68,24 @@ func TestCreateClient(t *testing.T) {
 	}
 }
 
+// createTestUser menyimpan user dengan role tertentu dan mengembalikan ID-nya
+func createTestUser(t *testing.T, role string) primitive.ObjectID {
+	user := models.User{
+		ID:        primitive.NewObjectID(),
+		Name:      "User Test",
+		Email:     primitive.NewObjectID().Hex() + "@example.com",
+		Password:  "hash",
+		Role:      role,
+		CreatedAt: time.Now().Unix(),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := config.GetCollection("users").InsertOne(ctx, user); err != nil {
+		t.Fatalf("Failed insert user: %v", err)
+	}
+	return user.ID
+}
+
 func TestGetClientByID(t *testing.T) {
 	app := setupClientApp()
 
@@ -144,8 +167,11 @@ func TestUpdateClient(t *testing.T) {
 	}
 
 	// Update data
+	// PUT mengganti seluruh field, jadi semua field wajib dikirim
 	updatedClient := models.Client{
-		Phone: "089876543210",
+		Name:    "Client Baru",
+		Phone:   "089876543210",
+		Address: "Jl. Merdeka No. 2",
 	}
 	data, _ := json.Marshal(updatedClient)
 	url := "/clients/" + client.ID.Hex()
@@ -155,6 +181,7 @@ func TestUpdateClient(t *testing.T) {
 		t.Fatalf("Failed to create request: %v", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("If-Match", utils.ETag(client.Version))
 	resp, err := app.Test(httpReq)
 	if err != nil {
 		t.Fatalf("Request failed: %v", err)
@@ -162,4 +189,75 @@ func TestUpdateClient(t *testing.T) {
 	if resp.StatusCode != fiber.StatusOK {
 		t.Errorf("Expected status 200, got %d", resp.StatusCode)
 	}
+	assert.Equal(t, utils.ETag(client.Version+1), resp.Header.Get("ETag"))
+
+	// ETag lama sudah tidak berlaku setelah update
+	httpReq, _ = http.NewRequest("PUT", url, bytes.NewBuffer(data))
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("If-Match", utils.ETag(client.Version))
+	resp, err = app.Test(httpReq)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	assert.Equal(t, fiber.StatusPreconditionFailed, resp.StatusCode)
+
+	// Tanpa If-Match update ditolak
+	httpReq, _ = http.NewRequest("PUT", url, bytes.NewBuffer(data))
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(httpReq)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	assert.Equal(t, fiber.StatusPreconditionRequired, resp.StatusCode)
+}
+
+func TestPatchClient(t *testing.T) {
+	app := setupClientApp()
+
+	client := models.Client{
+		ID:      primitive.NewObjectID(),
+		UserID:  primitive.NewObjectID(),
+		Name:    "Client Lama",
+		Phone:   "081234567890",
+		Address: "Jl. Merdeka No. 1",
+	}
+	client.Version = 1
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := config.GetCollection("clients").InsertOne(ctx, client)
+	if err != nil {
+		t.Fatalf("Failed insert client: %v", err)
+	}
+
+	patchRequest := func(body, ifMatch string) *http.Response {
+		req, _ := http.NewRequest("PATCH", "/clients/"+client.ID.Hex(), bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		req.Header.Set("If-Match", ifMatch)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		return resp
+	}
+
+	// Field yang tidak dikirim tidak ikut berubah
+	resp := patchRequest(`{"phone": "089876543210"}`, utils.ETag(1))
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, utils.ETag(2), resp.Header.Get("ETag"))
+
+	var result struct {
+		Data models.Client `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(t, "+6289876543210", result.Data.Phone)
+	assert.Equal(t, "Client Lama", result.Data.Name)
+	assert.Equal(t, "Jl. Merdeka No. 1", result.Data.Address)
+
+	// null menghapus field, sehingga field wajib ditolak validasi
+	resp = patchRequest(`{"name": null}`, utils.ETag(2))
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	// Field di luar whitelist tidak bisa diubah
+	resp = patchRequest(`{"user_id": "`+primitive.NewObjectID().Hex()+`"}`, utils.ETag(2))
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
 }
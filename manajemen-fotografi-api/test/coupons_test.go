@@ -0,0 +1,276 @@
+package test
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"manajemen-fotografi-api/coupons"
+	"manajemen-fotografi-api/handlers"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func couponKey(t *testing.T, err error) string {
+	require.Error(t, err)
+	appErr := utils.ToAppError(err)
+	require.Len(t, appErr.Details, 1)
+	return appErr.Details[0].Key
+}
+
+func TestCouponDiscount(t *testing.T) {
+	percent := models.Coupon{Type: models.CouponPercent, Value: 15}
+	assert.Equal(t, 225000.0, coupons.Discount(percent, 1500000))
+
+	percent.MaxDiscount = 100000
+	assert.Equal(t, 100000.0, coupons.Discount(percent, 1500000))
+
+	fixed := models.Coupon{Type: models.CouponFixed, Value: 250000}
+	assert.Equal(t, 250000.0, coupons.Discount(fixed, 1000000))
+	assert.Equal(t, 200000.0, coupons.Discount(fixed, 200000), "potongan tidak melebihi subtotal")
+
+	assert.Equal(t, 16667.0, coupons.Discount(models.Coupon{Type: models.CouponPercent, Value: 33.3334}, 50000))
+}
+
+func TestCouponNormalize(t *testing.T) {
+	assert.Equal(t, "WISUDA2026", coupons.Normalize("  wisuda2026 "))
+}
+
+func TestCouponCheck(t *testing.T) {
+	now := time.Date(2026, 6, 1, 10, 0, 0, 0, time.UTC)
+	start, end := now.Add(-time.Hour), now.Add(time.Hour)
+	photographer := primitive.NewObjectID()
+	valid := models.Coupon{
+		Type: models.CouponPercent, Value: 15, Active: true,
+		StartsAt: &start, EndsAt: &end, MinSpend: 500000,
+		PhotographerIDs: []primitive.ObjectID{photographer},
+	}
+	checkout := coupons.Checkout{PhotographerID: photographer, Subtotal: 750000, At: now}
+	require.NoError(t, coupons.Check(valid, checkout))
+
+	inactive := valid
+	inactive.Active = false
+	assert.Equal(t, "coupon.inactive", couponKey(t, coupons.Check(inactive, checkout)))
+
+	early := checkout
+	early.At = start.Add(-time.Minute)
+	assert.Equal(t, "coupon.not_started", couponKey(t, coupons.Check(valid, early)))
+
+	late := checkout
+	late.At = end
+	assert.Equal(t, "coupon.expired", couponKey(t, coupons.Check(valid, late)))
+
+	other := checkout
+	other.PhotographerID = primitive.NewObjectID()
+	assert.Equal(t, "coupon.not_applicable", couponKey(t, coupons.Check(valid, other)))
+
+	small := checkout
+	small.Subtotal = 499999
+	err := coupons.Check(valid, small)
+	assert.Equal(t, "coupon.min_spend", couponKey(t, err))
+	assert.Equal(t, "500000", utils.ToAppError(err).Details[0].Params["param"])
+
+	open := models.Coupon{Type: models.CouponFixed, Value: 50000, Active: true}
+	assert.NoError(t, coupons.Check(open, other), "tanpa batasan berlaku untuk semua fotografer")
+}
+
+func TestCouponRedeemConcurrentRespectsUsageLimit(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	const attempts, limit = 20, 5
+	coupon := models.Coupon{
+		ID:         primitive.NewObjectID(),
+		Code:       coupons.Normalize("par" + primitive.NewObjectID().Hex()),
+		Type:       models.CouponFixed,
+		Value:      50000,
+		UsageLimit: limit,
+		Active:     true,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	_, err := repositories.New(coupons.Collection).InsertOne(ctx, coupon)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		ctx := context.Background()
+		repositories.Unscoped(coupons.Collection).DeleteOne(ctx, bson.M{"_id": coupon.ID})
+		repositories.Unscoped(coupons.RedemptionCollection).DeleteMany(ctx, bson.M{"coupon_id": coupon.ID})
+	})
+
+	var (
+		wg          sync.WaitGroup
+		mu          sync.Mutex
+		redemptions []models.CouponRedemption
+		rejected    []error
+	)
+	start := make(chan struct{})
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			checkout := coupons.Checkout{
+				ClientID:  primitive.NewObjectID(),
+				BookingID: primitive.NewObjectID(),
+				Subtotal:  1000000,
+				At:        time.Now(),
+			}
+			redemption, _, err := coupons.Redeem(ctx, coupon.Code, checkout, primitive.NewObjectID())
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				rejected = append(rejected, err)
+				return
+			}
+			redemptions = append(redemptions, redemption)
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	require.Len(t, redemptions, limit)
+	require.Len(t, rejected, attempts-limit)
+	for _, err := range rejected {
+		assert.Equal(t, "coupon.used_up", couponKey(t, err))
+	}
+
+	stored := func() models.Coupon {
+		var c models.Coupon
+		require.NoError(t, repositories.New(coupons.Collection).FindOne(ctx, bson.M{"_id": coupon.ID}).Decode(&c))
+		return c
+	}
+	assert.Equal(t, limit, stored().Redeemed)
+	n, err := repositories.New(coupons.RedemptionCollection).CountDocuments(ctx, bson.M{"coupon_id": coupon.ID})
+	require.NoError(t, err)
+	assert.EqualValues(t, limit, n)
+
+	// Jatah yang dikembalikan bisa dipakai client lain, lalu kupon habis lagi
+	coupons.Release(ctx, redemptions[0])
+	released := stored()
+	assert.Equal(t, limit-1, released.Redeemed)
+	assert.Zero(t, released.Usage[redemptions[0].ClientID.Hex()])
+
+	checkout := coupons.Checkout{ClientID: primitive.NewObjectID(), Subtotal: 1000000, At: time.Now()}
+	_, _, err = coupons.Redeem(ctx, coupon.Code, checkout, primitive.NewObjectID())
+	require.NoError(t, err)
+	checkout.ClientID = primitive.NewObjectID()
+	_, _, err = coupons.Redeem(ctx, coupon.Code, checkout, primitive.NewObjectID())
+	assert.Equal(t, "coupon.used_up", couponKey(t, err))
+}
+
+func TestCheckoutConcurrentOnSameBookingPaysOnce(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	booking := models.Booking{
+		ID:             primitive.NewObjectID(),
+		ClientID:       primitive.NewObjectID(),
+		PhotographerID: primitive.NewObjectID(),
+		Date:           time.Now().AddDate(0, 1, 0),
+		Status:         models.BookingStatusPending,
+		CreatedAt:      time.Now(),
+	}
+	coupon := models.Coupon{
+		ID:         primitive.NewObjectID(),
+		Code:       coupons.Normalize("dup" + primitive.NewObjectID().Hex()),
+		Type:       models.CouponFixed,
+		Value:      50000,
+		UsageLimit: 10,
+		Active:     true,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	_, err := repositories.New("bookings").InsertOne(ctx, booking)
+	require.NoError(t, err)
+	_, err = repositories.New(coupons.Collection).InsertOne(ctx, coupon)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		ctx := context.Background()
+		repositories.Unscoped("bookings").DeleteOne(ctx, bson.M{"_id": booking.ID})
+		repositories.Unscoped("transactions").DeleteMany(ctx, bson.M{"booking_id": booking.ID})
+		repositories.Unscoped(coupons.Collection).DeleteOne(ctx, bson.M{"_id": coupon.ID})
+		repositories.Unscoped(coupons.RedemptionCollection).DeleteMany(ctx, bson.M{"coupon_id": coupon.ID})
+	})
+
+	app := fiber.New()
+	app.Post("/transactions", handlers.CreateDummyTransaction)
+	body := `{"booking_id":"` + booking.ID.Hex() + `","method":"transfer","total":1000000,"coupon_code":"` + coupon.Code + `"}`
+
+	const attempts = 2
+	statuses := make(chan int, attempts)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			req := httptest.NewRequest("POST", "/transactions", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req, -1)
+			if !assert.NoError(t, err) {
+				return
+			}
+			statuses <- resp.StatusCode
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(statuses)
+
+	var got []int
+	for status := range statuses {
+		got = append(got, status)
+	}
+	assert.ElementsMatch(t, []int{fiber.StatusCreated, fiber.StatusConflict}, got)
+
+	n, err := repositories.New("transactions").CountDocuments(ctx, bson.M{"booking_id": booking.ID})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, n, "booking hanya dibayar sekali")
+
+	var stored models.Coupon
+	require.NoError(t, repositories.New(coupons.Collection).FindOne(ctx, bson.M{"_id": coupon.ID}).Decode(&stored))
+	assert.Equal(t, 1, stored.Redeemed, "kupon hanya terpakai sekali")
+}
+
+func TestCheckoutRejectedCouponKeepsBookingPending(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	booking := models.Booking{
+		ID:             primitive.NewObjectID(),
+		ClientID:       primitive.NewObjectID(),
+		PhotographerID: primitive.NewObjectID(),
+		Date:           time.Now().AddDate(0, 1, 0),
+		Status:         models.BookingStatusPending,
+		CreatedAt:      time.Now(),
+	}
+	_, err := repositories.New("bookings").InsertOne(ctx, booking)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		repositories.Unscoped("bookings").DeleteOne(context.Background(), bson.M{"_id": booking.ID})
+	})
+
+	app := fiber.New()
+	app.Post("/transactions", handlers.CreateDummyTransaction)
+	body := `{"booking_id":"` + booking.ID.Hex() + `","method":"transfer","total":1000000,"coupon_code":"TIDAKADA` + primitive.NewObjectID().Hex()[:8] + `"}`
+	req := httptest.NewRequest("POST", "/transactions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	var stored models.Booking
+	require.NoError(t, repositories.New("bookings").FindOne(ctx, bson.M{"_id": booking.ID}).Decode(&stored))
+	assert.Equal(t, models.BookingStatusPending, stored.Status, "checkout gagal tidak boleh mengunci booking")
+}
@@ -0,0 +1,266 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"manajemen-fotografi-api/auth"
+	"manajemen-fotografi-api/events"
+	"manajemen-fotografi-api/handlers"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/repositories"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestBusDeliversOnlyToRecipients(t *testing.T) {
+	bus := events.NewBus(nil)
+	alice, bob := primitive.NewObjectID(), primitive.NewObjectID()
+
+	subAlice := bus.Subscribe(events.ForUser(alice))
+	subBob := bus.Subscribe(events.ForUser(bob))
+	defer subAlice.Close()
+	defer subBob.Close()
+
+	bus.Publish(context.Background(), events.BookingCreated, []primitive.ObjectID{alice},
+		map[string]interface{}{"booking": models.Booking{Location: "Bandung"}})
+
+	select {
+	case event := <-subAlice.C:
+		assert.Equal(t, events.BookingCreated, event.Type)
+		assert.Equal(t, "Bandung", event.Data["booking"].(bson.M)["location"])
+	case <-time.After(time.Second):
+		t.Fatal("event tidak diterima")
+	}
+	assert.Empty(t, subBob.C)
+}
+
+func TestBusDropsDuplicatesAndSlowSubscribers(t *testing.T) {
+	bus := events.NewBus(nil)
+	sub := bus.Subscribe(nil)
+
+	event := models.Event{ID: primitive.NewObjectID(), Type: events.TransactionPaid}
+	bus.Dispatch(event)
+	bus.Dispatch(event)
+	assert.Len(t, sub.C, 1)
+
+	// Subscriber yang tidak pernah membaca diputus, bukan menahan publisher
+	for i := 0; i < 100; i++ {
+		bus.Dispatch(models.Event{ID: primitive.NewObjectID()})
+	}
+	closed := false
+	for range sub.C {
+		closed = true
+	}
+	assert.True(t, closed)
+}
+
+func TestStreamEventsSendsServerSentEvents(t *testing.T) {
+	user := primitive.NewObjectID()
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(auth.WithActor(c.UserContext(), auth.Actor{UserID: user, Role: models.RoleClient}))
+		return c.Next()
+	})
+	app.Get("/events", handlers.StreamEvents)
+
+	go func() {
+		// Tunggu sampai handler subscribe, lalu kirim event dan tutup stream
+		time.Sleep(200 * time.Millisecond)
+		events.Publish(context.Background(), events.BookingStatusChanged, []primitive.ObjectID{user},
+			map[string]interface{}{"from": "pending", "to": "confirmed"})
+		time.Sleep(100 * time.Millisecond)
+		events.Default.Close()
+	}()
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/events", nil), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.True(t, strings.HasPrefix(string(body), "retry: 3000\n\n"))
+	assert.Contains(t, string(body), "event: booking.status_changed\n")
+	assert.Contains(t, string(body), `"to":"confirmed"`)
+}
+
+func TestStreamEventsRejectsInvalidLastEventID(t *testing.T) {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(auth.WithActor(c.UserContext(), auth.Actor{UserID: primitive.NewObjectID()}))
+		return c.Next()
+	})
+	app.Get("/events", handlers.StreamEvents)
+
+	req := httptest.NewRequest("GET", "/events", nil)
+	req.Header.Set("Last-Event-ID", "bukan-id")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// memoryEventStore menyimpan event bus di memori. beforeFind dijalankan sekali sebelum
+// Find berikutnya, untuk menerbitkan event di antara Subscribe dan Since milik Consume.
+type memoryEventStore struct {
+	repositories.Collection
+	mu         sync.Mutex
+	events     []models.Event
+	beforeFind func()
+}
+
+func (s *memoryEventStore) InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, document.(models.Event))
+	return &mongo.InsertOneResult{}, nil
+}
+
+func (s *memoryEventStore) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	s.mu.Lock()
+	before := s.beforeFind
+	s.beforeFind = nil
+	s.mu.Unlock()
+	if before != nil {
+		before()
+	}
+
+	afterID := filter.(bson.M)["_id"].(bson.M)["$gt"].(primitive.ObjectID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var docs []interface{}
+	for _, event := range s.events {
+		if bytes.Compare(event.ID[:], afterID[:]) > 0 {
+			docs = append(docs, event)
+		}
+	}
+	return mongo.NewCursorFromDocuments(docs, nil, nil)
+}
+
+func (s *memoryEventStore) snapshot() []models.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]models.Event(nil), s.events...)
+}
+
+func TestConsumeHandlesEventsPublishedDuringCatchUpOnce(t *testing.T) {
+	store := &memoryEventStore{}
+	bus := events.NewBus(store)
+	original := events.Default
+	events.Default = bus
+	defer func() { events.Default = original }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	handled := map[primitive.ObjectID]int{}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	first := true
+	go events.Consume(ctx, "test", func(ctx context.Context, event models.Event) {
+		if first {
+			// Consumer tertahan supaya buffer penuh dan langganannya diputus bus
+			first = false
+			close(started)
+			<-release
+		}
+		mu.Lock()
+		handled[event.ID]++
+		mu.Unlock()
+	})
+	count := func(id primitive.ObjectID) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return handled[id]
+	}
+
+	// Tunggu sampai Consume berlangganan
+	for waiting := true; waiting; {
+		bus.Publish(ctx, "test.ping", nil, bson.M{})
+		select {
+		case <-started:
+			waiting = false
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		bus.Publish(ctx, "test.batch", nil, bson.M{"i": i})
+	}
+	store.mu.Lock()
+	store.beforeFind = func() { bus.Publish(ctx, "test.window", nil, bson.M{}) }
+	store.mu.Unlock()
+	close(release)
+
+	// Event penutup baru diproses setelah antrean langganan sebelumnya habis
+	assert.Eventually(t, func() bool {
+		for _, event := range store.snapshot() {
+			if event.Type == "test.window" && count(event.ID) > 0 {
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+	bus.Publish(ctx, "test.done", nil, bson.M{})
+	assert.Eventually(t, func() bool {
+		all := store.snapshot()
+		return count(all[len(all)-1].ID) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	for _, event := range store.snapshot() {
+		if event.Type == "test.ping" {
+			assert.LessOrEqual(t, count(event.ID), 1)
+			continue
+		}
+		assert.Equal(t, 1, count(event.ID), event.Type)
+	}
+}
+
+func TestConsumeHandlesLiveEventsOutOfOrder(t *testing.T) {
+	bus := events.NewBus(&memoryEventStore{})
+	original := events.Default
+	events.Default = bus
+	defer func() { events.Default = original }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	handled := map[primitive.ObjectID]bool{}
+	go events.Consume(ctx, "test", func(ctx context.Context, event models.Event) {
+		mu.Lock()
+		handled[event.ID] = true
+		mu.Unlock()
+	})
+	seen := func(id primitive.ObjectID) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return handled[id]
+	}
+
+	// Tunggu sampai Consume berlangganan
+	assert.Eventually(t, func() bool {
+		bus.Dispatch(models.Event{ID: primitive.NewObjectID(), Type: "test.ping"})
+		mu.Lock()
+		defer mu.Unlock()
+		return len(handled) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Dua Publish bersamaan bisa sampai ke bus dengan urutan terbalik
+	older := models.Event{ID: primitive.NewObjectID(), Type: "test.older"}
+	newer := models.Event{ID: primitive.NewObjectID(), Type: "test.newer"}
+	bus.Dispatch(newer)
+	bus.Dispatch(older)
+
+	assert.Eventually(t, func() bool { return seen(newer.ID) && seen(older.ID) }, 5*time.Second, 10*time.Millisecond)
+}
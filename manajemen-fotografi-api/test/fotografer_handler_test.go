@@ -46,8 +46,8 @@ func TestCreatePhotographerHandler(t *testing.T) {
 	// Pasang route CreatePhotographer
 	app.Post("/photographers", handlers.CreatePhotographer)
 
-	// Dummy data input
-	userID := primitive.NewObjectID()
+	// Dummy data input, user harus ada dengan role photographer
+	userID := createTestUser(t, models.RolePhotographer)
 	newPhotographer := models.Photographer{
 		UserID:      userID,
 		Phone:       "08123456789",
@@ -72,10 +72,13 @@ func TestCreatePhotographerHandler(t *testing.T) {
 		t.Errorf("Expected status %d, got %d", fiber.StatusCreated, resp.StatusCode)
 	}
 
-	var created models.Photographer
-	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+	var envelope struct {
+		Data models.Photographer `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
 		t.Fatalf("Failed to decode response: %v", err)
 	}
+	created := envelope.Data
 
 	if created.ID.IsZero() {
 		t.Errorf("Expected created photographer to have ID assigned")
@@ -125,10 +128,13 @@ func TestGetPhotographerByID(t *testing.T) {
 		t.Errorf("Expected status 200, got %d", respValid.StatusCode)
 	}
 
-	var result models.Photographer
-	if err := json.NewDecoder(respValid.Body).Decode(&result); err != nil {
+	var envelope struct {
+		Data models.Photographer `json:"data"`
+	}
+	if err := json.NewDecoder(respValid.Body).Decode(&envelope); err != nil {
 		t.Fatalf("Decode failed: %v", err)
 	}
+	result := envelope.Data
 
 	if result.ID != dummyPhotographer.ID {
 		t.Errorf("Expected ID %s, got %s", dummyPhotographer.ID.Hex(), result.ID.Hex())
@@ -0,0 +1,79 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"manajemen-fotografi-api/i18n"
+	"manajemen-fotografi-api/middlewares"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveLanguage(t *testing.T) {
+	assert.Equal(t, i18n.Indonesian, i18n.Resolve("", ""))
+	assert.Equal(t, i18n.English, i18n.Resolve("", "en-US,en;q=0.9"))
+	assert.Equal(t, i18n.English, i18n.Resolve("", "fr-FR, id;q=0.5, en;q=0.8"))
+	assert.Equal(t, i18n.Indonesian, i18n.Resolve("", "fr-FR"))
+	assert.Equal(t, i18n.Indonesian, i18n.Resolve("id", "en"))
+}
+
+func TestCatalogsComplete(t *testing.T) {
+	// Setiap pesan bahasa Indonesia harus punya terjemahan bahasa Inggris, dan sebaliknya
+	assert.Equal(t, i18n.Keys(i18n.Indonesian), i18n.Keys(i18n.English))
+
+	for _, code := range []*utils.AppError{utils.ErrInternal, utils.ErrValidation, utils.ErrBookingNotFound, utils.ErrEmailTaken} {
+		assert.True(t, i18n.Has(code.Code), code.Code)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	assert.Equal(t, "Booking not found", i18n.T(i18n.English, "BOOKING_NOT_FOUND", nil))
+	assert.Equal(t, "Booking tidak ditemukan", i18n.T("fr", "BOOKING_NOT_FOUND", nil))
+	assert.Equal(t, "Hi Ayu,", i18n.T(i18n.English, "email.greeting", map[string]string{"name": "Ayu"}))
+	assert.Equal(t, "kunci.tidak.ada", i18n.T(i18n.English, "kunci.tidak.ada", nil))
+}
+
+func TestLocalizedErrorResponse(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler})
+	middlewares.SetupLocale(app)
+	app.Get("/booking", func(c *fiber.Ctx) error {
+		return utils.Error(c, utils.ErrBookingNotFound)
+	})
+	app.Post("/user", func(c *fiber.Ctx) error {
+		return utils.Error(c, utils.Validate(models.User{Name: "Ayu", Email: "salah", Role: "client"}))
+	})
+
+	req := httptest.NewRequest("GET", "/booking", nil)
+	req.Header.Set("Accept-Language", "en-GB,en;q=0.9")
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, "en", resp.Header.Get("Content-Language"))
+
+	var body utils.Response
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "BOOKING_NOT_FOUND", body.Error.Code)
+	assert.Equal(t, "Booking not found", body.Error.Message)
+
+	// Tanpa Accept-Language dipakai bahasa Indonesia
+	resp, err = app.Test(httptest.NewRequest("GET", "/booking", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, "id", resp.Header.Get("Content-Language"))
+
+	body = utils.Response{}
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "Booking tidak ditemukan", body.Error.Message)
+
+	// Detail validasi ikut diterjemahkan
+	resp, err = app.Test(httptest.NewRequest("POST", "/user?lang=en", nil))
+	assert.Nil(t, err)
+
+	body = utils.Response{}
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "Invalid data", body.Error.Message)
+	assert.Equal(t, []utils.FieldError{{Field: "email", Code: "email", Message: "Invalid email format"}}, body.Error.Details)
+}
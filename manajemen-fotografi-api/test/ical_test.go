@@ -0,0 +1,114 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"manajemen-fotografi-api/ical"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var wibZone = time.FixedZone("WIB", 7*60*60)
+
+func TestEncodeEscapesTextAndFoldsLongLines(t *testing.T) {
+	start := time.Date(2025, 8, 17, 9, 0, 0, 0, wibZone)
+	out := string(ical.Encode(ical.Calendar{
+		ProdID: "-//Test//ID",
+		Events: []ical.Event{{
+			UID:         "abc@manajemen-fotografi",
+			Start:       start,
+			End:         start.Add(2 * time.Hour),
+			Summary:     "Sesi foto: Budi, Ani; keluarga",
+			Location:    `Jl. Merdeka\10`,
+			Description: strings.Repeat("Catatan panjang dengan é ", 10) + "\nbaris kedua",
+			Status:      ical.StatusCancelled,
+			Sequence:    3,
+			Modified:    start,
+		}},
+	}))
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, "baris terlalu panjang: %q", line)
+		assert.False(t, strings.Contains(line, "\n"))
+	}
+	assert.Contains(t, out, "DTSTART:20250817T020000Z\r\n")
+	assert.Contains(t, out, "DTEND:20250817T040000Z\r\n")
+	assert.Contains(t, out, `SUMMARY:Sesi foto: Budi\, Ani\; keluarga`)
+	assert.Contains(t, out, `LOCATION:Jl. Merdeka\\10`)
+	assert.Contains(t, out, "STATUS:CANCELLED\r\n")
+	assert.Contains(t, out, "SEQUENCE:3\r\n")
+
+	// Hasil encode bisa dibaca kembali tanpa kehilangan teks, termasuk karakter multi-byte
+	events, err := ical.Parse(strings.NewReader(out), time.UTC)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "Sesi foto: Budi, Ani; keluarga", events[0].Summary)
+	assert.Equal(t, strings.Repeat("Catatan panjang dengan é ", 10)+"\nbaris kedua", events[0].Description)
+	assert.True(t, events[0].Start.Equal(start))
+}
+
+func TestParseReadsTimeFormatsAndSkipsNestedComponents(t *testing.T) {
+	data := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:utc",
+		"DTSTART:20250901T010000Z",
+		"DTEND:20250901T030000Z",
+		"BEGIN:VALARM",
+		"TRIGGER:-PT15M",
+		"DESCRIPTION:alarm",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:tzid",
+		`DTSTART;TZID="Asia/Tokyo":20250901T100000`,
+		"DURATION:PT1H30M",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:allday",
+		"DTSTART;VALUE=DATE:20250902",
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:floating",
+		"DTSTART:20250903T090000",
+		"DTEND:20250903T100000",
+		"RRULE:FREQ=WEEKLY",
+		"STATUS:cancelled",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	events, err := ical.Parse(strings.NewReader(data), wibZone)
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+
+	assert.Equal(t, "", events[0].Description, "properti VALARM tidak boleh masuk ke event")
+	assert.Equal(t, time.Date(2025, 9, 1, 1, 0, 0, 0, time.UTC), events[0].Start.UTC())
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err == nil {
+		assert.True(t, events[1].Start.Equal(time.Date(2025, 9, 1, 10, 0, 0, 0, tokyo)))
+		assert.Equal(t, 90*time.Minute, events[1].End.Sub(events[1].Start))
+	}
+
+	assert.True(t, events[2].AllDay)
+	assert.True(t, events[2].Transparent)
+	assert.Equal(t, 24*time.Hour, events[2].End.Sub(events[2].Start))
+
+	assert.True(t, events[3].Start.Equal(time.Date(2025, 9, 3, 9, 0, 0, 0, wibZone)))
+	assert.Equal(t, "FREQ=WEEKLY", events[3].RRule)
+	assert.Equal(t, ical.StatusCancelled, events[3].Status)
+}
+
+func TestParseRejectsInvalidCalendar(t *testing.T) {
+	_, err := ical.Parse(strings.NewReader("bukan kalender"), time.UTC)
+	assert.Error(t, err)
+
+	_, err = ical.Parse(strings.NewReader("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:x\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"), time.UTC)
+	assert.Error(t, err, "VEVENT tanpa DTSTART harus ditolak")
+}
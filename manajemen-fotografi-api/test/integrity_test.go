@@ -0,0 +1,78 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"manajemen-fotografi-api/i18n"
+	"manajemen-fotografi-api/integrity"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDeletePolicies(t *testing.T) {
+	policies := func(parent string) map[string]integrity.Policy {
+		out := map[string]integrity.Policy{}
+		for _, r := range integrity.Rules(parent) {
+			out[r.Name] = r.Policy
+		}
+		return out
+	}
+
+	assert.Equal(t, map[string]integrity.Policy{
+		"active_bookings": integrity.Restrict,
+		"galleries":       integrity.Cascade,
+		"bookings":        integrity.Anonymize,
+	}, policies("photographers"))
+	assert.Equal(t, integrity.Restrict, policies("bookings")["transactions"])
+
+	// Kebijakan bisa diganti lewat env, nilai yang tidak dikenal diabaikan
+	t.Setenv("DELETE_POLICY_PHOTOGRAPHERS_GALLERIES", "anonymize")
+	t.Setenv("DELETE_POLICY_CLIENTS_BOOKINGS", "hapus")
+	assert.Equal(t, integrity.Anonymize, policies("photographers")["galleries"])
+	assert.Equal(t, integrity.Anonymize, policies("clients")["bookings"])
+}
+
+func TestDeletePolicyMessages(t *testing.T) {
+	// Setiap aturan bisa diubah menjadi restrict, jadi semuanya butuh pesan
+	for _, parent := range []string{"clients", "photographers", "bookings"} {
+		for _, r := range integrity.Rules(parent) {
+			assert.True(t, i18n.Has("integrity.restricted."+r.Name), r.Name)
+		}
+	}
+}
+
+func TestRestoreProfileConflictsWithNewProfile(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	clients := repositories.New("clients")
+	userID := createTestUser(t, models.RoleClient)
+	old := models.Client{ID: primitive.NewObjectID(), UserID: userID, Name: "Lama", Phone: "081234567890"}
+	_, err := clients.InsertOne(ctx, old)
+	require.NoError(t, err)
+	require.NoError(t, integrity.Delete(ctx, "clients", old.ID, utils.ErrClientNotFound))
+
+	// Selama profil lama di trash, user yang sama membuat profil baru
+	fresh := models.Client{ID: primitive.NewObjectID(), UserID: userID, Name: "Baru", Phone: "081234567890"}
+	_, err = clients.InsertOne(ctx, fresh)
+	require.NoError(t, err)
+	defer repositories.Unscoped("clients").DeleteMany(ctx, bson.M{"user_id": userID})
+
+	err = integrity.Restore(ctx, "clients", old.ID, utils.ErrClientNotFound)
+	assert.ErrorIs(t, err, utils.ErrClientExists)
+	assert.Equal(t, fiber.StatusConflict, utils.StatusOf(err))
+
+	// Profil lama tetap di trash
+	n, err := clients.CountDocuments(ctx, bson.M{"_id": old.ID})
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
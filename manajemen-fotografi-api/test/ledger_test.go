@@ -0,0 +1,100 @@
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"manajemen-fotografi-api/ledger"
+	"manajemen-fotografi-api/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestLedgerPaymentSplitsCommission(t *testing.T) {
+	gross := ledger.Amount(1250000.4)
+	commission := ledger.Commission(gross, 0.15)
+	assert.Equal(t, int64(1250000), gross)
+	assert.Equal(t, int64(187500), commission)
+
+	lines := ledger.PaymentLines(gross, commission)
+	require.NoError(t, ledger.Balanced(lines))
+	assert.Equal(t, []models.LedgerLine{
+		{Account: ledger.AccountCash, Debit: 1250000},
+		{Account: ledger.AccountPayable, Credit: 1062500},
+		{Account: ledger.AccountCommission, Credit: 187500},
+	}, lines)
+	assert.Equal(t, int64(1062500), ledger.Payable(lines))
+}
+
+func TestLedgerZeroCommissionOmitsLine(t *testing.T) {
+	lines := ledger.PaymentLines(500000, ledger.Commission(500000, 0))
+	require.NoError(t, ledger.Balanced(lines))
+	assert.Len(t, lines, 2)
+	assert.Equal(t, int64(500000), ledger.Payable(lines))
+}
+
+func TestLedgerRefundReversesProportionally(t *testing.T) {
+	lines := ledger.RefundLines(400000, ledger.Commission(400000, 0.2))
+	require.NoError(t, ledger.Balanced(lines))
+	assert.Equal(t, int64(-320000), ledger.Payable(lines))
+	assert.Contains(t, lines, models.LedgerLine{Account: ledger.AccountCommission, Debit: 80000})
+	assert.Contains(t, lines, models.LedgerLine{Account: ledger.AccountCash, Credit: 400000})
+}
+
+func TestLedgerAdjustmentAndPayoutSigns(t *testing.T) {
+	plus := ledger.AdjustmentLines(75000)
+	minus := ledger.AdjustmentLines(-25000)
+	payout := ledger.PayoutLines(50000)
+	for _, lines := range [][]models.LedgerLine{plus, minus, payout} {
+		require.NoError(t, ledger.Balanced(lines))
+	}
+	assert.Equal(t, int64(75000), ledger.Payable(plus))
+	assert.Equal(t, int64(-25000), ledger.Payable(minus))
+	assert.Equal(t, int64(-50000), ledger.Payable(payout))
+}
+
+func TestLedgerRejectsUnbalancedJournal(t *testing.T) {
+	cases := map[string][]models.LedgerLine{
+		"satu baris":     {{Account: ledger.AccountCash, Debit: 100}},
+		"tidak seimbang": {{Account: ledger.AccountCash, Debit: 100}, {Account: ledger.AccountPayable, Credit: 90}},
+		"debit dan kredit": {
+			{Account: ledger.AccountCash, Debit: 100, Credit: 100},
+			{Account: ledger.AccountPayable, Credit: 0},
+		},
+		"negatif":     {{Account: ledger.AccountCash, Debit: -100}, {Account: ledger.AccountPayable, Credit: -100}},
+		"nominal nol": ledger.AdjustmentLines(0),
+	}
+	for name, lines := range cases {
+		assert.ErrorIs(t, ledger.Balanced(lines), ledger.ErrUnbalanced, name)
+	}
+}
+
+func TestLedgerEnvRate(t *testing.T) {
+	t.Setenv("PLATFORM_COMMISSION_RATE", "0.1")
+	assert.Equal(t, 0.1, ledger.EnvRate())
+
+	t.Setenv("PLATFORM_COMMISSION_RATE", "1.5")
+	assert.Equal(t, ledger.DefaultRate, ledger.EnvRate())
+
+	t.Setenv("PLATFORM_COMMISSION_RATE", "")
+	assert.Equal(t, ledger.DefaultRate, ledger.EnvRate())
+}
+
+func TestLedgerPayoutCSV(t *testing.T) {
+	photographer := primitive.NewObjectID()
+	journal := primitive.NewObjectID()
+	batch := models.PayoutBatch{Items: []models.PayoutItem{
+		{PhotographerID: photographer, Amount: 1062500, Journals: 3, JournalID: journal},
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, ledger.WriteCSV(&buf, batch, map[primitive.ObjectID]ledger.Payee{
+		photographer: {Name: "Budi, Studio", Phone: "+6281234567890"},
+	}))
+	assert.Equal(t,
+		"reference,photographer_id,name,phone,amount,currency\n"+
+			journal.Hex()+","+photographer.Hex()+",\"Budi, Studio\",+6281234567890,1062500,IDR\n",
+		buf.String())
+}
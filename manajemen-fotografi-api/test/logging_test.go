@@ -0,0 +1,59 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"manajemen-fotografi-api/logging"
+	"manajemen-fotografi-api/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerRedactsSensitiveFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, slog.LevelInfo)
+
+	logger.Info("registrasi", "email", "a@example.com", "password", "rahasia123",
+		slog.Group("client", "phone", "081234567890", "client_phone", "081200000000"))
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Log bukan JSON: %v", err)
+	}
+	assert.Equal(t, "a@example.com", entry["email"])
+	assert.Equal(t, "[REDACTED]", entry["password"])
+
+	client := entry["client"].(map[string]interface{})
+	assert.Equal(t, "[REDACTED]", client["phone"])
+	assert.Equal(t, "[REDACTED]", client["client_phone"])
+	assert.NotContains(t, buf.String(), "081234567890")
+}
+
+func TestRequestIDPropagation(t *testing.T) {
+	app := fiber.New()
+	middlewares.SetupLogger(app)
+
+	var seen string
+	app.Get("/ping", func(c *fiber.Ctx) error {
+		seen, _ = c.Locals("requestid").(string)
+		return c.SendString("pong")
+	})
+
+	// Request ID dari client dipakai ulang
+	req := httptest.NewRequest("GET", "/ping", nil)
+	req.Header.Set(middlewares.RequestIDHeader, "req-123")
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, "req-123", resp.Header.Get(middlewares.RequestIDHeader))
+	assert.Equal(t, "req-123", seen)
+
+	// Tanpa header, request ID dibuatkan
+	resp, err = app.Test(httptest.NewRequest("GET", "/ping", nil))
+	assert.Nil(t, err)
+	assert.NotEmpty(t, resp.Header.Get(middlewares.RequestIDHeader))
+}
@@ -0,0 +1,91 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"manajemen-fotografi-api/messaging"
+	"manajemen-fotografi-api/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWhatsAppProviderSendsTextMessage(t *testing.T) {
+	var got map[string]interface{}
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"messages":[{"id":"wamid.1"}]}`))
+	}))
+	defer server.Close()
+
+	provider := &messaging.WhatsAppProvider{URL: server.URL, Token: "token-rahasia"}
+	err := provider.Send(context.Background(), messaging.Message{To: "+6281234567890", Text: "Booking dikonfirmasi"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Bearer token-rahasia", auth)
+	assert.Equal(t, "whatsapp", got["messaging_product"])
+	assert.Equal(t, "6281234567890", got["to"])
+	assert.Equal(t, "text", got["type"])
+	assert.Equal(t, "Booking dikonfirmasi", got["text"].(map[string]interface{})["body"])
+}
+
+func TestSMSGatewayFailsOnNon2xx(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "kuota habis", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	gateway := &messaging.SMSGateway{URL: server.URL, SenderID: "STUDIO"}
+	err := gateway.Send(context.Background(), messaging.Message{To: "+6281234567890", Text: "Halo"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "kuota habis")
+}
+
+func TestFakeSenderRecordsMessages(t *testing.T) {
+	fake := &messaging.Fake{}
+	require.NoError(t, fake.Send(context.Background(), messaging.Message{To: "+6281234567890", Text: "Halo"}))
+	assert.Equal(t, []messaging.Message{{To: "+6281234567890", Text: "Halo"}}, fake.Sent())
+
+	fake.Err = errors.New("provider mati")
+	assert.Error(t, fake.Send(context.Background(), messaging.Message{To: "+6281234567890"}))
+	assert.Len(t, fake.Sent(), 1)
+}
+
+func TestThrottleLimitsRate(t *testing.T) {
+	fake := &messaging.Fake{}
+	sender := messaging.Throttle(fake, 10) // burst 10, lalu satu pesan per 100ms
+
+	start := time.Now()
+	for i := 0; i < 12; i++ {
+		require.NoError(t, sender.Send(context.Background(), messaging.Message{To: "+6281234567890"}))
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+	assert.Len(t, fake.Sent(), 12)
+}
+
+func TestThrottleStopsOnCancel(t *testing.T) {
+	sender := messaging.Throttle(&messaging.Fake{}, 1)
+	require.NoError(t, sender.Send(context.Background(), messaging.Message{}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, sender.Send(ctx, messaging.Message{}), context.DeadlineExceeded)
+}
+
+func TestNotificationPreferencesDefaultToEmail(t *testing.T) {
+	prefs := models.NotificationPreferences{"booking_confirmed": {"whatsapp"}, "gallery_delivered": {}}
+	assert.Equal(t, []string{"whatsapp"}, prefs.Channels("booking_confirmed"))
+	assert.Empty(t, prefs.Channels("gallery_delivered"))
+	assert.Equal(t, []string{"email"}, prefs.Channels("payment_receipt"))
+
+	var none models.NotificationPreferences
+	assert.Equal(t, []string{"email"}, none.Channels("booking_created"))
+}
@@ -0,0 +1,62 @@
+package test
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"manajemen-fotografi-api/metrics"
+	"manajemen-fotografi-api/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupMetricsApp() *fiber.App {
+	app := fiber.New()
+	middlewares.SetupMetrics(app)
+	app.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
+	app.Get("/api/bookings/:id", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	return app
+}
+
+func scrapeMetrics(t *testing.T, app *fiber.App) string {
+	resp, err := app.Test(httptest.NewRequest("GET", "/metrics", nil))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
+
+func TestMetricsRecordsRoutePattern(t *testing.T) {
+	app := setupMetricsApp()
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/bookings/665f1c2e8a1b2c3d4e5f6789", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+
+	body := scrapeMetrics(t, app)
+	assert.Contains(t, body, `http_requests_total{method="GET",route="/api/bookings/:id",status="204"}`)
+	assert.False(t, strings.Contains(body, "665f1c2e8a1b2c3d4e5f6789"), "ID tidak boleh menjadi label")
+}
+
+func TestMetricsBusinessCounters(t *testing.T) {
+	app := setupMetricsApp()
+
+	metrics.BookingCreated("pending")
+	metrics.PaymentRecorded("transfer", "paid", 500000)
+	metrics.UserRegistered("client")
+
+	body := scrapeMetrics(t, app)
+	assert.Contains(t, body, `bookings_created_total{status="pending"}`)
+	assert.Contains(t, body, `payments_total{method="transfer",status="paid"}`)
+	assert.Contains(t, body, `user_registrations_total{role="client"}`)
+}
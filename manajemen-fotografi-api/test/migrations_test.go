@@ -0,0 +1,104 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"manajemen-fotografi-api/config"
+	"manajemen-fotografi-api/migrations"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestMigrationRegistry(t *testing.T) {
+	all := migrations.All()
+	assert.NotEmpty(t, all)
+
+	// Versi harus unik dan berurutan, tanpa nomor yang terlewat
+	for i, m := range all {
+		assert.Equal(t, i+1, m.Version, m.Name)
+		assert.NotEmpty(t, m.Name)
+		assert.NotNil(t, m.Up, m.Name)
+	}
+}
+
+// scratchDatabase membuat database kosong untuk menjalankan migrasi tertentu,
+// lalu menghapusnya setelah test selesai
+func scratchDatabase(t *testing.T) *mongo.Database {
+	config.GetCollection("users") // memastikan koneksi sudah dibuka
+	db := config.MongoClient.Database("migrations_test_" + primitive.NewObjectID().Hex())
+	t.Cleanup(func() { db.Drop(context.Background()) })
+	return db
+}
+
+func migration(t *testing.T, name string) migrations.Migration {
+	for _, m := range migrations.All() {
+		if m.Name == name {
+			return m
+		}
+	}
+	t.Fatalf("migrasi %s tidak terdaftar", name)
+	return migrations.Migration{}
+}
+
+func TestUniqueEmailMigrationReportsDuplicates(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	db := scratchDatabase(t)
+	users := db.Collection("users")
+
+	ayu := primitive.NewObjectID()
+	_, err := users.InsertMany(ctx, []interface{}{
+		bson.M{"_id": ayu, "name": "Ayu", "email": "Ayu@Mail.com"},
+		bson.M{"name": "Ayu Lama", "email": " ayu@mail.com"},
+		bson.M{"name": "Budi", "email": "budi@mail.com"},
+	})
+	require.NoError(t, err)
+
+	up := migration(t, "users_unique_email").Up
+	err = up(ctx, db)
+	require.ErrorIs(t, err, migrations.ErrDuplicates)
+	assert.Contains(t, err.Error(), "ayu@mail.com (2 dokumen)")
+
+	// Data belum diubah selama duplikat belum dirapikan
+	var stored bson.M
+	require.NoError(t, users.FindOne(ctx, bson.M{"_id": ayu}).Decode(&stored))
+	assert.Equal(t, "Ayu@Mail.com", stored["email"])
+
+	_, err = users.DeleteOne(ctx, bson.M{"name": "Ayu Lama"})
+	require.NoError(t, err)
+	require.NoError(t, up(ctx, db))
+	require.NoError(t, users.FindOne(ctx, bson.M{"_id": ayu}).Decode(&stored))
+	assert.Equal(t, "ayu@mail.com", stored["email"])
+
+	_, err = users.InsertOne(ctx, bson.M{"name": "Ayu Baru", "email": "ayu@mail.com"})
+	assert.True(t, mongo.IsDuplicateKeyError(err), "index unik terpasang")
+}
+
+func TestUniqueProfileMigrationReportsDuplicates(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	db := scratchDatabase(t)
+
+	userID := primitive.NewObjectID()
+	_, err := db.Collection("photographers").InsertMany(ctx, []interface{}{
+		bson.M{"user_id": userID, "phone": "+6281234567890"},
+		bson.M{"user_id": userID, "phone": "+6281234567891"},
+	})
+	require.NoError(t, err)
+
+	up := migration(t, "profiles_unique_user_id").Up
+	err = up(ctx, db)
+	require.ErrorIs(t, err, migrations.ErrDuplicates)
+	assert.Contains(t, err.Error(), "photographers.user_id")
+	assert.Contains(t, err.Error(), userID.Hex())
+
+	_, err = db.Collection("photographers").DeleteOne(ctx, bson.M{"phone": "+6281234567891"})
+	require.NoError(t, err)
+	assert.NoError(t, up(ctx, db))
+}
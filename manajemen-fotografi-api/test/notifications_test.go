@@ -0,0 +1,203 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	netmail "net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"manajemen-fotografi-api/mail"
+	"manajemen-fotografi-api/notifications"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderNotificationLocalized(t *testing.T) {
+	params := map[string]string{"name": "Ayu", "date": "17-08-2025 09:00 WIB", "location": "Bandung"}
+
+	id, err := notifications.Render("id", notifications.BookingConfirmed, params)
+	require.NoError(t, err)
+	assert.Equal(t, "Booking 17-08-2025 09:00 WIB dikonfirmasi", id.Subject)
+	assert.Contains(t, id.Text, "Halo Ayu,")
+	assert.Contains(t, id.Text, "di Bandung telah dikonfirmasi")
+	assert.Contains(t, id.HTML, `<html lang="id">`)
+
+	en, err := notifications.Render("en-US", notifications.BookingConfirmed, params)
+	require.NoError(t, err)
+	assert.Contains(t, en.Text, "Hi Ayu,")
+	assert.Contains(t, en.HTML, `<html lang="en">`)
+}
+
+func TestRenderNotificationEscapesHTML(t *testing.T) {
+	content, err := notifications.Render("id", notifications.BookingCreated, map[string]string{
+		"name": "Ayu", "date": "17-08-2025", "location": "<script>alert(1)</script>",
+	})
+	require.NoError(t, err)
+	assert.NotContains(t, content.HTML, "<script>")
+	assert.Contains(t, content.HTML, "&lt;script&gt;")
+	assert.Contains(t, content.Text, "<script>") // versi teks tidak di-escape
+}
+
+func TestRenderBookingMessageNotification(t *testing.T) {
+	content, err := notifications.Render("en", notifications.BookingMessage, map[string]string{
+		"name": "Ayu", "date": "17-08-2025 09:00 WIB", "location": "Bandung", "preview": "Bisa <b>mulai</b> jam 8?",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "New message about booking 17-08-2025 09:00 WIB", content.Subject)
+	assert.Contains(t, content.Short, `"Bisa <b>mulai</b> jam 8?"`)
+	assert.Contains(t, content.HTML, "&lt;b&gt;mulai&lt;/b&gt;")
+	assert.Contains(t, notifications.Kinds, notifications.BookingMessage)
+}
+
+func TestRenderUnknownNotification(t *testing.T) {
+	_, err := notifications.Render("id", "tidak_ada", nil)
+	assert.Error(t, err)
+}
+
+func TestNotificationBackoff(t *testing.T) {
+	assert.Equal(t, time.Minute, notifications.Backoff(1))
+	assert.Equal(t, 4*time.Minute, notifications.Backoff(3))
+	assert.Equal(t, time.Hour, notifications.Backoff(10))
+}
+
+// fakeSMTP adalah server SMTP minimal seperti MailHog yang menyimpan email terakhir
+type fakeSMTP struct {
+	addr     string
+	from, to string
+	data     chan string
+}
+
+func startFakeSMTP(t *testing.T) *fakeSMTP {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	s := &fakeSMTP{addr: ln.Addr().String(), data: make(chan string, 1)}
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "MAIL FROM:"):
+				s.from = strings.Trim(strings.TrimSpace(line)[10:], "<>")
+				reply("250 OK")
+			case strings.HasPrefix(cmd, "RCPT TO:"):
+				s.to = strings.Trim(strings.TrimSpace(line)[8:], "<>")
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var b strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil || l == ".\r\n" {
+						break
+					}
+					b.WriteString(l)
+				}
+				s.data <- b.String()
+				reply("250 OK")
+			case cmd == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return s
+}
+
+// parts membaca isi multipart/alternative per Content-Type
+func parts(t *testing.T, raw string) (*netmail.Message, map[string]string) {
+	msg, err := netmail.ReadMessage(strings.NewReader(raw))
+	require.NoError(t, err)
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	found := map[string]string{}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		body, _ := io.ReadAll(part) // quoted-printable sudah didekode oleh multipart
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		found[contentType] = string(body)
+	}
+	return msg, found
+}
+
+func TestSMTPMailerSendsMultipart(t *testing.T) {
+	server := startFakeSMTP(t)
+	mailer := &mail.SMTPMailer{Addr: server.addr, From: "Studio <studio@example.com>"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := mailer.Send(ctx, mail.Message{
+		To:      "ayu@example.com",
+		Subject: "Booking dikonfirmasi",
+		Text:    "Halo Ayu,",
+		HTML:    "<p>Halo Ayu,</p>",
+	})
+	require.NoError(t, err)
+
+	raw := <-server.data
+	assert.Equal(t, "studio@example.com", server.from)
+	assert.Equal(t, "ayu@example.com", server.to)
+
+	msg, body := parts(t, raw)
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	assert.Equal(t, "Booking dikonfirmasi", subject)
+	assert.Equal(t, "Halo Ayu,", body["text/plain"])
+	assert.Equal(t, "<p>Halo Ayu,</p>", body["text/html"])
+}
+
+func TestSMTPMailerRejectsInvalidAddress(t *testing.T) {
+	mailer := &mail.SMTPMailer{Addr: "127.0.0.1:1", From: "studio@example.com"}
+	err := mailer.Send(context.Background(), mail.Message{To: "bukan email"})
+	assert.Error(t, err)
+}
+
+func TestFileMailerWritesEML(t *testing.T) {
+	dir := t.TempDir()
+	mailer := &mail.FileMailer{Dir: dir, From: mail.DefaultFrom}
+
+	err := mailer.Send(context.Background(), mail.Message{To: "ayu@example.com", Subject: "Tes", Text: "teks", HTML: "<b>html</b>"})
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	raw, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	_, body := parts(t, string(raw))
+	assert.Equal(t, "teks", body["text/plain"])
+	assert.Equal(t, "<b>html</b>", body["text/html"])
+}
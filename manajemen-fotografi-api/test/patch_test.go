@@ -0,0 +1,76 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/patch"
+	"manajemen-fotografi-api/utils"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMergeFollowsRFC7396(t *testing.T) {
+	target := map[string]interface{}{
+		"a": "b",
+		"c": map[string]interface{}{"d": "e", "f": "g"},
+	}
+	p := map[string]interface{}{
+		"a": "z",
+		"c": map[string]interface{}{"f": nil},
+	}
+	assert.Equal(t, map[string]interface{}{
+		"a": "z",
+		"c": map[string]interface{}{"d": "e"},
+	}, patch.Merge(target, p))
+
+	// Array selalu diganti utuh, bukan di-merge
+	assert.Equal(t, []interface{}{"x"}, patch.Merge([]interface{}{"a", "b"}, []interface{}{"x"}))
+}
+
+func TestApplyDistinguishesAbsentNullAndValue(t *testing.T) {
+	booking := models.Booking{
+		ID:       primitive.NewObjectID(),
+		Date:     time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC),
+		Location: "Bandung",
+		Status:   models.BookingStatusPending,
+		Note:     "Bawa drone",
+	}
+	booking.Version = 3
+
+	fields, err := patch.Apply(&booking, []byte(`{"location": "Jakarta", "note": null}`),
+		"location", "note", "status")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"location", "note"}, fields)
+
+	assert.Equal(t, "Jakarta", booking.Location)
+	assert.Empty(t, booking.Note)
+	assert.Equal(t, models.BookingStatusPending, booking.Status)
+	assert.Equal(t, int64(3), booking.Version)
+	assert.True(t, booking.Date.Equal(time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)))
+
+	// note memakai omitempty sehingga nilai kosong dihapus dari dokumen
+	update, err := patch.Update(booking, fields...)
+	assert.NoError(t, err)
+	assert.Equal(t, bson.M{
+		"$set":   bson.M{"location": "Jakarta"},
+		"$unset": bson.M{"note": ""},
+	}, update)
+}
+
+func TestApplyRejectsInvalidPatch(t *testing.T) {
+	var client models.Client
+
+	_, err := patch.Apply(&client, []byte(`{"user_id": "x", "name": "Budi"}`), "name")
+	assert.ErrorIs(t, err, utils.ErrValidation)
+	assert.Equal(t, "user_id", utils.ToAppError(err).Details[0].Field)
+
+	_, err = patch.Apply(&client, []byte(`["name"]`), "name")
+	assert.ErrorIs(t, err, utils.ErrInvalidBody)
+
+	_, err = patch.Apply(&client, []byte(`{"name": 5}`), "name")
+	assert.ErrorIs(t, err, utils.ErrInvalidBody)
+}
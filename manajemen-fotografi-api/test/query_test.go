@@ -0,0 +1,224 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"manajemen-fotografi-api/query"
+	"manajemen-fotografi-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var testListSpec = query.Spec{
+	Filters: map[string]query.Filter{
+		"status":          {Field: "status", Type: query.String, Op: query.Eq},
+		"photographer_id": {Field: "photographer_id", Type: query.ObjectID, Op: query.Eq},
+		"date_from":       {Field: "date", Type: query.Time, Op: query.Gte},
+		"date_to":         {Field: "date", Type: query.Time, Op: query.Lte},
+		"name":            {Field: "name", Type: query.String, Op: query.Prefix},
+	},
+	Sorts:       []string{"created_at", "date"},
+	DefaultSort: "-created_at",
+}
+
+// parseApp mengembalikan query hasil parsing lewat endpoint uji
+func parseApp(t *testing.T, target string) (*query.Query, *utils.Response) {
+	var parsed *query.Query
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler})
+	app.Get("/list", func(c *fiber.Ctx) error {
+		q, err := query.Parse(c, testListSpec)
+		if err != nil {
+			return utils.Error(c, err)
+		}
+		parsed = q
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", target, nil))
+	assert.Nil(t, err)
+	if resp.StatusCode == fiber.StatusNoContent {
+		return parsed, nil
+	}
+	var body utils.Response
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	return nil, &body
+}
+
+func TestParseListQuery(t *testing.T) {
+	photographerID := primitive.NewObjectID()
+	q, _ := parseApp(t, "/list?status=pending,confirmed&photographer_id="+photographerID.Hex()+
+		"&date_from=2025-01-01&date_to=2025-01-31&sort=date&limit=500&page=2&unknown=x")
+
+	assert.Equal(t, query.MaxLimit, q.Limit)
+	assert.Equal(t, 2, q.Page)
+	assert.Equal(t, "date", q.SortField)
+	assert.False(t, q.SortDesc)
+	assert.Equal(t, bson.M{"$in": []interface{}{"pending", "confirmed"}}, q.Filter["status"])
+	assert.Equal(t, bson.M{"$eq": photographerID}, q.Filter["photographer_id"])
+	assert.Equal(t, bson.M{
+		"$gte": time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		"$lt":  time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+	}, q.Filter["date"])
+	assert.NotContains(t, q.Filter, "unknown")
+
+	// Default sort dan limit
+	q, _ = parseApp(t, "/list")
+	assert.Equal(t, "created_at", q.SortField)
+	assert.True(t, q.SortDesc)
+	assert.Equal(t, query.DefaultLimit, q.Limit)
+	assert.Empty(t, q.Filter)
+
+	// Karakter regex dari user di-escape
+	q, _ = parseApp(t, "/list?name=a.*")
+	assert.Equal(t, bson.M{"$regex": `^a\.\*`, "$options": "i"}, q.Filter["name"])
+}
+
+func TestParseListQueryErrors(t *testing.T) {
+	cases := map[string]string{
+		"/list?sort=password":        "sort",
+		"/list?limit=0":              "limit",
+		"/list?page=abc":             "page",
+		"/list?photographer_id=xyz":  "photographer_id",
+		"/list?date_from=31-01-2025": "date_from",
+		"/list?cursor=rusak!":        "cursor",
+		"/list?cursor=abc&page=2":    "cursor",
+	}
+	for target, field := range cases {
+		q, body := parseApp(t, target)
+		assert.Nil(t, q, target)
+		if assert.NotNil(t, body, target) {
+			assert.Equal(t, "VALIDATION_FAILED", body.Error.Code, target)
+			assert.Equal(t, field, body.Error.Details[0].Field, target)
+		}
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	id := primitive.NewObjectID()
+	token, err := query.EncodeCursor(query.Cursor{Value: "2025-01-01", ID: id})
+	assert.Nil(t, err)
+
+	cursor, err := query.DecodeCursor(token)
+	assert.Nil(t, err)
+	assert.Equal(t, "2025-01-01", cursor.Value)
+	assert.Equal(t, id, cursor.ID)
+}
+
+func TestLinkHeader(t *testing.T) {
+	app := fiber.New()
+	app.Get("/list", func(c *fiber.Ctx) error {
+		q, err := query.Parse(c, testListSpec)
+		if err != nil {
+			return err
+		}
+		meta := &utils.Meta{Page: q.Page, Limit: q.Limit, Total: 45}
+		query.SetLinks(c, q, meta)
+		return utils.SuccessList(c, []string{}, meta)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "http://api.test/list?status=pending&limit=10&page=2", nil))
+	assert.Nil(t, err)
+
+	link := resp.Header.Get("Link")
+	assert.Contains(t, link, `<http://api.test/list?status=pending&limit=10&page=1>; rel="first"`)
+	assert.Contains(t, link, `<http://api.test/list?status=pending&limit=10&page=1>; rel="prev"`)
+	assert.Contains(t, link, `<http://api.test/list?status=pending&limit=10&page=3>; rel="next"`)
+	assert.Contains(t, link, `<http://api.test/list?status=pending&limit=10&page=5>; rel="last"`)
+}
+
+// listFinder mengembalikan dokumen tetap tanpa database
+type listFinder struct {
+	docs []interface{}
+}
+
+func (f listFinder) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	return mongo.NewCursorFromDocuments(f.docs, nil, nil)
+}
+
+func (f listFinder) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	return int64(len(f.docs)), nil
+}
+
+func TestFindSetsNextCursorOnlyInCursorMode(t *testing.T) {
+	finder := listFinder{}
+	for i := 0; i < 3; i++ {
+		finder.docs = append(finder.docs, bson.M{"_id": primitive.NewObjectID(), "created_at": time.Now()})
+	}
+
+	paged, _ := parseApp(t, "/list?limit=2")
+	_, meta, err := query.Find[bson.M](context.Background(), finder, paged)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, meta.Page)
+	assert.Empty(t, meta.NextCursor)
+
+	// ?cursor tanpa nilai memulai pagination cursor dari awal
+	cursored, _ := parseApp(t, "/list?limit=2&cursor=")
+	if assert.NotNil(t, cursored.Cursor) {
+		assert.True(t, cursored.Cursor.ID.IsZero())
+	}
+	_, meta, err = query.Find[bson.M](context.Background(), finder, cursored)
+	assert.Nil(t, err)
+	assert.Zero(t, meta.Page)
+	assert.NotEmpty(t, meta.NextCursor)
+}
+
+func TestLinkHeaderHasSingleNext(t *testing.T) {
+	app := fiber.New()
+	app.Get("/list", func(c *fiber.Ctx) error {
+		q, err := query.Parse(c, testListSpec)
+		if err != nil {
+			return err
+		}
+		meta := &utils.Meta{Page: q.Page, Limit: q.Limit, Total: 45, NextCursor: "abc"}
+		query.SetLinks(c, q, meta)
+		return utils.SuccessList(c, []string{}, meta)
+	})
+
+	for target, next := range map[string]string{
+		"http://api.test/list?limit=10&page=2":  `<http://api.test/list?limit=10&page=3>; rel="next"`,
+		"http://api.test/list?limit=10&cursor=": `<http://api.test/list?limit=10&cursor=abc>; rel="next"`,
+	} {
+		resp, err := app.Test(httptest.NewRequest("GET", target, nil))
+		assert.Nil(t, err)
+		link := resp.Header.Get("Link")
+		assert.Equal(t, 1, strings.Count(link, `rel="next"`), target)
+		assert.Contains(t, link, next, target)
+	}
+}
+
+func TestDecodeCursorRejectsOperators(t *testing.T) {
+	id := primitive.NewObjectID()
+	for _, value := range []interface{}{
+		bson.M{"$ne": nil},
+		bson.A{"a", "b"},
+		primitive.Regex{Pattern: ".*"},
+	} {
+		token, err := query.EncodeCursor(query.Cursor{Value: value, ID: id})
+		assert.Nil(t, err)
+		_, err = query.DecodeCursor(token)
+		assert.ErrorIs(t, err, query.ErrCursorValue, "%v", value)
+	}
+
+	for _, value := range []interface{}{"Bandung", int64(3), 4.5, time.Now(), nil} {
+		token, err := query.EncodeCursor(query.Cursor{Value: value, ID: id})
+		assert.Nil(t, err)
+		_, err = query.DecodeCursor(token)
+		assert.Nil(t, err, "%v", value)
+	}
+
+	token, _ := query.EncodeCursor(query.Cursor{Value: bson.M{"$gt": ""}, ID: id})
+	_, body := parseApp(t, "/list?cursor="+token)
+	if assert.NotNil(t, body) {
+		assert.Equal(t, "cursor", body.Error.Details[0].Field)
+	}
+}
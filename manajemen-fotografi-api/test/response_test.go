@@ -0,0 +1,82 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"manajemen-fotografi-api/middlewares"
+	"manajemen-fotografi-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestErrorEnvelope(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler})
+	app.Get("/booking", func(c *fiber.Ctx) error {
+		return utils.Error(c, utils.ErrBookingNotFound)
+	})
+	app.Get("/validation", func(c *fiber.Ctx) error {
+		return utils.Error(c, utils.Invalid("status", "validation.booking_status"))
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/booking", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+	var body utils.Response
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "BOOKING_NOT_FOUND", body.Error.Code)
+	assert.Equal(t, "Booking tidak ditemukan", body.Error.Message)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/validation", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	body = utils.Response{}
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "VALIDATION_FAILED", body.Error.Code)
+	assert.Equal(t, []utils.FieldError{{Field: "status", Message: "Status booking tidak valid"}}, body.Error.Details)
+
+	// Route yang tidak ada tetap memakai envelope lewat ErrorHandler
+	resp, err = app.Test(httptest.NewRequest("GET", "/tidak-ada", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+	body = utils.Response{}
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "ROUTE_NOT_FOUND", body.Error.Code)
+}
+
+func TestPanicUsesErrorEnvelope(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: utils.ErrorHandler})
+	middlewares.SetupRecover(app)
+	app.Get("/panic", func(c *fiber.Ctx) error {
+		panic("nil map")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/panic", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+	var body utils.Response
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "INTERNAL_ERROR", body.Error.Code)
+	assert.NotContains(t, body.Error.Message, "nil map")
+}
+
+func TestToAppErrorMapping(t *testing.T) {
+	assert.Equal(t, fiber.StatusNotFound, utils.StatusOf(utils.NotFoundOr(mongo.ErrNoDocuments, utils.ErrGalleryNotFound)))
+	assert.Equal(t, "GALLERY_NOT_FOUND", utils.NotFoundOr(mongo.ErrNoDocuments, utils.ErrGalleryNotFound).Code)
+	assert.Equal(t, "DATABASE_ERROR", utils.NotFoundOr(errors.New("koneksi putus"), utils.ErrGalleryNotFound).Code)
+	assert.Equal(t, fiber.StatusInternalServerError, utils.StatusOf(errors.New("boom")))
+	assert.True(t, errors.Is(utils.ErrBookingNotFound.WithMessage("lain"), utils.ErrBookingNotFound))
+
+	duplicate := mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000, Message: "E11000 duplicate key error"}}}
+	assert.Equal(t, "EMAIL_ALREADY_REGISTERED", utils.DuplicateOr(duplicate, utils.ErrEmailTaken, utils.ErrDatabase).Code)
+	assert.Equal(t, fiber.StatusConflict, utils.StatusOf(utils.DuplicateOr(duplicate, utils.ErrEmailTaken, utils.ErrDatabase)))
+	assert.Equal(t, "DATABASE_ERROR", utils.DuplicateOr(errors.New("boom"), utils.ErrEmailTaken, utils.ErrDatabase).Code)
+}
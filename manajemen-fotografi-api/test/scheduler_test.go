@@ -0,0 +1,83 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/notifications"
+	"manajemen-fotografi-api/scheduler"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestReminderRunAt(t *testing.T) {
+	date := time.Date(2025, 8, 17, 9, 0, 0, 0, time.UTC)
+
+	// Dijadwalkan jauh hari: H-7 dan H-1 dikirim tepat waktu
+	now := date.AddDate(0, 0, -10)
+	runAt, ok := scheduler.ReminderRunAt(date, 7, now)
+	assert.True(t, ok)
+	assert.Equal(t, date.AddDate(0, 0, -7), runAt)
+	runAt, ok = scheduler.ReminderRunAt(date, 1, now)
+	assert.True(t, ok)
+	assert.Equal(t, date.AddDate(0, 0, -1), runAt)
+
+	// Sedikit terlambat (scheduler sempat mati) masih dikirim
+	_, ok = scheduler.ReminderRunAt(date, 7, date.AddDate(0, 0, -7).Add(time.Hour))
+	assert.True(t, ok)
+
+	// Booking dibuat 3 hari sebelum sesi: H-7 dilewati, H-1 tetap
+	now = date.AddDate(0, 0, -3)
+	_, ok = scheduler.ReminderRunAt(date, 7, now)
+	assert.False(t, ok)
+	_, ok = scheduler.ReminderRunAt(date, 1, now)
+	assert.True(t, ok)
+
+	// Tanggal sudah lewat
+	_, ok = scheduler.ReminderRunAt(date, 1, date.Add(time.Minute))
+	assert.False(t, ok)
+}
+
+func TestSchedulerBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, scheduler.Backoff(1))
+	assert.Equal(t, 2*time.Minute, scheduler.Backoff(3))
+	assert.Equal(t, time.Hour, scheduler.Backoff(30))
+}
+
+func TestBookingRulesFromEnv(t *testing.T) {
+	assert.Equal(t, scheduler.DefaultPaymentTimeout, scheduler.PaymentTimeout())
+
+	t.Setenv("BOOKING_PAYMENT_TIMEOUT", "6h")
+	t.Setenv("BOOKING_CLOSE_AFTER", "bukan-durasi")
+	assert.Equal(t, 6*time.Hour, scheduler.PaymentTimeout())
+	assert.Equal(t, scheduler.DefaultCloseAfter, scheduler.CloseAfter())
+}
+
+func TestDecodeJobPayload(t *testing.T) {
+	id := primitive.NewObjectID()
+	date := time.Date(2025, 8, 17, 9, 0, 0, 0, time.UTC)
+	job := models.Job{Payload: bson.M{"booking_id": id, "date": primitive.NewDateTimeFromTime(date), "days": int32(7)}}
+
+	var payload struct {
+		BookingID primitive.ObjectID `bson:"booking_id"`
+		Date      time.Time          `bson:"date"`
+		Days      int                `bson:"days"`
+	}
+	require.NoError(t, scheduler.DecodePayload(job, &payload))
+	assert.Equal(t, id, payload.BookingID)
+	assert.True(t, date.Equal(payload.Date))
+	assert.Equal(t, 7, payload.Days)
+}
+
+func TestRenderBookingReminder(t *testing.T) {
+	content, err := notifications.Render("id", notifications.BookingReminder, map[string]string{
+		"name": "Ayu", "days": "7", "date": "17-08-2025 16:00 WIB", "location": "Bandung",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Pengingat: booking 17-08-2025 16:00 WIB", content.Subject)
+	assert.Equal(t, "Booking Anda di Bandung tinggal 7 hari lagi, pada 17-08-2025 16:00 WIB.", content.Short)
+}
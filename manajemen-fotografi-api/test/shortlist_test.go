@@ -0,0 +1,266 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"manajemen-fotografi-api/auth"
+	"manajemen-fotografi-api/handlers"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/shortlist"
+	"manajemen-fotografi-api/utils"
+	"manajemen-fotografi-api/waitlist"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestShortlistShareToken(t *testing.T) {
+	token, hash, err := shortlist.NewToken()
+	require.NoError(t, err)
+	assert.Len(t, token, 43)
+	assert.Equal(t, shortlist.HashToken(token), hash)
+	assert.NotEqual(t, token, hash)
+
+	other, _, err := shortlist.NewToken()
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other)
+}
+
+func TestShortlistPhotographerIDs(t *testing.T) {
+	a, b := primitive.NewObjectID(), primitive.NewObjectID()
+	gallery := primitive.NewObjectID()
+	items := []models.ShortlistItem{
+		{Kind: models.ShortlistGalleryImage, PhotographerID: b, GalleryID: &gallery},
+		{Kind: models.ShortlistPhotographer, PhotographerID: a},
+		{Kind: models.ShortlistPhotographer, PhotographerID: b},
+		{Kind: models.ShortlistGalleryImage, PhotographerID: a, GalleryID: &gallery},
+	}
+
+	assert.Equal(t, []primitive.ObjectID{b, a}, shortlist.PhotographerIDs(items, ""))
+	assert.Equal(t, []primitive.ObjectID{a, b}, shortlist.PhotographerIDs(items, models.ShortlistPhotographer))
+	assert.Empty(t, shortlist.PhotographerIDs(nil, ""))
+}
+
+func TestShortlistNameValidation(t *testing.T) {
+	assert.NoError(t, utils.Validate(models.Shortlist{Name: "Prewedding"}))
+
+	err := utils.Validate(models.Shortlist{Name: "   "})
+	require.Error(t, err)
+	assert.Equal(t, "name", utils.ToAppError(err).Details[0].Field)
+}
+
+// shortlistFixture menyimpan fotografer dan shortlist yang dibuat test lalu menghapusnya
+type shortlistFixture struct {
+	t             *testing.T
+	ctx           context.Context
+	location      string
+	photographers []primitive.ObjectID
+	clients       []primitive.ObjectID
+}
+
+func newShortlistFixture(t *testing.T, ctx context.Context) *shortlistFixture {
+	f := &shortlistFixture{t: t, ctx: ctx, location: "Shortlist " + primitive.NewObjectID().Hex()}
+	t.Cleanup(func() {
+		ctx := context.Background()
+		repositories.Unscoped("photographers").DeleteMany(ctx, bson.M{"_id": bson.M{"$in": f.photographers}})
+		repositories.Unscoped("bookings").DeleteMany(ctx, bson.M{"photographer_id": bson.M{"$in": f.photographers}})
+		repositories.Unscoped("clients").DeleteMany(ctx, bson.M{"_id": bson.M{"$in": f.clients}})
+		repositories.Unscoped(shortlist.Collection).DeleteMany(ctx, bson.M{"client_id": bson.M{"$in": f.clients}})
+	})
+	return f
+}
+
+func (f *shortlistFixture) photographer() primitive.ObjectID {
+	p := models.Photographer{
+		ID:       primitive.NewObjectID(),
+		UserID:   primitive.NewObjectID(),
+		Phone:    "+6281234567890",
+		Location: f.location,
+	}
+	_, err := repositories.New("photographers").InsertOne(f.ctx, p)
+	require.NoError(f.t, err)
+	f.photographers = append(f.photographers, p.ID)
+	return p.ID
+}
+
+func (f *shortlistFixture) client(userID primitive.ObjectID) primitive.ObjectID {
+	c := models.Client{ID: primitive.NewObjectID(), UserID: userID, Name: "Client Shortlist", Phone: "081234567890"}
+	_, err := repositories.New("clients").InsertOne(f.ctx, c)
+	require.NoError(f.t, err)
+	f.clients = append(f.clients, c.ID)
+	return c.ID
+}
+
+func (f *shortlistFixture) list(clientID primitive.ObjectID, photographerIDs ...primitive.ObjectID) models.Shortlist {
+	list := models.Shortlist{ID: primitive.NewObjectID(), ClientID: clientID, Name: "Favorit", Items: []models.ShortlistItem{}}
+	_, err := repositories.New(shortlist.Collection).InsertOne(f.ctx, list)
+	require.NoError(f.t, err)
+	for _, id := range photographerIDs {
+		list, err = shortlist.AddItem(f.ctx, list.ID, clientID,
+			models.ShortlistItem{Kind: models.ShortlistPhotographer, PhotographerID: id}, time.Now())
+		require.NoError(f.t, err)
+	}
+	return list
+}
+
+func (f *shortlistFixture) storedCount(id primitive.ObjectID) int {
+	var p models.Photographer
+	require.NoError(f.t, repositories.New("photographers").FindOne(f.ctx, bson.M{"_id": id}).Decode(&p))
+	return p.FavoriteCount
+}
+
+func TestShortlistFavoriteCountsDistinctClients(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	f := newShortlistFixture(t, ctx)
+
+	popular := f.photographer()
+	newer := f.photographer()
+	ayu, budi, citra := f.client(primitive.NewObjectID()), f.client(primitive.NewObjectID()), f.client(primitive.NewObjectID())
+
+	// Satu client yang menyimpan fotografer di dua shortlist tetap dihitung satu
+	first := f.list(ayu, popular)
+	second := f.list(ayu, popular)
+	f.list(budi, popular)
+	f.list(citra, newer)
+
+	count, err := shortlist.FavoriteCount(ctx, popular)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 2, f.storedCount(popular))
+	assert.Equal(t, 1, f.storedCount(newer))
+
+	// Item yang sama ditolak di shortlist yang sama
+	_, err = shortlist.AddItem(ctx, first.ID, ayu, models.ShortlistItem{Kind: models.ShortlistPhotographer, PhotographerID: popular}, time.Now())
+	assert.ErrorIs(t, err, utils.ErrShortlistItemExists)
+
+	// Ayu masih menyimpan fotografer di shortlist kedua
+	_, err = shortlist.RemoveItem(ctx, first.ID, ayu, first.Items[0].ID, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.storedCount(popular))
+
+	require.NoError(t, shortlist.Delete(ctx, second.ID, ayu))
+	assert.Equal(t, 1, f.storedCount(popular))
+}
+
+func TestPhotographerSearchRanksByFavorites(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	f := newShortlistFixture(t, ctx)
+
+	popular := f.photographer()
+	newer := f.photographer()
+	f.list(f.client(primitive.NewObjectID()), popular, newer)
+	f.list(f.client(primitive.NewObjectID()), popular)
+
+	app := fiber.New()
+	app.Get("/photographers", handlers.GetAllPhotographers)
+	resp, err := app.Test(httptest.NewRequest("GET", "/photographers?location="+url.QueryEscape(f.location), nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Data []models.Photographer `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body.Data, 2)
+	// Fotografer yang lebih baru kalah peringkat dari yang lebih sering difavoritkan
+	assert.Equal(t, []primitive.ObjectID{popular, newer}, []primitive.ObjectID{body.Data[0].ID, body.Data[1].ID})
+	assert.Equal(t, 2, body.Data[0].FavoriteCount)
+}
+
+func TestShortlistAvailability(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	f := newShortlistFixture(t, ctx)
+
+	free, booked := f.photographer(), f.photographer()
+	day := waitlist.Day(time.Now()).AddDate(0, 0, 14)
+	_, err := repositories.New("bookings").InsertOne(ctx, models.Booking{
+		ID:             primitive.NewObjectID(),
+		ClientID:       primitive.NewObjectID(),
+		PhotographerID: booked,
+		Date:           day.Add(9 * time.Hour),
+		Status:         models.BookingStatusConfirmed,
+		CreatedAt:      time.Now(),
+	})
+	require.NoError(t, err)
+
+	gallery := primitive.NewObjectID()
+	items := []models.ShortlistItem{
+		{Kind: models.ShortlistPhotographer, PhotographerID: free},
+		{Kind: models.ShortlistGalleryImage, PhotographerID: booked, GalleryID: &gallery},
+		{Kind: models.ShortlistPhotographer, PhotographerID: booked},
+	}
+	require.NoError(t, shortlist.Availability(ctx, items, day))
+	assert.True(t, *items[0].Available)
+	assert.False(t, *items[1].Available)
+	assert.False(t, *items[2].Available)
+
+	// Hari berikutnya fotografer kosong lagi
+	require.NoError(t, shortlist.Availability(ctx, items, day.AddDate(0, 0, 1)))
+	assert.True(t, *items[2].Available)
+}
+
+func TestSharedShortlistClosedAfterRevoke(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	f := newShortlistFixture(t, ctx)
+
+	userID := primitive.NewObjectID()
+	list := f.list(f.client(userID), f.photographer())
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		if c.Get("X-Test-User") != "" {
+			c.SetUserContext(auth.WithActor(c.UserContext(), auth.Actor{UserID: userID, Role: models.RoleClient}))
+		}
+		return c.Next()
+	})
+	app.Get("/api/shortlists/shared/:token", handlers.GetSharedShortlist)
+	app.Post("/api/shortlists/:id/share", handlers.ShareShortlist)
+	app.Delete("/api/shortlists/:id/share", handlers.RevokeShortlistShare)
+
+	call := func(method, path string, owner bool) *http.Response {
+		req := httptest.NewRequest(method, path, nil)
+		if owner {
+			req.Header.Set("X-Test-User", "1")
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := call("POST", "/api/shortlists/"+list.ID.Hex()+"/share", true)
+	require.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	var created struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	require.NotEmpty(t, created.Data.Token)
+
+	shared := "/api/shortlists/shared/" + created.Data.Token
+	resp = call("GET", shared, false)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var view struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&view))
+	assert.Equal(t, "Favorit", view.Data["name"])
+	assert.NotContains(t, view.Data, "client_id")
+
+	assert.Equal(t, fiber.StatusOK, call("DELETE", "/api/shortlists/"+list.ID.Hex()+"/share", true).StatusCode)
+	assert.Equal(t, fiber.StatusNotFound, call("GET", shared, false).StatusCode)
+}
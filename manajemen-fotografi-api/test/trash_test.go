@@ -0,0 +1,135 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"manajemen-fotografi-api/auth"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/storage"
+	"manajemen-fotografi-api/trash"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// recordingCollection mencatat filter dan update yang diterima, tanpa database
+type recordingCollection struct {
+	repositories.Collection
+	filters []interface{}
+	updates []interface{}
+}
+
+func (r *recordingCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	r.filters = append(r.filters, filter)
+	return 0, nil
+}
+
+func (r *recordingCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	r.filters = append(r.filters, filter)
+	r.updates = append(r.updates, update)
+	return &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil
+}
+
+func TestSoftDeleteScopesQueries(t *testing.T) {
+	rec := &recordingCollection{}
+	coll := repositories.WithSoftDelete(rec)
+	id := primitive.NewObjectID()
+
+	coll.CountDocuments(context.Background(), bson.M{"_id": id})
+	assert.Equal(t, bson.M{"_id": id, "deleted_at": nil}, rec.filters[0])
+
+	coll.CountDocuments(context.Background(), bson.D{{Key: "_id", Value: id}})
+	assert.Equal(t, bson.M{"$and": bson.A{bson.D{{Key: "_id", Value: id}}, repositories.NotDeleted}}, rec.filters[1])
+}
+
+func TestSoftDeleteMarksDocument(t *testing.T) {
+	rec := &recordingCollection{}
+	coll := repositories.WithSoftDelete(rec)
+
+	actor := auth.Actor{UserID: primitive.NewObjectID(), Role: models.RolePhotographer}
+	deletedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx := repositories.WithDeletedAt(auth.WithActor(context.Background(), actor), deletedAt)
+
+	id := primitive.NewObjectID()
+	result, err := coll.DeleteOne(ctx, bson.M{"_id": id})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), result.DeletedCount)
+	assert.Equal(t, bson.M{"_id": id, "deleted_at": nil}, rec.filters[0])
+	assert.Equal(t, bson.M{"$set": bson.M{"deleted_at": deletedAt, "deleted_by": actor.UserID}}, rec.updates[0])
+}
+
+func TestSoftDeleteFieldsInJSON(t *testing.T) {
+	data, _ := json.Marshal(models.Gallery{Title: "Prewedding"})
+	assert.NotContains(t, string(data), "deleted_at")
+
+	now := time.Now()
+	data, _ = json.Marshal(models.Gallery{Title: "Prewedding", SoftDelete: models.SoftDelete{DeletedAt: &now}})
+	assert.Contains(t, string(data), `"deleted_at"`)
+}
+
+func TestLocalStorageDelete(t *testing.T) {
+	dir := t.TempDir()
+	s := storage.NewLocalStorage(dir, "/uploads")
+
+	path := filepath.Join(dir, "1_foto.jpg")
+	assert.Nil(t, os.WriteFile(path, []byte("x"), 0o644))
+
+	assert.Nil(t, s.Delete(context.Background(), "/uploads/1_foto.jpg"))
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	// File yang sudah tidak ada dan URL luar diabaikan
+	assert.Nil(t, s.Delete(context.Background(), "/uploads/1_foto.jpg"))
+	assert.Nil(t, s.Delete(context.Background(), "https://cdn.example.com/foto.jpg"))
+}
+
+func TestTrashRetention(t *testing.T) {
+	assert.Equal(t, trash.DefaultRetention, trash.Retention())
+
+	t.Setenv("TRASH_RETENTION", "48h")
+	assert.Equal(t, 48*time.Hour, trash.Retention())
+}
+
+func TestPurgeKeepsUploadsReferencedByGallery(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dir := t.TempDir()
+	original := storage.Default
+	storage.Default = storage.NewLocalStorage(dir, "/uploads")
+	defer func() { storage.Default = original }()
+
+	// File milik dokumen lain, misal foto profil fotografer
+	path := filepath.Join(dir, "1700000000_profil.jpg")
+	assert.NoError(t, os.WriteFile(path, []byte("foto"), 0o644))
+
+	deletedAt := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+	gallery := models.Gallery{
+		ID:             primitive.NewObjectID(),
+		PhotographerID: primitive.NewObjectID(),
+		Title:          "Galeri purge",
+		ImageURL:       "/uploads/1700000000_profil.jpg",
+		CreatedAt:      deletedAt,
+		SoftDelete:     models.SoftDelete{DeletedAt: &deletedAt},
+	}
+	_, err := repositories.Unscoped("galleries").InsertOne(ctx, gallery)
+	assert.NoError(t, err)
+	defer repositories.Unscoped("galleries").DeleteOne(context.Background(), bson.M{"_id": gallery.ID})
+
+	purged := trash.Purge(ctx, deletedAt.Add(time.Hour))
+	assert.GreaterOrEqual(t, purged["galleries"], 1)
+
+	n, err := repositories.Unscoped("galleries").CountDocuments(ctx, bson.M{"_id": gallery.ID})
+	assert.NoError(t, err)
+	assert.Zero(t, n, "galeri terhapus permanen")
+	assert.FileExists(t, path, "file yang hanya dirujuk galeri tidak ikut dihapus")
+}
@@ -3,14 +3,18 @@ package test
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"manajemen-fotografi-api/config"
 	"manajemen-fotografi-api/handlers"
+	"manajemen-fotografi-api/i18n"
+	"manajemen-fotografi-api/middlewares"
 	"manajemen-fotografi-api/models"
 	"net/http"
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/session"
+	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson"
 	"context"
 	"time"
@@ -91,3 +95,54 @@ func TestLogoutUser(t *testing.T) {
 		t.Errorf("Expected status 200, got %d", resp.StatusCode)
 	}
 }
+
+func TestLanguagePreferenceAppliedAfterLogin(t *testing.T) {
+	app := fiber.New()
+	middlewares.SetupLocale(app)
+	middlewares.SetupSession(app)
+	app.Post("/register", handlers.RegisterUser)
+	app.Post("/login", handlers.LoginUser)
+	app.Get("/lang", func(c *fiber.Ctx) error {
+		return c.SendString(i18n.FromContext(c.UserContext()))
+	})
+
+	email := "english-user@example.com"
+	cleanupTestUser(email)
+	defer cleanupTestUser(email)
+
+	post := func(path string, body map[string]string) *http.Response {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", "id")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		return resp
+	}
+	post("/register", map[string]string{
+		"name": "English User", "email": email, "password": "password123",
+		"role": models.RoleClient, "language": "en",
+	})
+	login := post("/login", map[string]string{"email": email, "password": "password123"})
+	assert.Equal(t, fiber.StatusOK, login.StatusCode)
+	assert.Equal(t, "en", login.Header.Get(fiber.HeaderContentLanguage))
+
+	get := func(path string) string {
+		req, _ := http.NewRequest("GET", path, nil)
+		req.Header.Set("Accept-Language", "id")
+		for _, cookie := range login.Cookies() {
+			req.AddCookie(cookie)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+	// Request berikutnya tetap memakai preferensi user, ?lang= tetap didahulukan
+	assert.Equal(t, "en", get("/lang"))
+	assert.Equal(t, "id", get("/lang?lang=id"))
+}
@@ -0,0 +1,116 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/utils"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fieldCodes mengubah detail error validasi menjadi map field -> kode aturan
+func fieldCodes(t *testing.T, err error) map[string]string {
+	var appErr *utils.AppError
+	if !errors.As(err, &appErr) {
+		t.Fatalf("Expected AppError, got %v", err)
+	}
+	assert.Equal(t, "VALIDATION_FAILED", appErr.Code)
+
+	codes := map[string]string{}
+	for _, d := range appErr.Details {
+		codes[d.Field] = d.Code
+	}
+	return codes
+}
+
+func TestValidateReturnsAllFieldErrors(t *testing.T) {
+	err := utils.Validate(models.Client{Name: "Al", Phone: "12345", Address: "Jl"})
+
+	codes := fieldCodes(t, err)
+	assert.Equal(t, map[string]string{
+		"user_id": "objectid",
+		"name":    "person_name",
+		"phone":   "phone_id",
+		"address": "min",
+	}, codes)
+}
+
+func TestValidatePartialSkipsAbsentFields(t *testing.T) {
+	// Hanya phone yang dikirim, jadi user_id/name/address tidak wajib
+	assert.Nil(t, utils.ValidatePartial(models.Client{Phone: "081234567890"}))
+
+	codes := fieldCodes(t, utils.ValidatePartial(models.Client{Address: "Jl"}))
+	assert.Equal(t, map[string]string{"address": "min"}, codes)
+}
+
+func TestValidateBookingStatus(t *testing.T) {
+	booking := models.Booking{
+		ClientID:       primitive.NewObjectID(),
+		PhotographerID: primitive.NewObjectID(),
+		Date:           time.Now().Add(24 * time.Hour),
+		Status:         models.BookingStatusPending,
+	}
+	assert.Nil(t, utils.Validate(booking))
+
+	booking.Status = "selesai"
+	assert.Equal(t, map[string]string{"status": "booking_status"}, fieldCodes(t, utils.Validate(booking)))
+}
+
+func TestValidatePaymentMethodFromEnv(t *testing.T) {
+	trx := models.Transaction{BookingID: primitive.NewObjectID(), Method: "qris", Total: 500000}
+	assert.Equal(t, map[string]string{"method": "payment_method"}, fieldCodes(t, utils.Validate(trx)))
+
+	t.Setenv("PAYMENT_METHODS", "transfer, ewallet, QRIS")
+	assert.Equal(t, []string{"transfer", "ewallet", "qris"}, utils.PaymentMethods())
+	assert.NoError(t, utils.Validate(trx))
+}
+
+func TestIsValidPhone(t *testing.T) {
+	for _, phone := range []string{"081234567890", "+6281234567890", "6281234567890", "0215551234"} {
+		assert.True(t, utils.IsValidPhone(phone), phone)
+	}
+	for _, phone := range []string{"", "12345", "0012345678", "08123abc890"} {
+		assert.False(t, utils.IsValidPhone(phone), phone)
+	}
+}
+
+func TestIsURLOrPath(t *testing.T) {
+	for _, value := range []string{"/uploads/1_foto.jpg", "https://cdn.example.com/foto.jpg", "http://localhost:3000/uploads/a.png"} {
+		assert.True(t, utils.IsURLOrPath(value), value)
+	}
+	for _, value := range []string{"", "foto.jpg", "//evil.example.com/a.jpg", "ftp://example.com/a.jpg", "javascript:alert(1)"} {
+		assert.False(t, utils.IsURLOrPath(value), value)
+	}
+
+	// Gambar galeri dari storage lokal tersimpan sebagai path relatif
+	gallery := models.Gallery{PhotographerID: primitive.NewObjectID(), Title: "Prewedding", ImageURL: "/uploads/1_foto.jpg"}
+	assert.NoError(t, utils.Validate(gallery))
+}
+
+func TestNormalizePhone(t *testing.T) {
+	for _, phone := range []string{"081234567890", "6281234567890", "+6281234567890"} {
+		assert.Equal(t, "+6281234567890", utils.NormalizePhone(phone), phone)
+	}
+	assert.Equal(t, "+62215551234", utils.NormalizePhone("0215551234"))
+}
+
+func TestValidateNotificationPreferences(t *testing.T) {
+	valid := models.User{Notifications: models.NotificationPreferences{
+		"booking_confirmed": {"email", "whatsapp"},
+		"booking_message":   {"whatsapp"},
+		"gallery_delivered": {},
+	}}
+	assert.Nil(t, utils.ValidateFields(valid, "Notifications"))
+
+	for _, prefs := range []models.NotificationPreferences{
+		{"booking_confirmed": {"telegram"}},
+		{"tidak_ada": {"email"}},
+		{"booking_created": {"sms", "sms"}},
+	} {
+		assert.NotNil(t, utils.ValidateFields(models.User{Notifications: prefs}, "Notifications"), prefs)
+	}
+}
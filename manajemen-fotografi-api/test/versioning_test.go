@@ -0,0 +1,78 @@
+package test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestIfMatch(t *testing.T) {
+	app := fiber.New()
+	app.Put("/", func(c *fiber.Ctx) error {
+		version, err := utils.IfMatch(c)
+		if err != nil {
+			return utils.Error(c, err)
+		}
+		return c.JSON(version)
+	})
+
+	cases := []struct {
+		header string
+		status int
+		body   string
+	}{
+		{"", fiber.StatusPreconditionRequired, ""},
+		{`"3"`, fiber.StatusOK, "3"},
+		{`W/"3"`, fiber.StatusOK, "3"},
+		{"*", fiber.StatusOK, "-1"},
+		{"3", fiber.StatusPreconditionFailed, ""},
+		{`"abc"`, fiber.StatusPreconditionFailed, ""},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest("PUT", "/", nil)
+		if tc.header != "" {
+			req.Header.Set("If-Match", tc.header)
+		}
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.status, resp.StatusCode, tc.header)
+		if tc.body != "" {
+			buf := make([]byte, 8)
+			n, _ := resp.Body.Read(buf)
+			assert.Equal(t, tc.body, string(buf[:n]), tc.header)
+		}
+	}
+
+	assert.Equal(t, `"7"`, utils.ETag(7))
+}
+
+func TestVersioningBumpsEveryUpdate(t *testing.T) {
+	rec := &recordingCollection{}
+	coll := repositories.WithVersioning(rec)
+	id := primitive.NewObjectID()
+
+	coll.UpdateOne(context.Background(), bson.M{"_id": id}, bson.M{"$set": bson.M{"status": "done"}})
+	assert.Equal(t, bson.M{
+		"$set": bson.M{"status": "done"},
+		"$inc": bson.M{"version": 1},
+	}, rec.updates[0])
+
+	// Soft delete juga mengubah dokumen, jadi ETag lama harus ikut tidak berlaku
+	softDeleted := repositories.WithSoftDelete(coll)
+	softDeleted.DeleteOne(context.Background(), bson.M{"_id": id})
+	update := rec.updates[1].(bson.M)
+	assert.Equal(t, bson.M{"version": 1}, update["$inc"])
+}
+
+func TestVersionFilterMatchesLegacyDocuments(t *testing.T) {
+	assert.Equal(t, int64(2), repositories.VersionFilter(2))
+	assert.Equal(t, bson.M{"$in": bson.A{0, nil}}, repositories.VersionFilter(0))
+}
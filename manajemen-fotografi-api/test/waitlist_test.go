@@ -0,0 +1,237 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"manajemen-fotografi-api/events"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/notifications"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
+	"manajemen-fotografi-api/waitlist"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestWaitlistDayUsesWIB(t *testing.T) {
+	// 20:00 UTC sudah hari berikutnya di WIB
+	day := waitlist.Day(time.Date(2026, 9, 11, 20, 0, 0, 0, time.UTC))
+	assert.Equal(t, "2026-09-12T00:00:00+07:00", day.Format(time.RFC3339))
+	assert.Equal(t, day, waitlist.Day(day.Add(23*time.Hour)))
+}
+
+func TestWaitlistOfferExpiry(t *testing.T) {
+	day := time.Date(2026, 9, 12, 0, 0, 0, 0, waitlist.Location)
+
+	now := day.AddDate(0, 0, -3)
+	assert.Equal(t, now.Add(24*time.Hour), waitlist.OfferExpiry(now, day, 24*time.Hour))
+
+	// Tawaran pada hari H tidak melewati akhir hari
+	now = day.Add(20 * time.Hour)
+	assert.Equal(t, day.AddDate(0, 0, 1), waitlist.OfferExpiry(now, day, 24*time.Hour))
+}
+
+func TestWaitlistOfferTTLFromEnv(t *testing.T) {
+	t.Setenv("WAITLIST_OFFER_TTL", "6h")
+	assert.Equal(t, 6*time.Hour, waitlist.OfferTTL())
+
+	t.Setenv("WAITLIST_OFFER_TTL", "besok")
+	assert.Equal(t, waitlist.DefaultOfferTTL, waitlist.OfferTTL())
+}
+
+func TestRenderWaitlistOfferNotification(t *testing.T) {
+	content, err := notifications.Render("id", notifications.WaitlistOffer, map[string]string{
+		"name": "Ayu", "date": "12-09-2026", "expires": "10-09-2026 14:00 WIB",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Tanggal 12-09-2026 tersedia untuk Anda", content.Subject)
+	assert.Contains(t, content.Short, "10-09-2026 14:00 WIB")
+	assert.Contains(t, notifications.Kinds, notifications.WaitlistOffer)
+
+	prefs := models.User{Notifications: models.NotificationPreferences{"waitlist_offer": {"whatsapp", "sms"}}}
+	assert.Nil(t, utils.ValidateFields(prefs, "Notifications"))
+}
+
+// waitlistDay menyiapkan tanggal fotografer yang penuh oleh satu booking confirmed,
+// lalu dua client masuk antrean berurutan
+func waitlistDay(t *testing.T, ctx context.Context) (booking models.Booking, first, second models.WaitlistEntry) {
+	now := time.Now()
+	booking = models.Booking{
+		ID:             primitive.NewObjectID(),
+		ClientID:       primitive.NewObjectID(),
+		PhotographerID: primitive.NewObjectID(),
+		Date:           waitlist.Day(now).AddDate(0, 0, 30).Add(10 * time.Hour),
+		Status:         models.BookingStatusConfirmed,
+		CreatedAt:      now,
+	}
+	_, err := repositories.New("bookings").InsertOne(ctx, booking)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		ctx := context.Background()
+		repositories.Unscoped("bookings").DeleteMany(ctx, bson.M{"photographer_id": booking.PhotographerID})
+		repositories.Unscoped(waitlist.Collection).DeleteMany(ctx, bson.M{"photographer_id": booking.PhotographerID})
+	})
+
+	first, err = waitlist.Join(ctx, booking.PhotographerID, primitive.NewObjectID(), booking.Date, now)
+	require.NoError(t, err)
+	second, err = waitlist.Join(ctx, booking.PhotographerID, primitive.NewObjectID(), booking.Date, now.Add(time.Second))
+	require.NoError(t, err)
+	return booking, first, second
+}
+
+// cancelBooking membatalkan booking lalu mengirim event perubahan status ke waitlist
+func cancelBooking(t *testing.T, ctx context.Context, booking models.Booking) {
+	_, err := repositories.Unscoped("bookings").UpdateOne(ctx, bson.M{"_id": booking.ID},
+		bson.M{"$set": bson.M{"status": models.BookingStatusCancelled}})
+	require.NoError(t, err)
+
+	booking.Status = models.BookingStatusCancelled
+	waitlist.HandleEvent(ctx, models.Event{
+		ID:   primitive.NewObjectID(),
+		Type: events.BookingStatusChanged,
+		Data: bson.M{"from": models.BookingStatusConfirmed, "to": models.BookingStatusCancelled, "booking": booking},
+	})
+}
+
+func waitlistEntry(t *testing.T, ctx context.Context, id primitive.ObjectID) models.WaitlistEntry {
+	var entry models.WaitlistEntry
+	require.NoError(t, repositories.New(waitlist.Collection).FindOne(ctx, bson.M{"_id": id}).Decode(&entry))
+	return entry
+}
+
+func TestWaitlistJoinRequiresFullDate(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	booking, first, _ := waitlistDay(t, ctx)
+	_, err := waitlist.Join(ctx, booking.PhotographerID, first.ClientID, booking.Date, time.Now())
+	assert.ErrorIs(t, err, utils.ErrWaitlistJoined)
+
+	_, err = waitlist.Join(ctx, booking.PhotographerID, primitive.NewObjectID(), booking.Date.AddDate(0, 0, 1), time.Now())
+	assert.ErrorIs(t, err, utils.ErrWaitlistDateAvailable)
+}
+
+func TestWaitlistOffersCancelledDateAndHoldsIt(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	booking, first, second := waitlistDay(t, ctx)
+	cancelBooking(t, ctx, booking)
+
+	offered := waitlistEntry(t, ctx, first.ID)
+	assert.Equal(t, models.WaitlistOffered, offered.Status)
+	require.NotNil(t, offered.OfferExpiresAt)
+	assert.Equal(t, models.WaitlistWaiting, waitlistEntry(t, ctx, second.ID).Status)
+
+	// Selama tawaran berjalan, hanya client yang ditawari yang boleh booking
+	attempt := models.Booking{PhotographerID: booking.PhotographerID, Date: booking.Date, Status: models.BookingStatusPending}
+	attempt.ClientID = second.ClientID
+	assert.ErrorIs(t, waitlist.Hold(ctx, attempt), utils.ErrBookingSlotHeld)
+	attempt.ClientID = first.ClientID
+	assert.NoError(t, waitlist.Hold(ctx, attempt))
+
+	attempt.ID = primitive.NewObjectID()
+	require.NoError(t, waitlist.Accept(ctx, attempt, time.Now()))
+	booked := waitlistEntry(t, ctx, first.ID)
+	assert.Equal(t, models.WaitlistBooked, booked.Status)
+	assert.Equal(t, &attempt.ID, booked.BookingID)
+	assert.False(t, booked.Active)
+}
+
+func TestWaitlistDeclinedOfferMovesToNextClient(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	booking, first, second := waitlistDay(t, ctx)
+	cancelBooking(t, ctx, booking)
+
+	left, err := waitlist.Leave(ctx, first.ID, first.ClientID, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, models.WaitlistDeclined, left.Status)
+	assert.Equal(t, models.WaitlistOffered, waitlistEntry(t, ctx, second.ID).Status)
+
+	_, err = waitlist.Leave(ctx, first.ID, first.ClientID, time.Now())
+	assert.ErrorIs(t, err, utils.ErrWaitlistClosed)
+}
+
+func TestWaitlistSweepExpiresOfferAndMovesToNextClient(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	booking, first, second := waitlistDay(t, ctx)
+	cancelBooking(t, ctx, booking)
+	require.Equal(t, models.WaitlistOffered, waitlistEntry(t, ctx, first.ID).Status)
+
+	// Sweep sebelum batas tawaran tidak mengubah apa pun
+	require.NoError(t, waitlist.Sweep(ctx, time.Now()))
+	assert.Equal(t, models.WaitlistOffered, waitlistEntry(t, ctx, first.ID).Status)
+	assert.Equal(t, models.WaitlistWaiting, waitlistEntry(t, ctx, second.ID).Status)
+
+	require.NoError(t, waitlist.Sweep(ctx, time.Now().Add(waitlist.OfferTTL()+time.Minute)))
+	assert.Equal(t, models.WaitlistExpired, waitlistEntry(t, ctx, first.ID).Status)
+	assert.Equal(t, models.WaitlistOffered, waitlistEntry(t, ctx, second.ID).Status)
+}
+
+func TestWaitlistNotOfferedWhileDateStillFull(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	booking, first, _ := waitlistDay(t, ctx)
+	require.NoError(t, waitlist.Advance(ctx, booking.PhotographerID, waitlist.Day(booking.Date), time.Now()))
+	assert.Equal(t, models.WaitlistWaiting, waitlistEntry(t, ctx, first.ID).Status)
+}
+
+func TestWaitlistSweepFindsFreeDateBehindManyFullDates(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	photographerID := primitive.NewObjectID()
+	t.Cleanup(func() {
+		ctx := context.Background()
+		repositories.Unscoped("bookings").DeleteMany(ctx, bson.M{"photographer_id": photographerID})
+		repositories.Unscoped(waitlist.Collection).DeleteMany(ctx, bson.M{"photographer_id": photographerID})
+	})
+
+	queue := func(day time.Time) models.WaitlistEntry {
+		entry := models.WaitlistEntry{
+			ID:             primitive.NewObjectID(),
+			PhotographerID: photographerID,
+			ClientID:       primitive.NewObjectID(),
+			Date:           day,
+			Status:         models.WaitlistWaiting,
+			Active:         true,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		_, err := repositories.New(waitlist.Collection).InsertOne(ctx, entry)
+		require.NoError(t, err)
+		return entry
+	}
+
+	// Tanggal penuh yang lebih awal dan lebih banyak dari satu batch sweep
+	first := waitlist.Day(now).AddDate(0, 0, 1)
+	for i := 0; i < 250; i++ {
+		day := first.AddDate(0, 0, i)
+		_, err := repositories.New("bookings").InsertOne(ctx, models.Booking{
+			ID:             primitive.NewObjectID(),
+			ClientID:       primitive.NewObjectID(),
+			PhotographerID: photographerID,
+			Date:           day.Add(10 * time.Hour),
+			Status:         models.BookingStatusConfirmed,
+			CreatedAt:      now,
+		})
+		require.NoError(t, err)
+		queue(day)
+	}
+	// Tanggal yang kosong tanpa event, misal bookingnya dihapus permanen
+	free := queue(first.AddDate(0, 0, 300))
+
+	require.NoError(t, waitlist.Sweep(ctx, now))
+	assert.Equal(t, models.WaitlistOffered, waitlistEntry(t, ctx, free.ID).Status)
+}
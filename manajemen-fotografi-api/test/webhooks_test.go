@@ -0,0 +1,105 @@
+package test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/webhooks"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// receivedWebhook adalah request yang diterima endpoint partner palsu
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+// partnerServer menjalankan endpoint partner lokal yang membalas dengan status tertentu
+func partnerServer(t *testing.T, status int) (*httptest.Server, <-chan receivedWebhook) {
+	received := make(chan receivedWebhook, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- receivedWebhook{header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+func testDelivery() models.WebhookDelivery {
+	return models.WebhookDelivery{
+		ID:        primitive.NewObjectID(),
+		EventID:   primitive.NewObjectID(),
+		EventType: "booking.created",
+		Payload:   `{"id":"1","type":"booking.created","data":{"location":"Bandung"}}`,
+		Status:    models.WebhookDeliveryPending,
+	}
+}
+
+func TestWebhookSendSignsPayload(t *testing.T) {
+	server, received := partnerServer(t, http.StatusNoContent)
+	hook := models.Webhook{URL: server.URL, Secret: "rahasia", Active: true}
+	delivery := testDelivery()
+
+	attempt, ok := webhooks.Send(context.Background(), hook, delivery)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusNoContent, attempt.StatusCode)
+	assert.Empty(t, attempt.Error)
+
+	req := <-received
+	assert.Equal(t, delivery.Payload, string(req.body))
+	assert.Equal(t, "booking.created", req.header.Get(webhooks.HeaderEvent))
+	assert.Equal(t, delivery.ID.Hex(), req.header.Get(webhooks.HeaderDelivery))
+
+	timestamp, err := strconv.ParseInt(req.header.Get(webhooks.HeaderTimestamp), 10, 64)
+	assert.NoError(t, err)
+	signature := req.header.Get(webhooks.HeaderSignature)
+	assert.True(t, webhooks.Verify("rahasia", timestamp, req.body, signature))
+	assert.False(t, webhooks.Verify("secret-lain", timestamp, req.body, signature))
+	assert.False(t, webhooks.Verify("rahasia", timestamp+1, req.body, signature))
+}
+
+func TestWebhookSendFailsOnNon2xx(t *testing.T) {
+	server, received := partnerServer(t, http.StatusInternalServerError)
+	hook := models.Webhook{URL: server.URL, Secret: "rahasia", Active: true}
+
+	attempt, ok := webhooks.Send(context.Background(), hook, testDelivery())
+	<-received
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusInternalServerError, attempt.StatusCode)
+	assert.NotEmpty(t, attempt.Error)
+}
+
+func TestWebhookSendFailsWhenUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	hook := models.Webhook{URL: server.URL, Secret: "rahasia", Active: true}
+
+	attempt, ok := webhooks.Send(context.Background(), hook, testDelivery())
+	assert.False(t, ok)
+	assert.Zero(t, attempt.StatusCode)
+	assert.NotEmpty(t, attempt.Error)
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, webhooks.Backoff(1))
+	assert.Equal(t, time.Minute, webhooks.Backoff(2))
+	assert.Equal(t, 2*time.Minute, webhooks.Backoff(3))
+	assert.Equal(t, 6*time.Hour, webhooks.Backoff(20))
+}
+
+func TestWebhookMaxAttemptsFromEnv(t *testing.T) {
+	t.Setenv("WEBHOOK_MAX_ATTEMPTS", "3")
+	assert.Equal(t, 3, webhooks.MaxAttempts())
+
+	t.Setenv("WEBHOOK_MAX_ATTEMPTS", "bukan-angka")
+	assert.Equal(t, webhooks.DefaultMaxAttempts, webhooks.MaxAttempts())
+}
@@ -0,0 +1,143 @@
+package trash
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"manajemen-fotografi-api/integrity"
+	"manajemen-fotografi-api/logging"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/storage"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Default retensi dan interval purger, bisa diganti lewat TRASH_RETENTION dan TRASH_PURGE_INTERVAL
+const (
+	DefaultRetention = 30 * 24 * time.Hour
+	DefaultInterval  = time.Hour
+)
+
+// batchSize membatasi jumlah dokumen per collection dalam satu putaran purge
+const batchSize = 500
+
+// fileFields adalah field berisi URL file upload yang ikut dihapus saat dokumen di-purge.
+// Hanya field yang diisi server dari storage.Save, supaya purge tidak menghapus file milik
+// dokumen lain; image_url galeri diisi client dan bisa menunjuk upload siapa saja, jadi tidak
+// termasuk. Path bertitik membaca field di dalam array, misal attachments.url.
+var fileFields = map[string][]string{
+	"photographers": {"profile_photo"},
+	"messages":      {"attachments.url"},
+}
+
+// Retention membaca TRASH_RETENTION (format durasi Go, misal 720h), default 30 hari
+func Retention() time.Duration {
+	return durationFromEnv("TRASH_RETENTION", DefaultRetention)
+}
+
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+// Start menjalankan purger di background sampai ctx dibatalkan
+func Start(ctx context.Context) {
+	interval := durationFromEnv("TRASH_PURGE_INTERVAL", DefaultInterval)
+	retention := Retention()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			Purge(ctx, time.Now().Add(-retention))
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Purge menghapus permanen dokumen yang masuk trash sebelum cutoff beserta file upload-nya.
+// Mengembalikan jumlah dokumen yang dihapus per collection.
+func Purge(ctx context.Context, cutoff time.Time) map[string]int {
+	logger := logging.FromContext(ctx)
+	purged := map[string]int{}
+
+	for _, name := range repositories.SoftDeleteCollections {
+		coll := repositories.Unscoped(name)
+		cursor, err := coll.Find(ctx,
+			bson.M{"deleted_at": bson.M{"$ne": nil, "$lt": cutoff}},
+			options.Find().SetLimit(batchSize),
+		)
+		if err != nil {
+			logger.ErrorContext(ctx, "purge trash gagal", "collection", name, "error", err)
+			continue
+		}
+
+		var docs []bson.M
+		if err := cursor.All(ctx, &docs); err != nil {
+			logger.ErrorContext(ctx, "purge trash gagal", "collection", name, "error", err)
+			continue
+		}
+
+		for _, doc := range docs {
+			id, _ := doc["_id"].(primitive.ObjectID)
+			if err := purgeOne(ctx, name, id, doc); err != nil {
+				logger.ErrorContext(ctx, "purge dokumen gagal", "collection", name, "id", id.Hex(), "error", err)
+				continue
+			}
+			purged[name]++
+		}
+
+		if purged[name] > 0 {
+			logger.InfoContext(ctx, "trash di-purge", "collection", name, "count", purged[name])
+		}
+	}
+	return purged
+}
+
+// purgeOne menghapus file upload dulu, baru dokumennya; jika hapus file gagal
+// dokumen tetap di trash dan dicoba lagi pada putaran berikutnya
+func purgeOne(ctx context.Context, name string, id primitive.ObjectID, doc bson.M) error {
+	for _, field := range fileFields[name] {
+		for _, url := range fileURLs(doc, field) {
+			if err := storage.Default.Delete(ctx, url); err != nil {
+				return err
+			}
+		}
+	}
+	return integrity.Purge(ctx, name, id)
+}
+
+// fileURLs mengambil URL file dari field dokumen, termasuk dari setiap elemen array
+func fileURLs(doc bson.M, field string) []string {
+	head, rest, nested := strings.Cut(field, ".")
+	switch v := doc[head].(type) {
+	case string:
+		if v != "" && !nested {
+			return []string{v}
+		}
+	case bson.M:
+		if nested {
+			return fileURLs(v, rest)
+		}
+	case bson.A:
+		var urls []string
+		for _, item := range v {
+			if m, ok := item.(bson.M); ok && nested {
+				urls = append(urls, fileURLs(m, rest)...)
+			}
+		}
+		return urls
+	}
+	return nil
+}
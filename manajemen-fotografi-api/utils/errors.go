@@ -0,0 +1,179 @@
+package utils
+
+import (
+	"fmt"
+
+	"manajemen-fotografi-api/i18n"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FieldError menjelaskan kesalahan pada satu field input.
+// Message berbahasa default, diterjemahkan ulang dari Key saat dikirim ke client.
+type FieldError struct {
+	Field   string            `json:"field"`
+	Code    string            `json:"code,omitempty"`
+	Message string            `json:"message"`
+	Key     string            `json:"-"`
+	Params  map[string]string `json:"-"`
+}
+
+// AppError adalah error dengan kode stabil yang bisa dibaca mesin (misal BOOKING_NOT_FOUND),
+// status HTTP, pesan untuk user dan detail per field.
+// Key adalah kunci katalog i18n untuk pesan, defaultnya sama dengan Code.
+type AppError struct {
+	Code    string
+	Status  int
+	Message string
+	Key     string
+	Params  map[string]string
+	Details []FieldError
+	Err     error // error asli, tidak pernah dikirim ke client
+}
+
+func (e *AppError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// Is membuat errors.Is cocok berdasarkan kode, walau instance sudah di-copy lewat With*
+func (e *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	return ok && t.Code == e.Code
+}
+
+// NewError membuat entri katalog error baru. Pesan diambil dari katalog i18n
+// berdasarkan kode; message dipakai jika kode belum ada di katalog.
+func NewError(code string, status int, message string) *AppError {
+	e := &AppError{Code: code, Status: status, Message: message}
+	if i18n.Has(code) {
+		e.Key = code
+		e.Message = i18n.T(i18n.Default, code, nil)
+	}
+	return e
+}
+
+// Wrap menyalin error katalog dan menyimpan error asli sebagai penyebab
+func (e *AppError) Wrap(err error) *AppError {
+	cp := *e
+	cp.Err = err
+	return &cp
+}
+
+// WithMessage menyalin error katalog dengan pesan apa adanya (tidak diterjemahkan),
+// misal pesan dari library pihak ketiga
+func (e *AppError) WithMessage(message string) *AppError {
+	cp := *e
+	cp.Message = message
+	cp.Key = ""
+	cp.Params = nil
+	return &cp
+}
+
+// WithKey menyalin error katalog dengan pesan yang lebih spesifik dari katalog i18n
+func (e *AppError) WithKey(key string, params map[string]string) *AppError {
+	cp := *e
+	cp.Key = key
+	cp.Params = params
+	cp.Message = i18n.T(i18n.Default, key, params)
+	return &cp
+}
+
+// Localize mengembalikan pesan error dalam bahasa tertentu
+func (e *AppError) Localize(lang string) string {
+	if e.Key == "" {
+		return e.Message
+	}
+	return i18n.T(lang, e.Key, e.Params)
+}
+
+// Localize mengembalikan pesan field dalam bahasa tertentu
+func (fe FieldError) Localize(lang string) FieldError {
+	if fe.Key != "" {
+		fe.Message = i18n.T(lang, fe.Key, fe.Params)
+	}
+	return fe
+}
+
+// WithDetails menyalin error katalog dan menambahkan detail per field
+func (e *AppError) WithDetails(details ...FieldError) *AppError {
+	cp := *e
+	cp.Details = append(append([]FieldError{}, e.Details...), details...)
+	return &cp
+}
+
+// Katalog error umum
+var (
+	ErrInternal             = NewError("INTERNAL_ERROR", fiber.StatusInternalServerError, "Terjadi kesalahan pada server")
+	ErrDatabase             = NewError("DATABASE_ERROR", fiber.StatusInternalServerError, "Gagal memproses data")
+	ErrUpload               = NewError("UPLOAD_FAILED", fiber.StatusInternalServerError, "Gagal menyimpan file")
+	ErrTimeout              = NewError("TIMEOUT", fiber.StatusGatewayTimeout, "Permintaan melebihi batas waktu")
+	ErrRouteNotFound        = NewError("ROUTE_NOT_FOUND", fiber.StatusNotFound, "Endpoint tidak ditemukan")
+	ErrInvalidID            = NewError("INVALID_ID", fiber.StatusBadRequest, "ID tidak valid")
+	ErrInvalidUserID        = NewError("INVALID_USER_ID", fiber.StatusBadRequest, "User ID tidak valid")
+	ErrInvalidBody          = NewError("INVALID_BODY", fiber.StatusBadRequest, "Format data salah")
+	ErrValidation           = NewError("VALIDATION_FAILED", fiber.StatusBadRequest, "Data tidak valid")
+	ErrDuplicate            = NewError("DUPLICATE", fiber.StatusConflict, "Data sudah ada")
+	ErrDeleteRestricted     = NewError("DELETE_RESTRICTED", fiber.StatusConflict, "Data masih dipakai dan tidak bisa dihapus")
+	ErrPreconditionRequired = NewError("PRECONDITION_REQUIRED", fiber.StatusPreconditionRequired, "Header If-Match wajib dikirim")
+	ErrPreconditionFailed   = NewError("PRECONDITION_FAILED", fiber.StatusPreconditionFailed, "Data sudah diubah pihak lain, muat ulang lalu coba lagi")
+	ErrUnauthenticated      = NewError("UNAUTHENTICATED", fiber.StatusUnauthorized, "Silakan login terlebih dahulu")
+	ErrForbidden            = NewError("FORBIDDEN", fiber.StatusForbidden, "Anda tidak memiliki akses ke data ini")
+)
+
+// Katalog error per entitas
+var (
+	ErrUserNotFound            = NewError("USER_NOT_FOUND", fiber.StatusNotFound, "User tidak ditemukan")
+	ErrEmailTaken              = NewError("EMAIL_ALREADY_REGISTERED", fiber.StatusConflict, "Email sudah terdaftar")
+	ErrInvalidCredentials      = NewError("INVALID_CREDENTIALS", fiber.StatusUnauthorized, "Email atau password salah")
+	ErrClientNotFound          = NewError("CLIENT_NOT_FOUND", fiber.StatusNotFound, "Client tidak ditemukan")
+	ErrClientExists            = NewError("CLIENT_ALREADY_EXISTS", fiber.StatusConflict, "User sudah memiliki profil client")
+	ErrPhotographerNotFound    = NewError("PHOTOGRAPHER_NOT_FOUND", fiber.StatusNotFound, "Fotografer tidak ditemukan")
+	ErrPhotographerExists      = NewError("PHOTOGRAPHER_ALREADY_EXISTS", fiber.StatusConflict, "User sudah memiliki profil fotografer")
+	ErrBookingNotFound         = NewError("BOOKING_NOT_FOUND", fiber.StatusNotFound, "Booking tidak ditemukan")
+	ErrBookingNotPending       = NewError("BOOKING_NOT_PENDING", fiber.StatusConflict, "Booking tidak dalam status pending")
+	ErrGalleryNotFound         = NewError("GALLERY_NOT_FOUND", fiber.StatusNotFound, "Galeri tidak ditemukan")
+	ErrTransactionNotFound     = NewError("TRANSACTION_NOT_FOUND", fiber.StatusNotFound, "Transaksi tidak ditemukan")
+	ErrWebhookNotFound         = NewError("WEBHOOK_NOT_FOUND", fiber.StatusNotFound, "Webhook tidak ditemukan")
+	ErrWebhookDeliveryNotFound = NewError("WEBHOOK_DELIVERY_NOT_FOUND", fiber.StatusNotFound, "Delivery webhook tidak ditemukan atau tidak berada di dead-letter")
+	ErrJobNotFound             = NewError("JOB_NOT_FOUND", fiber.StatusNotFound, "Job tidak ditemukan atau tidak berstatus gagal")
+	ErrCalendarTokenInvalid    = NewError("CALENDAR_TOKEN_INVALID", fiber.StatusForbidden, "Token kalender tidak valid")
+	ErrBookingSlotBusy         = NewError("BOOKING_SLOT_BUSY", fiber.StatusConflict, "Fotografer sedang tidak tersedia pada tanggal tersebut")
+	ErrMetricNotFound          = NewError("ANALYTICS_METRIC_NOT_FOUND", fiber.StatusNotFound, "Laporan analitik tidak ditemukan")
+	ErrPaymentNotRecorded      = NewError("LEDGER_PAYMENT_NOT_FOUND", fiber.StatusNotFound, "Pembayaran transaksi belum tercatat di buku besar")
+	ErrRefundExceedsPayment    = NewError("REFUND_EXCEEDS_PAYMENT", fiber.StatusConflict, "Total refund melebihi nominal pembayaran")
+	ErrCommissionRateNotFound  = NewError("COMMISSION_RATE_NOT_FOUND", fiber.StatusNotFound, "Fotografer tidak memiliki tarif komisi khusus")
+	ErrPayoutNotFound          = NewError("PAYOUT_NOT_FOUND", fiber.StatusNotFound, "Batch payout tidak ditemukan")
+	ErrCouponNotFound          = NewError("COUPON_NOT_FOUND", fiber.StatusNotFound, "Kupon tidak ditemukan")
+	ErrCouponExists            = NewError("COUPON_ALREADY_EXISTS", fiber.StatusConflict, "Kode kupon sudah dipakai")
+	ErrBookingSlotHeld         = NewError("BOOKING_SLOT_HELD", fiber.StatusConflict, "Tanggal ini sedang ditawarkan ke client lain dari waitlist")
+	ErrWaitlistNotFound        = NewError("WAITLIST_NOT_FOUND", fiber.StatusNotFound, "Antrean waitlist tidak ditemukan")
+	ErrWaitlistJoined          = NewError("WAITLIST_ALREADY_JOINED", fiber.StatusConflict, "Anda sudah berada di waitlist tanggal ini")
+	ErrWaitlistDateAvailable   = NewError("WAITLIST_DATE_AVAILABLE", fiber.StatusConflict, "Tanggal masih tersedia, silakan booking langsung")
+	ErrWaitlistClosed          = NewError("WAITLIST_CLOSED", fiber.StatusConflict, "Antrean waitlist sudah tidak aktif")
+	ErrShortlistNotFound       = NewError("SHORTLIST_NOT_FOUND", fiber.StatusNotFound, "Shortlist tidak ditemukan")
+	ErrShortlistItemNotFound   = NewError("SHORTLIST_ITEM_NOT_FOUND", fiber.StatusNotFound, "Item shortlist tidak ditemukan")
+	ErrShortlistItemExists     = NewError("SHORTLIST_ITEM_EXISTS", fiber.StatusConflict, "Item sudah ada di shortlist ini")
+)
+
+// Invalid membuat error validasi untuk satu field, key adalah kunci katalog i18n
+// dengan placeholder {field}
+func Invalid(field, key string) *AppError {
+	return InvalidWith(field, key, map[string]string{"field": field})
+}
+
+// InvalidWith seperti Invalid, dengan parameter pesan tambahan
+func InvalidWith(field, key string, params map[string]string) *AppError {
+	return ErrValidation.WithDetails(FieldError{
+		Field:   field,
+		Message: i18n.T(i18n.Default, key, params),
+		Key:     key,
+		Params:  params,
+	})
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AnyVersion dikembalikan IfMatch untuk "If-Match: *", artinya update tanpa cek versi
+const AnyVersion int64 = -1
+
+// ETag membuat ETag dari version dokumen, misal "3"
+func ETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// SetETag menulis header ETag untuk dokumen dengan version tertentu
+func SetETag(c *fiber.Ctx, version int64) {
+	c.Set(fiber.HeaderETag, ETag(version))
+}
+
+// IfMatch membaca version dari header If-Match. Header wajib ada (428 jika tidak),
+// dan nilai yang tidak bisa dibaca dianggap tidak cocok (412).
+func IfMatch(c *fiber.Ctx) (int64, error) {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" {
+		return 0, ErrPreconditionRequired
+	}
+	if header == "*" {
+		return AnyVersion, nil
+	}
+
+	// Weak ETag (W/"3") diterima karena version tetap menunjuk isi dokumen yang sama
+	tag := strings.TrimPrefix(header, "W/")
+	unquoted, err := strconv.Unquote(tag)
+	if err != nil {
+		return 0, ErrPreconditionFailed
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version < 0 {
+		return 0, ErrPreconditionFailed
+	}
+	return version, nil
+}
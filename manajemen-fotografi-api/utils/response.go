@@ -1 +1,140 @@
-package utils
\ No newline at end of file
+package utils
+
+import (
+	"context"
+	"errors"
+
+	"manajemen-fotografi-api/i18n"
+	"manajemen-fotografi-api/logging"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Response adalah envelope standar untuk semua response API.
+// Response sukses berisi data (dan meta untuk list), response gagal berisi error.
+type Response struct {
+	Data    interface{} `json:"data,omitempty"`
+	Meta    *Meta       `json:"meta,omitempty"`
+	Message string      `json:"message,omitempty"`
+	Error   *ErrorBody  `json:"error,omitempty"`
+}
+
+// Meta berisi informasi pagination untuk response list.
+// Page diisi untuk pagination berbasis halaman, NextCursor untuk pagination berbasis cursor.
+type Meta struct {
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit,omitempty"`
+	Total      int64  `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ErrorBody adalah bentuk error yang dikirim ke client
+type ErrorBody struct {
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Details []FieldError `json:"details,omitempty"`
+}
+
+// Success mengirim data dengan status tertentu
+func Success(c *fiber.Ctx, status int, data interface{}) error {
+	return c.Status(status).JSON(Response{Data: data})
+}
+
+// SuccessMessage mengirim pesan sukses dari katalog i18n, dengan data opsional
+func SuccessMessage(c *fiber.Ctx, status int, key string, data interface{}) error {
+	return c.Status(status).JSON(Response{Data: data, Message: i18n.T(Lang(c), key, nil)})
+}
+
+// SuccessList mengirim data list beserta meta pagination
+func SuccessList(c *fiber.Ctx, data interface{}, meta *Meta) error {
+	return c.Status(fiber.StatusOK).JSON(Response{Data: data, Meta: meta})
+}
+
+// Error mengirim error dalam envelope standar dengan status dari ToAppError.
+// Error server (5xx) dicatat ke log beserta error aslinya, karena error asli
+// tidak pernah dikirim ke client.
+func Error(c *fiber.Ctx, err error) error {
+	appErr := ToAppError(err)
+	if appErr.Status >= fiber.StatusInternalServerError {
+		logging.FromContext(c.UserContext()).ErrorContext(c.UserContext(), appErr.Message,
+			"code", appErr.Code,
+			"error", appErr.Err,
+		)
+	}
+	return c.Status(appErr.Status).JSON(Response{Error: appErr.Body(Lang(c))})
+}
+
+// Lang mengembalikan bahasa response yang dipilih middleware locale
+func Lang(c *fiber.Ctx) string {
+	return i18n.FromContext(c.UserContext())
+}
+
+// Body mengubah AppError menjadi bentuk yang aman dikirim ke client, dalam bahasa tertentu
+func (e *AppError) Body(lang string) *ErrorBody {
+	body := &ErrorBody{Code: e.Code, Message: e.Localize(lang)}
+	for _, d := range e.Details {
+		body.Details = append(body.Details, d.Localize(lang))
+	}
+	return body
+}
+
+// ToAppError adalah satu-satunya tempat pemetaan error ke kode dan status HTTP
+func ToAppError(err error) *AppError {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		switch fiberErr.Code {
+		case fiber.StatusNotFound:
+			return ErrRouteNotFound
+		case fiber.StatusBadRequest, fiber.StatusUnprocessableEntity:
+			return ErrInvalidBody.Wrap(err)
+		}
+		return NewError("HTTP_ERROR", fiberErr.Code, fiberErr.Message).Wrap(err)
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrTimeout.Wrap(err)
+	case mongo.IsDuplicateKeyError(err):
+		return ErrDuplicate.Wrap(err)
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return errNotFound.Wrap(err)
+	}
+	return ErrInternal.Wrap(err)
+}
+
+var errNotFound = NewError("NOT_FOUND", fiber.StatusNotFound, "Data tidak ditemukan")
+
+// StatusOf mengembalikan status HTTP untuk sebuah error
+func StatusOf(err error) int {
+	return ToAppError(err).Status
+}
+
+// NotFoundOr memetakan mongo.ErrNoDocuments ke error "tidak ditemukan" milik entitas,
+// sedangkan error lain dianggap kegagalan database.
+func NotFoundOr(err error, notFound *AppError) *AppError {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return notFound
+	}
+	return ErrDatabase.Wrap(err)
+}
+
+// DuplicateOr memetakan pelanggaran index unik ke error "sudah ada" milik entitas,
+// sedangkan error lain dibungkus dengan fallback.
+func DuplicateOr(err error, duplicate, fallback *AppError) *AppError {
+	if mongo.IsDuplicateKeyError(err) {
+		return duplicate.Wrap(err)
+	}
+	return fallback.Wrap(err)
+}
+
+// ErrorHandler dipasang di fiber.Config agar error yang lolos dari handler
+// (route tidak ada, body terlalu besar, panic, dsb) tetap memakai envelope standar.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	return Error(c, err)
+}
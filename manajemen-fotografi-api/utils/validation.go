@@ -1,10 +1,228 @@
 package utils
 
-import "regexp"
+import (
+	"errors"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
 
+	"manajemen-fotografi-api/i18n"
+	"manajemen-fotografi-api/models"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/go-playground/validator/v10/non-standard/validators"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// phoneRegex menerima nomor Indonesia dengan awalan 0, 62 atau +62 (seluler maupun PSTN)
+var phoneRegex = regexp.MustCompile(`^(\+62|62|0)[2-9][0-9]{7,11}$`)
+
+var bookingStatuses = map[string]bool{
+	models.BookingStatusPending:   true,
+	models.BookingStatusConfirmed: true,
+	models.BookingStatusDone:      true,
+	models.BookingStatusCancelled: true,
+	models.BookingStatusNoShow:    true,
+}
+
+// DefaultPaymentMethods adalah metode pembayaran yang diterima jika PAYMENT_METHODS kosong
+var DefaultPaymentMethods = []string{"transfer", "ewallet"}
+
+// PaymentMethods membaca daftar metode pembayaran dari PAYMENT_METHODS (dipisah koma),
+// misal "transfer,ewallet,qris", supaya metode baru bisa dibuka tanpa rilis ulang
+func PaymentMethods() []string {
+	var methods []string
+	for _, m := range strings.Split(os.Getenv("PAYMENT_METHODS"), ",") {
+		if m = strings.ToLower(strings.TrimSpace(m)); m != "" {
+			methods = append(methods, m)
+		}
+	}
+	if len(methods) == 0 {
+		return DefaultPaymentMethods
+	}
+	return methods
+}
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// Nama field di pesan error memakai nama JSON, bukan nama field Go
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" || name == "" {
+			name = strings.SplitN(field.Tag.Get("form"), ",", 2)[0]
+		}
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	v.RegisterValidation("notblank", validators.NotBlank)
+	v.RegisterValidation("phone_id", func(fl validator.FieldLevel) bool {
+		return IsValidPhone(fl.Field().String())
+	})
+	v.RegisterValidation("objectid", validateObjectID)
+	v.RegisterValidation("booking_status", func(fl validator.FieldLevel) bool {
+		return bookingStatuses[fl.Field().String()]
+	})
+	v.RegisterValidation("payment_method", func(fl validator.FieldLevel) bool {
+		return slices.Contains(PaymentMethods(), fl.Field().String())
+	})
+	v.RegisterValidation("url_or_path", func(fl validator.FieldLevel) bool {
+		return IsURLOrPath(fl.Field().String())
+	})
+	v.RegisterValidation("alphaspace", func(fl validator.FieldLevel) bool {
+		for _, r := range fl.Field().String() {
+			if !(unicode.IsLetter(r) || unicode.IsSpace(r)) {
+				return false
+			}
+		}
+		return true
+	})
+
+	// Aturan nama orang dipakai bersama oleh User dan Client
+	v.RegisterAlias("person_name", "min=3,max=50,alphaspace")
+
+	return v
+}
+
+// IsURLOrPath menerima URL http(s) absolut atau path relatif root seperti /uploads/foto.jpg,
+// yaitu bentuk URL yang dihasilkan storage lokal
+func IsURLOrPath(value string) bool {
+	if strings.HasPrefix(value, "/") && !strings.HasPrefix(value, "//") {
+		_, err := url.ParseRequestURI(value)
+		return err == nil
+	}
+	u, err := url.ParseRequestURI(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// validateObjectID menerima primitive.ObjectID yang tidak kosong atau string hex ObjectID
+func validateObjectID(fl validator.FieldLevel) bool {
+	switch value := fl.Field().Interface().(type) {
+	case primitive.ObjectID:
+		return !value.IsZero()
+	case string:
+		return primitive.IsValidObjectID(value)
+	}
+	return false
+}
+
+// IsValidPhone mengecek format nomor telepon Indonesia
 func IsValidPhone(phone string) bool {
-	re := regexp.MustCompile(`^\d{8,15}$`)
-	return re.MatchString(phone)
+	return phoneRegex.MatchString(phone)
 }
 
+// NormalizePhone mengubah nomor yang lolos IsValidPhone ke format E.164,
+// misal 081234567890 dan 6281234567890 menjadi +6281234567890
+func NormalizePhone(phone string) string {
+	switch {
+	case strings.HasPrefix(phone, "+62"):
+		return phone
+	case strings.HasPrefix(phone, "62"):
+		return "+" + phone
+	case strings.HasPrefix(phone, "0"):
+		return "+62" + phone[1:]
+	}
+	return phone
+}
+
+// Validate memvalidasi semua field (mode create / penggantian penuh).
+// Semua kesalahan dikumpulkan dalam satu error VALIDATION_FAILED.
+func Validate(s interface{}) error {
+	return toValidationError(validate.Struct(s))
+}
+
+// ValidatePartial memvalidasi hanya field yang terisi (mode update parsial).
+// Field kosong dianggap tidak dikirim sehingga aturan "required" tidak berlaku.
+func ValidatePartial(s interface{}) error {
+	return ValidateFields(s, filledFields(s)...)
+}
+
+// ValidateFields memvalidasi field tertentu saja, berdasarkan nama field Go
+func ValidateFields(s interface{}, fields ...string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return toValidationError(validate.StructPartial(s, fields...))
+}
+
+// filledFields mengembalikan nama field top-level yang nilainya bukan zero value
+func filledFields(s interface{}) []string {
+	v := reflect.Indirect(reflect.ValueOf(s))
+	t := v.Type()
+
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() && !v.Field(i).IsZero() {
+			fields = append(fields, t.Field(i).Name)
+		}
+	}
+	return fields
+}
+
+func toValidationError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return ErrInternal.Wrap(err)
+	}
+
+	details := make([]FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		key, params := fieldKey(fe)
+		details = append(details, FieldError{
+			Field:   fieldPath(fe),
+			Code:    fe.Tag(),
+			Message: i18n.T(i18n.Default, key, params),
+			Key:     key,
+			Params:  params,
+		})
+	}
+	return ErrValidation.WithDetails(details...)
+}
 
+// fieldPath membuang nama struct di depan namespace, misal "Client.address" menjadi "address"
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return fe.Field()
+}
+
+// fieldKey memetakan aturan validasi ke kunci katalog i18n beserta parameternya
+func fieldKey(fe validator.FieldError) (string, map[string]string) {
+	params := map[string]string{"field": fe.Field(), "param": fe.Param()}
+
+	switch fe.Tag() {
+	case "required", "notblank":
+		return "validation.required", params
+	case "min", "max":
+		if fe.Kind() == reflect.String {
+			return "validation." + fe.Tag() + "_string", params
+		}
+		return "validation." + fe.Tag(), params
+	case "oneof":
+		params["param"] = strings.ReplaceAll(fe.Param(), " ", ", ")
+		return "validation.oneof", params
+	case "payment_method":
+		params["param"] = strings.Join(PaymentMethods(), ", ")
+		return "validation.oneof", params
+	case "url_or_path":
+		return "validation.url", params
+	case "gt", "email", "url", "phone_id", "objectid", "booking_status", "person_name":
+		return "validation." + fe.Tag(), params
+	}
+	return "validation.invalid", params
+}
@@ -0,0 +1,187 @@
+package waitlist
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"manajemen-fotografi-api/events"
+	"manajemen-fotografi-api/logging"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/scheduler"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// JobSweep mengakhiri tawaran yang kedaluwarsa dan menawarkan tanggal yang kosong ke
+// antrean berikutnya, termasuk tanggal yang kosong karena booking dihapus
+const JobSweep = "waitlist.sweep"
+
+// DefaultSweepInterval adalah jeda sweep, bisa diganti lewat WAITLIST_SWEEP_INTERVAL
+const DefaultSweepInterval = time.Minute
+
+// sweepBatch membatasi jumlah entri yang diproses satu putaran sweep
+const sweepBatch = 200
+
+// dayMillis adalah panjang satu hari dalam milidetik, untuk aritmetika tanggal di aggregate.
+// Hari dihitung dalam WIB yang tidak punya DST, jadi selalu 24 jam.
+const dayMillis = int64(24 * time.Hour / time.Millisecond)
+
+// Start menawarkan tanggal ke antrean begitu booking dibatalkan dan mendaftarkan job sweep.
+// Harus dipanggil sebelum scheduler.Start.
+func Start(ctx context.Context) {
+	go events.Consume(ctx, "waitlist", HandleEvent)
+
+	scheduler.Register(JobSweep, func(ctx context.Context, job models.Job) error {
+		return Sweep(ctx, time.Now())
+	})
+	if err := scheduler.Every(ctx, JobSweep, sweepInterval()); err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "gagal mendaftarkan job berulang", "job", JobSweep, "error", err)
+	}
+}
+
+func sweepInterval() time.Duration {
+	d, err := time.ParseDuration(os.Getenv("WAITLIST_SWEEP_INTERVAL"))
+	if err != nil || d <= 0 {
+		return DefaultSweepInterval
+	}
+	return d
+}
+
+// HandleEvent menawarkan tanggal booking yang baru saja dibatalkan atau ditolak fotografer
+func HandleEvent(ctx context.Context, event models.Event) {
+	if event.Type != events.BookingStatusChanged {
+		return
+	}
+	from, _ := event.Data["from"].(string)
+	to, _ := event.Data["to"].(string)
+	if !active(from) || active(to) {
+		return
+	}
+
+	var booking models.Booking
+	raw, err := bson.Marshal(event.Data["booking"])
+	if err == nil {
+		err = bson.Unmarshal(raw, &booking)
+	}
+	if err == nil {
+		err = Advance(ctx, booking.PhotographerID, Day(booking.Date), time.Now())
+	}
+	if err != nil {
+		// Sweep berikutnya akan mencoba lagi
+		logging.FromContext(ctx).ErrorContext(ctx, "gagal memproses waitlist", "event_id", event.ID.Hex(), "error", err)
+	}
+}
+
+func active(status string) bool {
+	return status == models.BookingStatusPending || status == models.BookingStatusConfirmed
+}
+
+// Sweep mengakhiri tawaran yang lewat batas dan langsung menawarkannya ke antrean berikutnya,
+// menutup antrean untuk tanggal yang sudah lewat, lalu memanggil Advance untuk tanggal kosong
+// yang masih punya antrean tanpa tawaran berjalan (misal karena booking dihapus)
+func Sweep(ctx context.Context, now time.Time) error {
+	coll := collection(Collection)
+
+	cursor, err := coll.Find(ctx,
+		bson.M{"offer_open": true, "offer_expires_at": bson.M{"$lte": now}},
+		options.Find().SetLimit(sweepBatch),
+	)
+	if err != nil {
+		return err
+	}
+	var expired []models.WaitlistEntry
+	if err := cursor.All(ctx, &expired); err != nil {
+		return err
+	}
+	for _, entry := range expired {
+		// Status ikut di filter supaya entri yang baru saja dibooking client tidak tertimpa
+		res, err := coll.UpdateOne(ctx,
+			bson.M{"_id": entry.ID, "status": models.WaitlistOffered},
+			closed(models.WaitlistExpired, now),
+		)
+		if err != nil {
+			return err
+		}
+		// Tawaran langsung berpindah ke antrean berikutnya, tanpa menunggu pencarian di bawah
+		if res.ModifiedCount > 0 {
+			if err := Advance(ctx, entry.PhotographerID, entry.Date, now); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := coll.UpdateMany(ctx,
+		bson.M{"active": true, "date": bson.M{"$lt": Day(now)}},
+		closed(models.WaitlistExpired, now),
+	); err != nil {
+		return err
+	}
+
+	cursor, err = coll.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"active": true}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     bson.M{"photographer_id": "$photographer_id", "date": "$date"},
+			"offered": bson.M{"$max": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$status", models.WaitlistOffered}}, 1, 0}}},
+		}}},
+		{{Key: "$match", Value: bson.M{"offered": 0}}},
+		// Tanggal yang masih penuh dibuang sebelum dibatasi, supaya batch tidak terus berisi
+		// tanggal yang sama dan tanggal kosong lain tidak pernah terambil
+		{{Key: "$lookup", Value: bson.M{
+			"from": "bookings",
+			"let":  bson.M{"photographer_id": "$_id.photographer_id", "day": "$_id.date"},
+			"pipeline": mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{
+					"status":     bson.M{"$in": bson.A{models.BookingStatusPending, models.BookingStatusConfirmed}},
+					"deleted_at": nil,
+					"$expr": bson.M{"$and": bson.A{
+						bson.M{"$eq": bson.A{"$photographer_id", "$$photographer_id"}},
+						bson.M{"$gte": bson.A{"$date", "$$day"}},
+						bson.M{"$lt": bson.A{"$date", bson.M{"$add": bson.A{"$$day", dayMillis}}}},
+					}},
+				}}},
+				{{Key: "$limit", Value: 1}},
+			},
+			"as": "bookings",
+		}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": "busy_times",
+			"let":  bson.M{"photographer_id": "$_id.photographer_id", "day": "$_id.date"},
+			"pipeline": mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{
+					"$expr": bson.M{"$and": bson.A{
+						bson.M{"$eq": bson.A{"$photographer_id", "$$photographer_id"}},
+						bson.M{"$lt": bson.A{"$start", bson.M{"$add": bson.A{"$$day", dayMillis}}}},
+						bson.M{"$gt": bson.A{"$end", "$$day"}},
+					}},
+				}}},
+				{{Key: "$limit", Value: 1}},
+			},
+			"as": "busy",
+		}}},
+		{{Key: "$match", Value: bson.M{"bookings": bson.M{"$size": 0}, "busy": bson.M{"$size": 0}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id.date", Value: 1}, {Key: "_id.photographer_id", Value: 1}}}},
+		{{Key: "$limit", Value: sweepBatch}},
+	})
+	if err != nil {
+		return err
+	}
+	var days []struct {
+		ID struct {
+			PhotographerID primitive.ObjectID `bson:"photographer_id"`
+			Date           time.Time          `bson:"date"`
+		} `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &days); err != nil {
+		return err
+	}
+	for _, d := range days {
+		if err := Advance(ctx, d.ID.PhotographerID, d.ID.Date, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+// Package waitlist mengelola antrean client untuk tanggal fotografer yang sudah penuh.
+// Satu tanggal dianggap penuh jika fotografer sudah punya booking aktif atau jadwal sibuk
+// pada hari itu. Saat tanggal kosong lagi, client terdepan mendapat tawaran berbatas waktu;
+// tawaran yang ditolak atau kedaluwarsa otomatis berpindah ke antrean berikutnya.
+package waitlist
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"manajemen-fotografi-api/events"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/repositories"
+	"manajemen-fotografi-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection menyimpan antrean waitlist
+const Collection = "waitlist"
+
+// DefaultOfferTTL adalah lama tawaran berlaku, bisa diganti lewat WAITLIST_OFFER_TTL
+const DefaultOfferTTL = 24 * time.Hour
+
+// collection diambil saat dipakai, bukan saat package di-load,
+// supaya aturan antrean bisa diuji tanpa koneksi database
+var collection = repositories.New
+
+// Location adalah zona waktu pembagian hari booking
+var Location = loadWIB()
+
+func loadWIB() *time.Location {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		return time.FixedZone("WIB", 7*60*60)
+	}
+	return loc
+}
+
+// OfferTTL membaca WAITLIST_OFFER_TTL, default 24 jam
+func OfferTTL() time.Duration {
+	d, err := time.ParseDuration(os.Getenv("WAITLIST_OFFER_TTL"))
+	if err != nil || d <= 0 {
+		return DefaultOfferTTL
+	}
+	return d
+}
+
+// Day mengembalikan awal hari t dalam WIB
+func Day(t time.Time) time.Time {
+	y, m, d := t.In(Location).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, Location)
+}
+
+// OfferExpiry adalah batas tawaran yang dibuat pada now untuk tanggal day: now ditambah
+// ttl, tapi tidak melewati akhir hari tersebut
+func OfferExpiry(now, day time.Time, ttl time.Duration) time.Time {
+	end := day.AddDate(0, 0, 1)
+	if expires := now.Add(ttl); expires.Before(end) {
+		return expires
+	}
+	return end
+}
+
+// Full mengecek apakah fotografer sudah punya booking aktif atau jadwal sibuk pada day
+func Full(ctx context.Context, photographerID primitive.ObjectID, day time.Time) (bool, error) {
+	end := day.AddDate(0, 0, 1)
+	n, err := collection("bookings").CountDocuments(ctx, bson.M{
+		"photographer_id": photographerID,
+		"status":          bson.M{"$in": bson.A{models.BookingStatusPending, models.BookingStatusConfirmed}},
+		"date":            bson.M{"$gte": day, "$lt": end},
+	}, options.Count().SetLimit(1))
+	if err != nil || n > 0 {
+		return n > 0, err
+	}
+	n, err = collection("busy_times").CountDocuments(ctx, bson.M{
+		"photographer_id": photographerID,
+		"start":           bson.M{"$lt": end},
+		"end":             bson.M{"$gt": day},
+	}, options.Count().SetLimit(1))
+	return n > 0, err
+}
+
+// Join memasukkan client ke antrean tanggal date. Tanggal yang masih kosong ditolak
+// supaya client langsung booking.
+func Join(ctx context.Context, photographerID, clientID primitive.ObjectID, date, now time.Time) (models.WaitlistEntry, error) {
+	day := Day(date)
+	entry := models.WaitlistEntry{
+		ID:             primitive.NewObjectID(),
+		PhotographerID: photographerID,
+		ClientID:       clientID,
+		Date:           day,
+		Status:         models.WaitlistWaiting,
+		Active:         true,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if day.Before(Day(now)) {
+		return entry, utils.Invalid("date", "waitlist.past_date")
+	}
+
+	full, err := Full(ctx, photographerID, day)
+	if err != nil {
+		return entry, utils.ErrDatabase.Wrap(err)
+	}
+	if !full {
+		return entry, utils.ErrWaitlistDateAvailable
+	}
+
+	if _, err := collection(Collection).InsertOne(ctx, entry); err != nil {
+		return entry, utils.DuplicateOr(err, utils.ErrWaitlistJoined, utils.ErrDatabase)
+	}
+	return entry, nil
+}
+
+// closed adalah update yang menutup entri aktif dengan status akhir
+func closed(status string, now time.Time) bson.M {
+	return bson.M{
+		"$set":   bson.M{"status": status, "updated_at": now},
+		"$unset": bson.M{"active": "", "offer_open": ""},
+	}
+}
+
+// Leave mengeluarkan client dari antrean. Tawaran yang sedang dipegang dianggap ditolak
+// dan langsung berpindah ke antrean berikutnya.
+func Leave(ctx context.Context, entryID, clientID primitive.ObjectID, now time.Time) (models.WaitlistEntry, error) {
+	coll := collection(Collection)
+	var entry models.WaitlistEntry
+	err := coll.FindOne(ctx, bson.M{"_id": entryID, "client_id": clientID}).Decode(&entry)
+	if err != nil {
+		return entry, utils.NotFoundOr(err, utils.ErrWaitlistNotFound)
+	}
+	if !entry.Active {
+		return entry, utils.ErrWaitlistClosed
+	}
+
+	status := models.WaitlistLeft
+	if entry.Status == models.WaitlistOffered {
+		status = models.WaitlistDeclined
+	}
+	err = coll.FindOneAndUpdate(ctx,
+		bson.M{"_id": entryID, "status": entry.Status},
+		closed(status, now),
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&entry)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return entry, utils.ErrWaitlistClosed // status berubah bersamaan, misal tawaran kedaluwarsa
+	}
+	if err != nil {
+		return entry, utils.ErrDatabase.Wrap(err)
+	}
+
+	if status == models.WaitlistDeclined {
+		if err := Advance(ctx, entry.PhotographerID, entry.Date, now); err != nil {
+			return entry, utils.ErrDatabase.Wrap(err)
+		}
+	}
+	return entry, nil
+}
+
+// Advance memberi tawaran ke client terdepan jika tanggal day sudah kosong dan belum ada
+// tawaran yang berjalan. Index unik offer_open menjamin hanya satu tawaran per tanggal,
+// juga saat dipanggil bersamaan dari event dan sweep.
+func Advance(ctx context.Context, photographerID primitive.ObjectID, day, now time.Time) error {
+	if !now.Before(day.AddDate(0, 0, 1)) {
+		return nil
+	}
+	full, err := Full(ctx, photographerID, day)
+	if err != nil || full {
+		return err
+	}
+
+	expires := OfferExpiry(now, day, OfferTTL())
+	var entry models.WaitlistEntry
+	err = collection(Collection).FindOneAndUpdate(ctx,
+		bson.M{"photographer_id": photographerID, "date": day, "status": models.WaitlistWaiting},
+		bson.M{"$set": bson.M{
+			"status":           models.WaitlistOffered,
+			"offer_open":       true,
+			"offered_at":       now,
+			"offer_expires_at": expires,
+			"updated_at":       now,
+		}},
+		options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+			SetReturnDocument(options.After),
+	).Decode(&entry)
+	if errors.Is(err, mongo.ErrNoDocuments) || mongo.IsDuplicateKeyError(err) {
+		return nil // antrean kosong atau tawaran lain masih berjalan
+	}
+	if err != nil {
+		return err
+	}
+
+	events.Publish(ctx, events.WaitlistOffered, clientRecipients(ctx, entry.ClientID), map[string]interface{}{"entry": entry})
+	return nil
+}
+
+// clientRecipients adalah user pemilik profil client, kosong jika profil sudah dihapus
+func clientRecipients(ctx context.Context, clientID primitive.ObjectID) []primitive.ObjectID {
+	var client models.Client
+	err := collection("clients").FindOne(ctx, bson.M{"_id": clientID},
+		options.FindOne().SetProjection(bson.M{"user_id": 1}),
+	).Decode(&client)
+	if err != nil {
+		return nil
+	}
+	return []primitive.ObjectID{client.UserID}
+}
+
+// Hold menolak booking aktif pada tanggal yang sedang ditawarkan ke client lain
+func Hold(ctx context.Context, booking models.Booking) error {
+	if booking.Status != models.BookingStatusPending && booking.Status != models.BookingStatusConfirmed {
+		return nil
+	}
+	n, err := collection(Collection).CountDocuments(ctx, bson.M{
+		"photographer_id": booking.PhotographerID,
+		"date":            Day(booking.Date),
+		"offer_open":      true,
+		"client_id":       bson.M{"$ne": booking.ClientID},
+	}, options.Count().SetLimit(1))
+	if err != nil {
+		return utils.ErrDatabase.Wrap(err)
+	}
+	if n > 0 {
+		return utils.ErrBookingSlotHeld
+	}
+	return nil
+}
+
+// Accept menandai antrean client sebagai selesai setelah client booking tanggal tersebut,
+// baik lewat tawaran maupun karena tanggalnya kosong sebelum tawaran dikirim
+func Accept(ctx context.Context, booking models.Booking, now time.Time) error {
+	update := closed(models.WaitlistBooked, now)
+	update["$set"].(bson.M)["booking_id"] = booking.ID
+	_, err := collection(Collection).UpdateOne(ctx, bson.M{
+		"photographer_id": booking.PhotographerID,
+		"date":            Day(booking.Date),
+		"client_id":       booking.ClientID,
+		"active":          true,
+	}, update)
+	return err
+}
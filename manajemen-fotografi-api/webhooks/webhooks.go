@@ -0,0 +1,105 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"manajemen-fotografi-api/models"
+)
+
+// Header yang dikirim bersama setiap payload webhook
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Batas backoff antar percobaan kirim
+const (
+	baseBackoff = 30 * time.Second
+	maxBackoff  = 6 * time.Hour
+)
+
+// Client dipakai untuk mengirim webhook; endpoint yang lambat dianggap gagal
+var Client = &http.Client{Timeout: 10 * time.Second}
+
+// NewSecret membuat secret acak untuk tanda tangan HMAC
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Sign menghasilkan nilai header X-Webhook-Signature: "sha256=" + HMAC-SHA256 hex dari
+// "<timestamp>.<body>". Timestamp ikut ditandatangani supaya payload lama tidak bisa
+// dikirim ulang oleh pihak lain; penerima sebaiknya menolak timestamp yang terlalu lama.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify mengecek tanda tangan dari sisi penerima, dipakai juga di test
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// Backoff adalah jeda sebelum percobaan berikutnya setelah attempt kali gagal:
+// 30 detik, 1 menit, 2 menit, dan seterusnya sampai maksimal 6 jam
+func Backoff(attempt int) time.Duration {
+	d := baseBackoff
+	for i := 1; i < attempt && d < maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxBackoff)
+}
+
+// Send mengirim satu delivery ke webhook dan mengembalikan hasil percobaannya.
+// Hanya status 2xx yang dianggap berhasil.
+func Send(ctx context.Context, webhook models.Webhook, delivery models.WebhookDelivery) (models.WebhookAttempt, bool) {
+	start := time.Now()
+	attempt := models.WebhookAttempt{At: start}
+	body := []byte(delivery.Payload)
+	timestamp := start.Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt, false
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "manajemen-fotografi-webhook/1")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.ID.Hex())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, body))
+
+	resp, err := Client.Do(req)
+	attempt.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt, false
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10)) // supaya koneksi bisa dipakai ulang
+
+	attempt.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		attempt.Error = resp.Status
+		return attempt, false
+	}
+	return attempt, true
+}
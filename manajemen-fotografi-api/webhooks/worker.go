@@ -0,0 +1,193 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"manajemen-fotografi-api/events"
+	"manajemen-fotografi-api/logging"
+	"manajemen-fotografi-api/models"
+	"manajemen-fotografi-api/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Nama collection webhook dan log pengirimannya
+const (
+	Collection         = "webhooks"
+	DeliveryCollection = "webhook_deliveries"
+)
+
+// Default pengiriman, bisa diganti lewat WEBHOOK_MAX_ATTEMPTS dan WEBHOOK_POLL_INTERVAL
+const (
+	DefaultMaxAttempts  = 8
+	DefaultPollInterval = 5 * time.Second
+)
+
+// lease adalah lama delivery dikunci satu instance selama dikirim. Delivery yang kuncinya
+// kedaluwarsa (instance mati di tengah jalan) diambil lagi oleh instance lain.
+const lease = time.Minute
+
+// batchSize membatasi jumlah delivery yang dikirim dalam satu putaran
+const batchSize = 50
+
+// collection diambil saat dipakai, bukan saat package di-load,
+// supaya pengiriman bisa diuji tanpa koneksi database
+var collection = repositories.Unscoped
+
+// MaxAttempts membaca WEBHOOK_MAX_ATTEMPTS, default 8 percobaan
+func MaxAttempts() int {
+	n, err := strconv.Atoi(os.Getenv("WEBHOOK_MAX_ATTEMPTS"))
+	if err != nil || n < 1 {
+		return DefaultMaxAttempts
+	}
+	return n
+}
+
+func pollInterval() time.Duration {
+	d, err := time.ParseDuration(os.Getenv("WEBHOOK_POLL_INTERVAL"))
+	if err != nil || d <= 0 {
+		return DefaultPollInterval
+	}
+	return d
+}
+
+// Start berlangganan semua event domain untuk membuat delivery, lalu mengirim delivery
+// yang sudah jatuh tempo di background sampai ctx dibatalkan
+func Start(ctx context.Context) {
+	go events.Consume(ctx, "webhooks", Enqueue)
+
+	go func() {
+		ticker := time.NewTicker(pollInterval())
+		defer ticker.Stop()
+		for {
+			ProcessDue(ctx, time.Now())
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Enqueue membuat delivery untuk setiap webhook aktif yang berlangganan jenis event.
+// Index unik (webhook_id, event_id) mencegah delivery ganda saat event diterima beberapa instance.
+func Enqueue(ctx context.Context, event models.Event) {
+	logger := logging.FromContext(ctx)
+
+	cursor, err := collection(Collection).Find(ctx, bson.M{"active": true, "events": event.Type})
+	if err != nil {
+		logger.ErrorContext(ctx, "gagal membaca webhook", "error", err)
+		return
+	}
+	var hooks []models.Webhook
+	if err := cursor.All(ctx, &hooks); err != nil {
+		logger.ErrorContext(ctx, "gagal membaca webhook", "error", err)
+		return
+	}
+	if len(hooks) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		logger.ErrorContext(ctx, "gagal membuat payload webhook", "event_id", event.ID.Hex(), "error", err)
+		return
+	}
+
+	now := time.Now()
+	for _, hook := range hooks {
+		_, err := collection(DeliveryCollection).InsertOne(ctx, models.WebhookDelivery{
+			ID:            primitive.NewObjectID(),
+			WebhookID:     hook.ID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       string(payload),
+			Status:        models.WebhookDeliveryPending,
+			Attempts:      []models.WebhookAttempt{},
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			logger.ErrorContext(ctx, "gagal membuat delivery webhook", "webhook_id", hook.ID.Hex(), "error", err)
+		}
+	}
+}
+
+// ProcessDue mengirim delivery yang jatuh tempo pada now, maksimal satu batch.
+// Mengembalikan jumlah delivery yang diproses.
+func ProcessDue(ctx context.Context, now time.Time) int {
+	logger := logging.FromContext(ctx)
+	deliveries := collection(DeliveryCollection)
+
+	processed := 0
+	for ; processed < batchSize && ctx.Err() == nil; processed++ {
+		var delivery models.WebhookDelivery
+		err := deliveries.FindOneAndUpdate(ctx,
+			bson.M{
+				"status":          models.WebhookDeliveryPending,
+				"next_attempt_at": bson.M{"$lte": now},
+				"$or":             bson.A{bson.M{"locked_until": nil}, bson.M{"locked_until": bson.M{"$lt": now}}},
+			},
+			bson.M{"$set": bson.M{"locked_until": now.Add(lease)}},
+			options.FindOneAndUpdate().SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).SetReturnDocument(options.After),
+		).Decode(&delivery)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			logger.ErrorContext(ctx, "gagal mengambil delivery webhook", "error", err)
+			break
+		}
+
+		if err := deliver(ctx, delivery); err != nil {
+			logger.ErrorContext(ctx, "gagal menyimpan hasil delivery webhook", "delivery_id", delivery.ID.Hex(), "error", err)
+		}
+	}
+	return processed
+}
+
+// deliver mengirim satu delivery dan mencatat hasilnya. Webhook yang sudah dihapus atau
+// dinonaktifkan membuat delivery langsung masuk dead-letter.
+func deliver(ctx context.Context, delivery models.WebhookDelivery) error {
+	var hook models.Webhook
+	err := collection(Collection).FindOne(ctx, bson.M{"_id": delivery.WebhookID}).Decode(&hook)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+
+	var attempt models.WebhookAttempt
+	ok := false
+	if err == nil && hook.Active {
+		attempt, ok = Send(ctx, hook, delivery)
+	} else {
+		attempt = models.WebhookAttempt{At: time.Now(), Error: "webhook tidak aktif"}
+	}
+
+	set := bson.M{"locked_until": nil}
+	// Retry manual dari dead-letter memberi jatah percobaan baru
+	attempts := len(delivery.Attempts) - delivery.RetryFrom + 1
+	switch {
+	case ok:
+		set["status"] = models.WebhookDeliveryDelivered
+		set["delivered_at"] = attempt.At
+	case attempts >= MaxAttempts() || !hook.Active:
+		set["status"] = models.WebhookDeliveryDead
+	default:
+		set["next_attempt_at"] = attempt.At.Add(Backoff(attempts))
+	}
+
+	_, err = collection(DeliveryCollection).UpdateOne(ctx, bson.M{"_id": delivery.ID}, bson.M{
+		"$set":  set,
+		"$push": bson.M{"attempts": attempt},
+	})
+	return err
+}